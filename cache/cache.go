@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/rahulwagh/infrakit/fetcher" // CHANGE THIS to your module path
 )
@@ -80,30 +81,114 @@ func LoadResources() ([]fetcher.StandardizedResource, error) {
 // into the existing cache. It removes old resources from that project and adds the new ones,
 // while preserving resources from all other projects.
 func MergeResourcesForProject(newResources []fetcher.StandardizedResource, projectID string) error {
-	// Load existing cache
+	return MergeResources(newResources, fetcher.SyncReport{Providers: []string{"gcp"}, Scope: projectID})
+}
+
+// MergeResources merges the result of a sync into the existing cache. The cache
+// is replaced one slice at a time, where a slice is one service of one provider
+// within one project (or provider-wide). Slices that synced successfully are
+// replaced by the new resources; slices that failed or were skipped keep their
+// last-known resources, marked with a "stale_since" attribute. Providers,
+// projects and accounts outside the sync are left untouched.
+func MergeResources(newResources []fetcher.StandardizedResource, report fetcher.SyncReport) error {
 	existingResources, err := LoadResources()
-	if err != nil {
-		// If cache doesn't exist yet, just save the new resources
-		if errors.Is(err, os.ErrNotExist) {
-			return SaveResources(newResources)
-		}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		return fmt.Errorf("failed to load existing cache: %w", err)
 	}
+	return SaveResources(mergeResources(existingResources, newResources, report))
+}
+
+// sliceKey identifies the unit of the cache that a sync replaces.
+type sliceKey struct {
+	provider, scope, service string
+}
+
+func resourceSlice(resource fetcher.StandardizedResource) sliceKey {
+	scope := resource.Attributes["project_id"]
+	if scope == "" {
+		scope = resource.Attributes["account_id"]
+	}
+	return sliceKey{resource.Provider, scope, resource.Service}
+}
+
+func statusSlice(status fetcher.ServiceStatus) sliceKey {
+	// Provider-level collectors emit resources without a project or account,
+	// even when discovery was limited to a single one.
+	scope := ""
+	if status.Level == fetcher.ScopeProject.String() {
+		scope = status.Scope
+	}
+	return sliceKey{status.Provider, scope, status.Service}
+}
+
+func mergeResources(existing, fresh []fetcher.StandardizedResource, report fetcher.SyncReport) []fetcher.StandardizedResource {
+	synced := make(map[string]bool)
+	for _, provider := range report.Providers {
+		synced[provider] = true
+	}
+
+	// incomplete marks providers whose provider-wide collectors did not all
+	// succeed. Projects they would have discovered may be missing from the
+	// report, so their resources are kept rather than treated as deleted.
+	statuses := make(map[sliceKey]fetcher.SyncStatus)
+	incomplete := make(map[string]bool)
+	for _, status := range report.Services {
+		statuses[statusSlice(status)] = status.Status
+		if status.Level == fetcher.ScopeProvider.String() && status.Status != fetcher.StatusSucceeded {
+			incomplete[status.Provider] = true
+		}
+	}
+
+	type resourceKey struct {
+		slice sliceKey
+		id    string
+	}
+	fetched := make(map[resourceKey]bool)
+	for _, resource := range fresh {
+		fetched[resourceKey{resourceSlice(resource), resource.ID}] = true
+	}
+
+	staleSince := report.StartedAt.UTC().Format(time.RFC3339)
+	var merged []fetcher.StandardizedResource
+	for _, resource := range existing {
+		if !synced[resource.Provider] || (report.Scope != "" && !belongsToScope(resource, report.Scope)) {
+			merged = append(merged, resource)
+			continue
+		}
 
-	// Filter out resources belonging to the specified project
-	var filteredResources []fetcher.StandardizedResource
-	for _, resource := range existingResources {
-		// Keep the resource if it doesn't belong to the project being synced
-		if !belongsToProject(resource, projectID) {
-			filteredResources = append(filteredResources, resource)
+		slice := resourceSlice(resource)
+		status, ok := statuses[slice]
+		keep := (ok && status != fetcher.StatusSucceeded) || (!ok && incomplete[resource.Provider])
+		if !keep || fetched[resourceKey{slice, resource.ID}] {
+			continue
+		}
+		if resource.Attributes == nil {
+			resource.Attributes = make(map[string]string)
 		}
+		if resource.Attributes["stale_since"] == "" {
+			resource.Attributes["stale_since"] = staleSince
+		}
+		merged = append(merged, resource)
 	}
 
-	// Add the newly fetched resources for the project
-	filteredResources = append(filteredResources, newResources...)
+	return append(merged, fresh...)
+}
+
+// belongsToScope checks if a resource belongs to the GCP project or AWS
+// account that a targeted sync was limited to.
+func belongsToScope(resource fetcher.StandardizedResource, scope string) bool {
+	return belongsToProject(resource, scope) || belongsToAccount(resource, scope)
+}
 
-	// Save the merged cache
-	return SaveResources(filteredResources)
+// belongsToAccount checks if a resource belongs to a specific AWS account
+func belongsToAccount(resource fetcher.StandardizedResource, accountID string) bool {
+	if resource.Provider != "aws" {
+		return false
+	}
+	if resource.Service == "aws-account" && resource.ID == accountID {
+		return true
+	}
+	return resource.Attributes["account_id"] == accountID
 }
 
 // belongsToProject checks if a resource belongs to a specific GCP project
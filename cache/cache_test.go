@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/rahulwagh/infrakit/fetcher"
 )
@@ -418,3 +421,176 @@ func TestMergeResourcesForProjectNoCacheFile(t *testing.T) {
 		t.Errorf("Expected ID 'new-project', got '%s'", loadedResources[0].ID)
 	}
 }
+
+func TestSaveAndLoadSyncReport(t *testing.T) {
+	_, cleanup := setupTestCache(t)
+	defer cleanup()
+
+	if _, err := LoadSyncReport(); !os.IsNotExist(err) {
+		t.Fatalf("Expected os.ErrNotExist before the first sync, got %v", err)
+	}
+
+	report := fetcher.SyncReport{
+		Providers: []string{"gcp"},
+		Services: []fetcher.ServiceStatus{
+			{Provider: "gcp", Scope: "p1", Level: "project", Service: "vpc", Fetcher: "gcp-network", Status: fetcher.StatusSkipped, Error: "403"},
+		},
+	}
+	if err := SaveSyncReport(report); err != nil {
+		t.Fatalf("SaveSyncReport failed: %v", err)
+	}
+
+	loaded, err := LoadSyncReport()
+	if err != nil {
+		t.Fatalf("LoadSyncReport failed: %v", err)
+	}
+	if len(loaded.Services) != 1 || loaded.Services[0].Status != fetcher.StatusSkipped || loaded.Services[0].Scope != "p1" {
+		t.Errorf("Unexpected report after round trip: %+v", loaded)
+	}
+}
+
+func TestMergeResources(t *testing.T) {
+	startedAt := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	status := func(provider, scope, level, service string, st fetcher.SyncStatus) fetcher.ServiceStatus {
+		return fetcher.ServiceStatus{Provider: provider, Scope: scope, Level: level, Service: service, Status: st}
+	}
+	vpc := func(id, projectID string) fetcher.StandardizedResource {
+		return fetcher.StandardizedResource{Provider: "gcp", Service: "vpc", ID: id, Attributes: map[string]string{"project_id": projectID}}
+	}
+
+	tests := []struct {
+		name      string
+		fresh     []fetcher.StandardizedResource
+		report    fetcher.SyncReport
+		wantIDs   []string
+		wantStale []string
+	}{
+		{
+			name:  "successful services are replaced",
+			fresh: []fetcher.StandardizedResource{vpc("test-vpc-1-new", "test-project-1")},
+			report: fetcher.SyncReport{Providers: []string{"gcp"}, Services: []fetcher.ServiceStatus{
+				status("gcp", "", "provider", "project", fetcher.StatusSucceeded),
+				status("gcp", "test-project-1", "project", "vpc", fetcher.StatusSucceeded),
+				status("gcp", "test-project-1", "project", "cloud-run", fetcher.StatusSucceeded),
+			}},
+			// test-project-2 was not discovered, so it and its VPC are gone.
+			wantIDs: []string{"i-1234567890", "test-vpc-1-new"},
+		},
+		{
+			name:  "failed services keep their last-known data",
+			fresh: []fetcher.StandardizedResource{vpc("test-vpc-1-new", "test-project-1")},
+			report: fetcher.SyncReport{Providers: []string{"gcp"}, Services: []fetcher.ServiceStatus{
+				status("gcp", "", "provider", "project", fetcher.StatusSucceeded),
+				status("gcp", "test-project-1", "project", "vpc", fetcher.StatusSucceeded),
+				status("gcp", "test-project-1", "project", "cloud-run", fetcher.StatusFailed),
+			}},
+			wantIDs:   []string{"i-1234567890", "test-service-1", "test-vpc-1-new"},
+			wantStale: []string{"test-service-1"},
+		},
+		{
+			name:  "failed discovery keeps every project",
+			fresh: nil,
+			report: fetcher.SyncReport{Providers: []string{"gcp"}, Services: []fetcher.ServiceStatus{
+				status("gcp", "", "provider", "project", fetcher.StatusSkipped),
+				status("gcp", "", "provider", "folder", fetcher.StatusSkipped),
+			}},
+			wantIDs:   []string{"i-1234567890", "test-project-1", "test-project-2", "test-service-1", "test-vpc-1", "test-vpc-2"},
+			wantStale: []string{"test-project-1", "test-project-2", "test-service-1", "test-vpc-1", "test-vpc-2"},
+		},
+		{
+			name:  "refetched resources in a failed service are not stale",
+			fresh: []fetcher.StandardizedResource{vpc("test-vpc-1", "test-project-1")},
+			report: fetcher.SyncReport{Providers: []string{"gcp"}, Scope: "test-project-1", Services: []fetcher.ServiceStatus{
+				status("gcp", "test-project-1", "provider", "project", fetcher.StatusSucceeded),
+				status("gcp", "test-project-1", "project", "vpc", fetcher.StatusFailed),
+				status("gcp", "test-project-1", "project", "cloud-run", fetcher.StatusSucceeded),
+			}},
+			// test-project-1 itself was not returned by this run and is dropped.
+			wantIDs: []string{"i-1234567890", "test-project-2", "test-vpc-1", "test-vpc-2"},
+		},
+		{
+			name:  "other providers are untouched",
+			fresh: nil,
+			report: fetcher.SyncReport{Providers: []string{"aws"}, Services: []fetcher.ServiceStatus{
+				status("aws", "", "provider", "ec2", fetcher.StatusFailed),
+			}},
+			wantIDs:   []string{"i-1234567890", "test-project-1", "test-project-2", "test-service-1", "test-vpc-1", "test-vpc-2"},
+			wantStale: []string{"i-1234567890"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.report.StartedAt = startedAt
+			merged := mergeResources(createTestResources(), tt.fresh, tt.report)
+
+			var ids, stale []string
+			for _, res := range merged {
+				ids = append(ids, res.ID)
+				if since := res.Attributes["stale_since"]; since != "" {
+					if since != "2025-10-01T12:00:00Z" {
+						t.Errorf("Resource %s: unexpected stale_since %q", res.ID, since)
+					}
+					stale = append(stale, res.ID)
+				}
+			}
+			sort.Strings(ids)
+			sort.Strings(stale)
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("Expected resources %v, got %v", tt.wantIDs, ids)
+			}
+			if !reflect.DeepEqual(stale, tt.wantStale) {
+				t.Errorf("Expected stale resources %v, got %v", tt.wantStale, stale)
+			}
+		})
+	}
+}
+
+func TestMergeResourcesKeepsStaleSince(t *testing.T) {
+	existing := []fetcher.StandardizedResource{
+		{Provider: "aws", Service: "iam", ID: "role-1", Attributes: map[string]string{"stale_since": "2025-09-01T00:00:00Z"}},
+	}
+	report := fetcher.SyncReport{
+		StartedAt: time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC),
+		Providers: []string{"aws"},
+		Services:  []fetcher.ServiceStatus{{Provider: "aws", Level: "provider", Service: "iam", Status: fetcher.StatusFailed}},
+	}
+
+	merged := mergeResources(existing, nil, report)
+	if len(merged) != 1 || merged[0].Attributes["stale_since"] != "2025-09-01T00:00:00Z" {
+		t.Errorf("Expected the original stale_since to be kept, got %+v", merged)
+	}
+}
+
+func TestMergeResourcesForAccount(t *testing.T) {
+	account := func(id string) fetcher.StandardizedResource {
+		return fetcher.StandardizedResource{Provider: "aws", Service: "aws-account", ID: id, Attributes: map[string]string{}}
+	}
+	instance := func(id, accountID string) fetcher.StandardizedResource {
+		return fetcher.StandardizedResource{Provider: "aws", Service: "ec2", ID: id, Attributes: map[string]string{"account_id": accountID}}
+	}
+	existing := []fetcher.StandardizedResource{
+		account("111111111111"), instance("i-old", "111111111111"),
+		account("222222222222"), instance("i-other", "222222222222"),
+	}
+	fresh := []fetcher.StandardizedResource{account("111111111111"), instance("i-new", "111111111111")}
+	report := fetcher.SyncReport{
+		StartedAt: time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC),
+		Providers: []string{"aws"},
+		Scope:     "111111111111",
+		Services: []fetcher.ServiceStatus{
+			{Provider: "aws", Scope: "111111111111", Level: "provider", Service: "aws-account", Status: fetcher.StatusSucceeded},
+			{Provider: "aws", Scope: "111111111111", Level: "project", Service: "ec2", Status: fetcher.StatusSucceeded},
+		},
+	}
+
+	var keys []string
+	for _, res := range mergeResources(existing, fresh, report) {
+		keys = append(keys, res.Service+"/"+res.ID)
+	}
+	sort.Strings(keys)
+	want := []string{"aws-account/111111111111", "aws-account/222222222222", "ec2/i-new", "ec2/i-other"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("Expected resources %v, got %v", want, keys)
+	}
+}
@@ -0,0 +1,53 @@
+// cache/report.go
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rahulwagh/infrakit/fetcher"
+)
+
+const syncReportFile = "last_sync.json"
+
+// SaveSyncReport writes the outcome of the latest sync next to the cache file.
+func SaveSyncReport(report fetcher.SyncReport) error {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return fmt.Errorf("failed to get cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync report to JSON: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(cacheDir, syncReportFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write sync report: %w", err)
+	}
+	return nil
+}
+
+// LoadSyncReport reads the report written by the latest sync.
+func LoadSyncReport() (fetcher.SyncReport, error) {
+	var report fetcher.SyncReport
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return report, fmt.Errorf("failed to get cache directory: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(cacheDir, syncReportFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return report, os.ErrNotExist
+		}
+		return report, fmt.Errorf("failed to read sync report: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &report); err != nil {
+		return report, fmt.Errorf("failed to unmarshal sync report: %w", err)
+	}
+	return report, nil
+}
@@ -0,0 +1,61 @@
+// cmd/certificates.go
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/rahulwagh/infrakit/cache"
+	"github.com/rahulwagh/infrakit/fetcher"
+	"github.com/spf13/cobra"
+)
+
+var expiringWithinDays int
+
+var certificatesCmd = &cobra.Command{
+	Use:   "certificates",
+	Short: "List cached ACM certificates that expire soon and what uses them.",
+	Run: func(cmd *cobra.Command, args []string) {
+		resources, err := cache.LoadResources()
+		if err != nil {
+			log.Fatalf("Error loading cache: %v", err)
+		}
+
+		expiring := fetcher.ExpiringCertificates(resources, time.Duration(expiringWithinDays)*24*time.Hour)
+		if len(expiring) == 0 {
+			fmt.Printf("No cached certificates expire within %d days\n", expiringWithinDays)
+			return
+		}
+		for _, cert := range expiring {
+			when := fmt.Sprintf("in %d days", cert.DaysLeft)
+			if cert.DaysLeft < 0 {
+				when = fmt.Sprintf("%d days ago", -cert.DaysLeft)
+			}
+			line := fmt.Sprintf("%s  expires %s (%s)  [%s", cert.Certificate.Name, cert.Expires.Format("2006-01-02"), when, cert.Certificate.Region)
+			if account := accountLabel(cert.Certificate); account != "" {
+				line += " in " + account
+			}
+			fmt.Println(line + "]")
+
+			attached := make(map[string]bool)
+			for _, res := range cert.AttachedTo {
+				attached[res.ID] = true
+				fmt.Printf("  used by %s %s\n", res.Service, res.Name)
+			}
+			for _, arn := range cert.InUseBy {
+				if !attached[arn] {
+					fmt.Printf("  used by %s\n", arn)
+				}
+			}
+			if len(cert.InUseBy) == 0 {
+				fmt.Println("  not in use")
+			}
+		}
+	},
+}
+
+func init() {
+	certificatesCmd.Flags().IntVar(&expiringWithinDays, "within", 30, "List certificates expiring within this many days")
+	rootCmd.AddCommand(certificatesCmd)
+}
@@ -0,0 +1,57 @@
+// cmd/resolve.go
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/rahulwagh/infrakit/cache"
+	"github.com/rahulwagh/infrakit/fetcher"
+	"github.com/spf13/cobra"
+)
+
+var resolveCmd = &cobra.Command{
+	Use:   "resolve <hostname>",
+	Short: "Show what a hostname points at, using the DNS records in the local cache.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		resources, err := cache.LoadResources()
+		if err != nil {
+			log.Fatalf("Error loading cache: %v", err)
+		}
+
+		links := fetcher.ResolveDNSName(resources, args[0])
+		if len(links) == 0 {
+			fmt.Printf("No cached DNS records found for %s\n", args[0])
+			return
+		}
+		for _, link := range links {
+			line := fmt.Sprintf("%s%s %s -> %s", strings.Repeat("  ", link.Depth), link.Record.Name, link.Record.Attributes["type"], link.Value)
+			if t := link.Target; t != nil {
+				line += "  " + resolveTargetLabel(*t)
+			}
+			fmt.Println(line)
+			// A value held by several resources, such as a reused private IP.
+			for _, candidate := range link.Candidates {
+				fmt.Printf("%s  candidate %s\n", strings.Repeat("  ", link.Depth), resolveTargetLabel(candidate))
+			}
+		}
+	},
+}
+
+// resolveTargetLabel describes a resource a DNS record points at, e.g.
+// "[aws ec2 web-1 in acme-prod (111122223333)]".
+func resolveTargetLabel(t fetcher.StandardizedResource) string {
+	label := fmt.Sprintf("[%s %s %s", t.Provider, t.Service, t.Name)
+	if project := t.Attributes["project_id"]; project != "" {
+		label += " in " + project
+	} else if account := accountLabel(t); account != "" {
+		label += " in " + account
+	}
+	return label + "]"
+}
+
+func init() {
+	rootCmd.AddCommand(resolveCmd)
+}
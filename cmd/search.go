@@ -4,11 +4,13 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 
 	"github.com/ktr0731/go-fuzzyfinder"
 	"github.com/spf13/cobra"
 	"github.com/rahulwagh/infrakit/cache"
-	//"github.com/rahulwagh/infrakit/fetcher"
+	"github.com/rahulwagh/infrakit/fetcher"
 )
 
 var searchCmd = &cobra.Command{
@@ -26,6 +28,9 @@ var searchCmd = &cobra.Command{
 			resources,
 			func(i int) string {
 				// This is the string that the finder will search against
+				if account := accountLabel(resources[i]); account != "" {
+					return fmt.Sprintf("%s :: %s :: %s", resources[i].Name, resources[i].ID, account)
+				}
 				return fmt.Sprintf("%s :: %s", resources[i].Name, resources[i].ID)
 			},
 			fuzzyfinder.WithPreviewWindow(func(i, w, h int) string {
@@ -34,8 +39,15 @@ var searchCmd = &cobra.Command{
 					return ""
 				}
 				r := resources[i]
-				return fmt.Sprintf("Name: %s\nID: %s\nService: %s\nRegion: %s\nProvider: %s",
+				preview := fmt.Sprintf("Name: %s\nID: %s\nService: %s\nRegion: %s\nProvider: %s",
 					r.Name, r.ID, r.Service, r.Region, r.Provider)
+				if account := accountLabel(r); account != "" {
+					preview += "\nAccount: " + account
+				}
+				if details := attributeLines(r); details != "" {
+					preview += "\n\n" + details
+				}
+				return preview
 			}),
 		)
 
@@ -53,6 +65,33 @@ var searchCmd = &cobra.Command{
 	},
 }
 
+// accountLabel names the AWS account a resource belongs to, e.g.
+// "acme-prod (111122223333)", or "" for resources without one.
+func accountLabel(r fetcher.StandardizedResource) string {
+	id := r.Attributes["account_id"]
+	if id == "" {
+		return ""
+	}
+	if alias := r.Attributes["account_alias"]; alias != "" {
+		return fmt.Sprintf("%s (%s)", alias, id)
+	}
+	return id
+}
+
+// attributeLines lists a resource's attributes as sorted "key: value" lines,
+// leaving out the account ones that accountLabel already shows.
+func attributeLines(r fetcher.StandardizedResource) string {
+	var lines []string
+	for key, value := range r.Attributes {
+		if key == "account_id" || key == "account_alias" {
+			continue
+		}
+		lines = append(lines, key+": "+value)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
 func init() {
 	rootCmd.AddCommand(searchCmd)
-}
\ No newline at end of file
+}
@@ -2,124 +2,218 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/rahulwagh/infrakit/cache"
+	"github.com/rahulwagh/infrakit/config"
 	"github.com/rahulwagh/infrakit/fetcher"
+	"github.com/rahulwagh/infrakit/syncer"
 	"github.com/spf13/cobra"
 )
 
+var (
+	listFetchers      bool
+	syncConcurrency   int
+	syncTimeout       time.Duration
+	perServiceTimeout time.Duration
+	failOn            string
+	configPath        string
+	awsProfiles       []string
+)
+
 var syncCmd = &cobra.Command{
-	Use:   "sync [provider] [project-id]",
+	Use:   "sync [provider] [project-id|account-id]",
 	Short: "Fetch resources from cloud providers and update the local cache.",
 	Long: `Sync resources from cloud providers. Examples:
   infrakit sync              - Sync all providers (AWS, GCP)
   infrakit sync aws          - Sync only AWS resources
   infrakit sync gcp          - Sync all GCP projects
-  infrakit sync gcp my-proj  - Sync only the specified GCP project`,
-
-    Run: func(cmd *cobra.Command, args []string) {
-    	log.Println("Starting resource sync...")
-
-    	// Parse arguments
-    	providerToSync := ""
-    	projectID := ""
-    	if len(args) > 0 {
-    		providerToSync = args[0]
-    	}
-    	if len(args) > 1 {
-    		projectID = args[1]
-    	}
-
-    	// --- Handle GCP project-specific sync ---
-    	if providerToSync == "gcp" && projectID != "" {
-    		log.Printf("--- Syncing specific GCP project: %s ---", projectID)
-
-    		// Fetch resources for the specific project
-    		gcpResources, err := fetcher.FetchGCPSingleProject(projectID)
-    		if err != nil {
-    			log.Fatalf("Error fetching resources for project %s: %v", projectID, err)
-    		}
-
-    		log.Printf("Found %d resources for project %s", len(gcpResources), projectID)
-
-    		// Merge with existing cache (intelligent merge)
-    		if err := cache.MergeResourcesForProject(gcpResources, projectID); err != nil {
-    			log.Fatalf("Error merging cache for project %s: %v", projectID, err)
-    		}
-
-    		log.Printf("Successfully synced project %s and merged with cache!\n", projectID)
-    		return
-    	}
-
-    	// --- Handle full provider sync (existing behavior) ---
-    	var allResources []fetcher.StandardizedResource
-
-    	// --- Sync AWS Resources ---
-    	// This block runs if no provider is specified (sync all) OR if the provider is "aws".
-    	if providerToSync == "" || providerToSync == "aws" {
-    		log.Println("--- Syncing AWS Resources ---")
-    		var awsResources []fetcher.StandardizedResource
-
-    		ec2Resources, err := fetcher.FetchEC2Instances()
-    		if err != nil {
-    			log.Fatalf("Error fetching EC2 instances: %v", err)
-    		}
-    		awsResources = append(awsResources, ec2Resources...)
-
-    		iamResources, err := fetcher.FetchIAMRoles()
-    		if err != nil {
-    			log.Fatalf("Error fetching IAM roles: %v", err)
-    		}
-    		awsResources = append(awsResources, iamResources...)
-
-    		allResources = append(allResources, awsResources...)
-    		log.Printf("Found %d AWS resources.", len(awsResources))
-    	}
-
-    	// --- Sync GCP Resources ---
-    	// This block runs if no provider is specified (sync all) OR if the provider is "gcp".
-    	if providerToSync == "" || providerToSync == "gcp" {
-    		log.Println("--- Syncing GCP Resources ---")
-
-    		gcpOrganizationID, err := fetcher.DiscoverGCPOrganization()
-    		if err != nil {
-    			log.Printf("Warning: Could not discover GCP organization: %v", err)
-    		}
-
-    		var gcpResources []fetcher.StandardizedResource
-    		if gcpOrganizationID != "" {
-    			gcpResources, err = fetcher.FetchGCPResourcesFromOrg(gcpOrganizationID)
-    		} else {
-    			gcpResources, err = fetcher.FetchGCPProjectsNoOrg()
-    		}
-
-    		if err != nil {
-    			log.Fatalf("Error fetching GCP resources: %v", err)
-    		}
-
-    		allResources = append(allResources, gcpResources...)
-    		log.Printf("Found %d GCP resources.", len(gcpResources))
-    	}
-
-    	// --- Input Validation ---
-    	// If a provider was specified but it wasn't "aws" or "gcp", it's invalid.
-    	if providerToSync != "" && providerToSync != "aws" && providerToSync != "gcp" {
-    		log.Fatalf("Error: Invalid provider '%s'. Valid providers are 'aws' or 'gcp', or no provider to sync all.", providerToSync)
-    	}
-
-    	// --- Save combined results (full replacement for full provider sync) ---
-    	if len(allResources) > 0 {
-    		if err := cache.SaveResources(allResources); err != nil {
-    			log.Fatalf("Error saving cache: %v", err)
-    		}
-    		log.Printf("Sync completed successfully! Found %d total resources.\n", len(allResources))
-    	} else {
-    		log.Println("Sync finished. No new resources found.")
-    	}
-    },
+  infrakit sync gcp my-proj  - Sync only the specified GCP project
+  infrakit sync aws 123456789012
+                             - Sync only the specified AWS account
+  infrakit sync aws --profile prod --profile staging
+                             - Sync the AWS accounts of these named profiles
+  infrakit sync --list       - List the available collectors`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		if listFetchers {
+			printFetchers()
+			return
+		}
+
+		log.Println("Starting resource sync...")
+		if syncConcurrency < 1 {
+			log.Fatalf("Error: --concurrency must be at least 1, got %d", syncConcurrency)
+		}
+		policy, err := syncer.ParseFailurePolicy(failOn)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+
+		// Ctrl-C or SIGTERM cancels in-flight API calls instead of killing the process mid-write.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if syncTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, syncTimeout)
+			defer cancel()
+		}
+
+		if configPath == "" {
+			if configPath, err = config.DefaultPath(); err != nil {
+				log.Fatalf("Error: could not locate config file: %v", err)
+			}
+		}
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+
+		engine := &syncer.Engine{
+			Fetchers:       fetcher.Fetchers(),
+			Concurrency:    syncConcurrency,
+			ServiceTimeout: perServiceTimeout,
+			Throttle:       cfg.Throttle(),
+		}
+		scope := fetcher.Scope{AWSRegions: cfg.AWS.Regions, AWSAccounts: cfg.AWS.Accounts, AWSOrganization: cfg.AWS.Organization}
+		if len(awsProfiles) > 0 {
+			// Profiles on the command line replace the configured accounts.
+			scope.AWSAccounts = nil
+			for _, profile := range awsProfiles {
+				scope.AWSAccounts = append(scope.AWSAccounts, fetcher.AWSAccount{Profile: profile})
+			}
+		}
+
+		// Parse arguments
+		providerToSync := ""
+		target := ""
+		if len(args) > 0 {
+			providerToSync = args[0]
+			if err := fetcher.ValidateProvider(providerToSync); err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+		}
+		if len(args) > 1 {
+			target = args[1]
+		}
+
+		// --- Handle GCP project- or AWS account-specific sync ---
+		if target != "" {
+			var targetScope fetcher.Scope
+			var what string
+			switch providerToSync {
+			case "gcp":
+				targetScope, what = scope.WithProject(target), "project"
+			case "aws":
+				targetScope, what = scope.WithAccount(fetcher.AWSAccount{ID: target}), "account"
+			default:
+				log.Fatalf("Error: provider %s does not support syncing a single project or account", providerToSync)
+			}
+			log.Printf("--- Syncing specific %s %s: %s ---", strings.ToUpper(providerToSync), what, target)
+
+			result := engine.Run(ctx, []string{providerToSync}, targetScope)
+			abortIfInterrupted(ctx, result.Report)
+			if len(result.ProviderErrors) > 0 {
+				finishSync(result.Report, policy)
+				log.Fatalf("Error fetching resources for %s %s: %v", what, target, result.ProviderErrors[0].Err)
+			}
+			targetResources := result.Resources
+
+			log.Printf("Found %d resources for %s %s", len(targetResources), what, target)
+
+			// Merge with existing cache; services that failed keep their last-known data
+			if err := cache.MergeResources(targetResources, result.Report); err != nil {
+				log.Fatalf("Error merging cache for %s %s: %v", what, target, err)
+			}
+
+			log.Printf("Successfully synced %s %s and merged with cache!\n", what, target)
+			finishSync(result.Report, policy)
+			return
+		}
+
+		// --- Handle full provider sync ---
+		providers := fetcher.Providers()
+		if providerToSync != "" {
+			providers = []string{providerToSync}
+		}
+
+		log.Printf("--- Syncing %s Resources ---", strings.ToUpper(strings.Join(providers, ", ")))
+		result := engine.Run(ctx, providers, scope)
+		abortIfInterrupted(ctx, result.Report)
+		allResources := result.Resources
+		for _, provider := range providers {
+			count := 0
+			for _, res := range allResources {
+				if res.Provider == provider {
+					count++
+				}
+			}
+			log.Printf("Found %d %s resources.", count, strings.ToUpper(provider))
+		}
+
+		// --- Merge results; services that failed keep their last-known data ---
+		if err := cache.MergeResources(allResources, result.Report); err != nil {
+			log.Fatalf("Error saving cache: %v", err)
+		}
+		if len(allResources) > 0 {
+			log.Printf("Sync completed successfully! Found %d total resources.\n", len(allResources))
+		} else {
+			log.Println("Sync finished. No new resources found.")
+		}
+		finishSync(result.Report, policy)
+	},
+}
+
+// abortIfInterrupted stops before the cache is touched when the sync was
+// cancelled or ran past --timeout. The report is still written.
+func abortIfInterrupted(ctx context.Context, report fetcher.SyncReport) {
+	if ctx.Err() == nil {
+		return
+	}
+	finishSync(report, syncer.FailNever)
+	log.Fatalf("Sync aborted (%v); the cache was left unchanged.", ctx.Err())
+}
+
+// finishSync writes ~/.infrakit/last_sync.json, prints the summary table and
+// exits non-zero if the report violates the failure policy.
+func finishSync(report fetcher.SyncReport, policy syncer.FailurePolicy) {
+	if err := cache.SaveSyncReport(report); err != nil {
+		log.Printf("Warning: could not save sync report: %v", err)
+	}
+	fmt.Println()
+	syncer.WriteSummary(os.Stdout, report)
+	if policy.Violated(report) {
+		log.Printf("Sync finished with %d failed and %d skipped service(s) (--fail-on=%s).",
+			report.Count(fetcher.StatusFailed), report.Count(fetcher.StatusSkipped), policy)
+		os.Exit(1)
+	}
+}
+
+// printFetchers writes the registered collectors as a table to stdout.
+func printFetchers() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPROVIDER\tSCOPE\tSERVICES")
+	for _, f := range fetcher.Fetchers() {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.Name(), f.Provider(), fetcher.ScopeKindOf(f), strings.Join(f.Services(), ", "))
+	}
+	w.Flush()
 }
 
 func init() {
 	rootCmd.AddCommand(syncCmd)
-}
\ No newline at end of file
+	syncCmd.Flags().BoolVar(&listFetchers, "list", false, "List the available collectors and exit")
+	syncCmd.Flags().IntVar(&syncConcurrency, "concurrency", syncer.DefaultConcurrency, "Maximum number of collectors to run in parallel")
+	syncCmd.Flags().DurationVar(&syncTimeout, "timeout", 0, "Abort the whole sync after this long (0 for no limit)")
+	syncCmd.Flags().StringVar(&failOn, "fail-on", string(syncer.FailProvider), "When to exit non-zero: never, provider (a provider-wide collector failed), failed (any service failed), any (any service failed or was skipped)")
+	syncCmd.Flags().StringVar(&configPath, "config", "", "Path to the config file (default ~/.infrakit/config.yaml)")
+	syncCmd.Flags().StringArrayVar(&awsProfiles, "profile", nil, "AWS named profile to sync (repeatable); replaces the accounts in the config file")
+	syncCmd.Flags().DurationVar(&perServiceTimeout, "per-service-timeout", 10*time.Minute, "Abandon a single collector after this long (0 for no limit)")
+}
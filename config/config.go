@@ -0,0 +1,110 @@
+// config/config.go
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rahulwagh/infrakit/fetcher"
+	"gopkg.in/yaml.v3"
+)
+
+const configFile = "config.yaml"
+
+// Config is the optional ~/.infrakit/config.yaml file.
+//
+//	retry:
+//	  max_attempts: 5
+//	  base_delay: 500ms
+//	  max_delay: 30s
+//	rate_limits:
+//	  gcp-compute: {requests_per_second: 20, burst: 20}
+//	  aws-iam: {requests_per_second: 5}
+//	aws:
+//	  regions: [us-east-1, eu-west-1]
+//	  accounts:
+//	    - profile: prod
+//	    - id: "444455556666"
+//	      alias: staging
+//	      role_arn: arn:aws:iam::444455556666:role/InfrakitReadOnly
+//	  organization:
+//	    discover: true
+//	    role_name: OrganizationAccountAccessRole
+type Config struct {
+	Retry      fetcher.RetryPolicy          `yaml:"retry"`
+	RateLimits map[string]fetcher.RateLimit `yaml:"rate_limits"`
+	AWS        AWSConfig                    `yaml:"aws"`
+}
+
+// AWSConfig holds the AWS-specific settings.
+type AWSConfig struct {
+	// Regions is an allow-list of regions to collect. When empty, every
+	// region enabled for the account is collected.
+	Regions []string `yaml:"regions"`
+	// Accounts lists the accounts to sync, each reached through a named
+	// profile, a role assumed via STS, or both. When empty, the default
+	// credentials are used.
+	Accounts []fetcher.AWSAccount `yaml:"accounts"`
+	// Organization turns on AWS Organizations discovery and, with a role
+	// name, collection of every member account.
+	Organization fetcher.AWSOrganization `yaml:"organization"`
+}
+
+// DefaultPath returns the location of the config file.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".infrakit", configFile), nil
+}
+
+// Load reads the config file at path. A missing file yields an empty Config.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Throttle builds the API throttle described by the config.
+func (c *Config) Throttle() *fetcher.Throttle {
+	return fetcher.NewThrottle(c.EffectiveRetryPolicy(), c.EffectiveRateLimits())
+}
+
+// EffectiveRetryPolicy returns the configured retry policy, with settings
+// that are not given taken from fetcher.DefaultRetryPolicy.
+func (c *Config) EffectiveRetryPolicy() fetcher.RetryPolicy {
+	policy := fetcher.DefaultRetryPolicy()
+	if c.Retry.MaxAttempts > 0 {
+		policy.MaxAttempts = c.Retry.MaxAttempts
+	}
+	if c.Retry.BaseDelay > 0 {
+		policy.BaseDelay = c.Retry.BaseDelay
+	}
+	if c.Retry.MaxDelay > 0 {
+		policy.MaxDelay = c.Retry.MaxDelay
+	}
+	return policy
+}
+
+// EffectiveRateLimits returns fetcher.DefaultRateLimits overridden by the
+// configured limits. A limit of 0 requests per second disables the default.
+func (c *Config) EffectiveRateLimits() map[string]fetcher.RateLimit {
+	limits := fetcher.DefaultRateLimits()
+	for api, limit := range c.RateLimits {
+		limits[api] = limit
+	}
+	return limits
+}
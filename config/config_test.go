@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rahulwagh/infrakit/fetcher"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("Load returned error for a missing file: %v", err)
+	}
+	if cfg.EffectiveRetryPolicy().MaxAttempts != 5 {
+		t.Errorf("Expected default retry policy, got %+v", cfg.EffectiveRetryPolicy())
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+retry:
+  max_attempts: 8
+  max_delay: 1m
+rate_limits:
+  gcp-compute: {requests_per_second: 50, burst: 100}
+  aws-iam: {requests_per_second: 0}
+  gcp-dns: {requests_per_second: 2.5}
+aws:
+  regions: [us-east-1, eu-west-1]
+  accounts:
+    - profile: prod
+    - id: "444455556666"
+      alias: staging
+      role_arn: arn:aws:iam::444455556666:role/InfrakitReadOnly
+      external_id: infrakit-ext
+  organization:
+    discover: true
+    profile: management
+    role_name: OrganizationAccountAccessRole
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(cfg.AWS.Regions) != 2 || cfg.AWS.Regions[1] != "eu-west-1" {
+		t.Errorf("Unexpected AWS regions: %v", cfg.AWS.Regions)
+	}
+	wantAccounts := []fetcher.AWSAccount{
+		{Profile: "prod"},
+		{ID: "444455556666", Alias: "staging", RoleARN: "arn:aws:iam::444455556666:role/InfrakitReadOnly", ExternalID: "infrakit-ext"},
+	}
+	if !reflect.DeepEqual(cfg.AWS.Accounts, wantAccounts) {
+		t.Errorf("Unexpected AWS accounts: %+v", cfg.AWS.Accounts)
+	}
+	wantOrg := fetcher.AWSOrganization{Discover: true, Profile: "management", RoleName: "OrganizationAccountAccessRole"}
+	if cfg.AWS.Organization != wantOrg {
+		t.Errorf("Unexpected AWS organization: %+v", cfg.AWS.Organization)
+	}
+
+	policy := cfg.EffectiveRetryPolicy()
+	if policy.MaxAttempts != 8 || policy.MaxDelay != time.Minute || policy.BaseDelay != 500*time.Millisecond {
+		t.Errorf("Unexpected retry policy: %+v", policy)
+	}
+
+	limits := cfg.EffectiveRateLimits()
+	tests := []struct {
+		api   string
+		rps   float64
+		burst int
+	}{
+		{"gcp-compute", 50, 100},
+		{"aws-iam", 0, 0},
+		{"gcp-dns", 2.5, 0},
+		{"aws-ec2", 20, 20}, // default kept
+	}
+	for _, tt := range tests {
+		t.Run(tt.api, func(t *testing.T) {
+			limit := limits[tt.api]
+			if limit.RequestsPerSecond != tt.rps || limit.Burst != tt.burst {
+				t.Errorf("Expected %v/%d, got %+v", tt.rps, tt.burst, limit)
+			}
+		})
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := writeConfig(t, "retry: [not, a, map]\n")
+	if _, err := Load(path); err == nil {
+		t.Error("Expected an error for an invalid config file")
+	}
+}
@@ -0,0 +1,381 @@
+// fetcher/aws_accounts.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// maxAccountConcurrency caps how many AWS accounts are resolved at once.
+const maxAccountConcurrency = 8
+
+// roleSessionName identifies infrakit in the CloudTrail logs of assumed roles.
+const roleSessionName = "infrakit"
+
+func init() {
+	Register(NewFetcher("aws-accounts", "aws", ScopeProvider, []string{"aws-account", "aws-ou"}, FetchAWSAccounts))
+}
+
+// AWSAccount is an AWS account to sync and the credentials to reach it: a
+// named profile, a role to assume, or both (the profile supplies the source
+// credentials for the role). The zero value means the default credentials.
+type AWSAccount struct {
+	// ID is the 12-digit account ID. For configured accounts it is optional
+	// and, when given, checked against the account the credentials reach.
+	ID         string `yaml:"id"`
+	Alias      string `yaml:"alias"`
+	Profile    string `yaml:"profile"`
+	RoleARN    string `yaml:"role_arn"`
+	ExternalID string `yaml:"external_id"`
+}
+
+// AWSAccountFromResource rebuilds the account described by an "aws-account"
+// resource. The external ID is not cached and must be filled in by the caller.
+func AWSAccountFromResource(res StandardizedResource) AWSAccount {
+	return AWSAccount{
+		ID:      res.ID,
+		Alias:   res.Attributes["account_alias"],
+		Profile: res.Attributes["profile"],
+		RoleARN: res.Attributes["role_arn"],
+	}
+}
+
+// FetchAWSAccounts resolves the accounts of scope.AWSAccounts (or of the
+// default credentials) to "aws-account" resources, which the sync engine then
+// fans out to the account-scoped AWS fetchers. When organization discovery is
+// on, the organization's OUs and accounts are listed too, and with a role name
+// every active member account is collected through that role. When
+// scope.Account.ID is set, only that account is returned.
+func FetchAWSAccounts(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+	want := scope.Account.ID
+	org := scope.AWSOrganization
+	var errs, accountErrs []error
+
+	var orgResources []StandardizedResource
+	orgAccounts := make(map[string]StandardizedResource)
+	if org.Discover {
+		cfg, err := awsConfigFor(ctx, scope.WithAccount(AWSAccount{Profile: org.Profile}))
+		if err == nil {
+			orgResources, err = FetchAWSOrganization(ctx, cfg)
+		}
+		if err != nil {
+			errs = append(errs, &ServiceError{Service: "aws-ou", Err: err})
+		}
+		for _, res := range orgResources {
+			if res.Service == "aws-account" {
+				orgAccounts[res.ID] = res
+			}
+		}
+	}
+
+	var resources []StandardizedResource
+	seen := make(map[string]bool)
+	add := func(account AWSAccount) {
+		if seen[account.ID] || (want != "" && account.ID != want) {
+			return
+		}
+		seen[account.ID] = true
+		res := awsAccountResource(account)
+		if member, ok := orgAccounts[account.ID]; ok {
+			if account.Alias == "" {
+				res.Name = member.Name
+			}
+			for k, v := range member.Attributes {
+				if _, ok := res.Attributes[k]; !ok {
+					res.Attributes[k] = v
+				}
+			}
+		}
+		resources = append(resources, res)
+	}
+
+	accounts := scope.AWSAccounts
+	if len(accounts) == 0 {
+		accounts = []AWSAccount{{}}
+	}
+	resolved, errs1 := resolveAWSAccounts(ctx, scope, accountsFor(accounts, want))
+	for _, account := range resolved {
+		add(account)
+	}
+	accountErrs = append(accountErrs, errs1...)
+
+	if org.RoleName != "" {
+		var members []AWSAccount
+		for _, res := range orgResources {
+			if res.Service != "aws-account" || seen[res.ID] || res.Attributes["state"] != "active" || (want != "" && res.ID != want) {
+				continue
+			}
+			members = append(members, AWSAccount{ID: res.ID, Profile: org.Profile, RoleARN: organizationRoleARN(res, org.RoleName), ExternalID: org.ExternalID})
+		}
+		resolved, errs2 := resolveAWSAccounts(ctx, scope, members)
+		for _, account := range resolved {
+			add(account)
+		}
+		accountErrs = append(accountErrs, errs2...)
+	}
+
+	// Organization accounts that no credentials reach are listed, but the
+	// account-scoped fetchers are not run against them.
+	for _, res := range orgResources {
+		if seen[res.ID] || (want != "" && (res.Service != "aws-account" || res.ID != want)) {
+			continue
+		}
+		if res.Service == "aws-account" {
+			res.Attributes["collect"] = "false"
+		}
+		resources = append(resources, res)
+	}
+
+	if len(accountErrs) > 0 {
+		errs = append(errs, &ServiceError{Service: "aws-account", Err: errors.Join(accountErrs...)})
+	}
+	err := errors.Join(errs...)
+	if want != "" && len(resources) == 0 && err == nil {
+		err = fmt.Errorf("account %s is not reachable with the configured profiles and roles", want)
+	}
+	return resources, err
+}
+
+// accountsFor narrows accounts to the one with ID want, or, when none is
+// configured with that ID, to those whose ID is only known once resolved.
+func accountsFor(accounts []AWSAccount, want string) []AWSAccount {
+	if want == "" {
+		return accounts
+	}
+	var unresolved []AWSAccount
+	for _, account := range accounts {
+		if account.ID == want {
+			return []AWSAccount{account}
+		}
+		if account.ID == "" {
+			unresolved = append(unresolved, account)
+		}
+	}
+	return unresolved
+}
+
+// resolveAWSAccounts resolves accounts in parallel. It returns the accounts
+// that could be reached and an error for each one that could not.
+func resolveAWSAccounts(ctx context.Context, scope Scope, accounts []AWSAccount) ([]AWSAccount, []error) {
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+	log.Printf("   -> Resolving %d AWS account(s)...", len(accounts))
+	resolved := make([]AWSAccount, len(accounts))
+	errs := make([]error, len(accounts))
+	sem := make(chan struct{}, maxAccountConcurrency)
+	var wg sync.WaitGroup
+	for i, account := range accounts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			resolved[i], errs[i] = ResolveAWSAccount(ctx, scope, account)
+		}()
+	}
+	wg.Wait()
+
+	var reached []AWSAccount
+	var failed []error
+	for i, account := range resolved {
+		if errs[i] != nil {
+			failed = append(failed, fmt.Errorf("account %s: %w", accounts[i].label(), errs[i]))
+			continue
+		}
+		reached = append(reached, account)
+	}
+	return reached, failed
+}
+
+// ResolveAWSAccount looks up the ID and alias of the account that the
+// credentials of account reach.
+func ResolveAWSAccount(ctx context.Context, scope Scope, account AWSAccount) (AWSAccount, error) {
+	cfg, err := awsConfigFor(ctx, scope.WithAccount(account))
+	if err != nil {
+		return AWSAccount{}, err
+	}
+
+	stsClient := sts.NewFromConfig(cfg)
+	identity, err := callAPI(ctx, apiAWSSTS, func() (*sts.GetCallerIdentityOutput, error) {
+		return stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	})
+	if err != nil {
+		return AWSAccount{}, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	id := aws.ToString(identity.Account)
+	if account.ID != "" && account.ID != id {
+		return AWSAccount{}, fmt.Errorf("credentials belong to account %s, not %s", id, account.ID)
+	}
+	account.ID = id
+
+	if account.Alias == "" {
+		iamClient := iam.NewFromConfig(cfg)
+		aliases, err := callAPI(ctx, apiAWSIAM, func() (*iam.ListAccountAliasesOutput, error) {
+			return iamClient.ListAccountAliases(ctx, &iam.ListAccountAliasesInput{})
+		})
+		switch {
+		case err != nil:
+			log.Printf("Warning: could not look up the alias of AWS account %s: %v", id, err)
+		case len(aliases.AccountAliases) > 0:
+			account.Alias = aliases.AccountAliases[0]
+		}
+	}
+	return account, nil
+}
+
+func awsAccountResource(account AWSAccount) StandardizedResource {
+	name := account.Alias
+	if name == "" {
+		name = account.ID
+	}
+	attributes := map[string]string{}
+	if account.Alias != "" {
+		attributes["account_alias"] = account.Alias
+	}
+	if account.Profile != "" {
+		attributes["profile"] = account.Profile
+	}
+	if account.RoleARN != "" {
+		attributes["role_arn"] = account.RoleARN
+	}
+	return StandardizedResource{
+		Provider:   "aws",
+		Service:    "aws-account",
+		Region:     "global",
+		ID:         account.ID,
+		Name:       name,
+		Attributes: attributes,
+	}
+}
+
+// label names an account in messages before its ID is known.
+func (a AWSAccount) label() string {
+	switch {
+	case a.ID != "":
+		return a.ID
+	case a.RoleARN != "":
+		return a.RoleARN
+	case a.Profile != "":
+		return "profile " + a.Profile
+	default:
+		return "default credentials"
+	}
+}
+
+// stampAWSAccount records the account that resources were collected from.
+func stampAWSAccount(resources []StandardizedResource, account AWSAccount) {
+	if account.ID == "" {
+		return
+	}
+	for i := range resources {
+		if resources[i].Attributes == nil {
+			resources[i].Attributes = make(map[string]string)
+		}
+		resources[i].Attributes["account_id"] = account.ID
+		if account.Alias != "" {
+			resources[i].Attributes["account_alias"] = account.Alias
+		}
+	}
+}
+
+// awsAccountFor returns the account of a discovered "aws-account" resource,
+// with the external ID taken from the matching configured account.
+func (s Scope) awsAccountFor(res StandardizedResource) AWSAccount {
+	account := AWSAccountFromResource(res)
+	if account.RoleARN == "" {
+		return account
+	}
+	if org := s.AWSOrganization; org.RoleName != "" && strings.HasSuffix(account.RoleARN, ":role/"+org.RoleName) {
+		account.ExternalID = org.ExternalID
+	}
+	for _, configured := range s.AWSAccounts {
+		if configured.RoleARN == account.RoleARN {
+			account.ExternalID = configured.ExternalID
+		}
+	}
+	return account
+}
+
+// loadAWSConfig loads the default AWS configuration, or that of a named
+// profile. The SDK's own retryer is disabled so that throttling is handled
+// once, by the shared Throttle.
+func loadAWSConfig(ctx context.Context, profile string) (aws.Config, error) {
+	opts := []func(*config.LoadOptions) error{
+		config.WithRetryer(func() aws.Retryer { return aws.NopRetryer{} }),
+	}
+	if profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+	return config.LoadDefaultConfig(ctx, opts...)
+}
+
+// awsConfigs memoizes loaded configurations per account, so that the
+// fetchers of one account share a profile load and an assumed-role session.
+var awsConfigs sync.Map
+
+// awsConfigFor returns the AWS configuration for scope.Account. The base
+// configuration is scope.AWSConfig or the one of the account's profile; when
+// the account has a role, its credentials are replaced by the assumed role's.
+func awsConfigFor(ctx context.Context, scope Scope) (aws.Config, error) {
+	account := scope.Account
+	key := account.Profile + "|" + account.RoleARN + "|" + account.ExternalID
+	if scope.AWSConfig == nil {
+		if cfg, ok := awsConfigs.Load(key); ok {
+			return cfg.(aws.Config), nil
+		}
+	}
+
+	var cfg aws.Config
+	if scope.AWSConfig != nil {
+		cfg = scope.AWSConfig.Copy()
+	} else {
+		var err error
+		cfg, err = loadAWSConfig(ctx, account.Profile)
+		if err != nil {
+			return aws.Config{}, fmt.Errorf("failed to load configuration: %w", err)
+		}
+	}
+	if cfg.Region == "" {
+		cfg.Region = defaultAWSRegion
+	}
+	if account.RoleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), account.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = roleSessionName
+			if account.ExternalID != "" {
+				o.ExternalID = aws.String(account.ExternalID)
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(throttledCredentials{provider})
+	}
+
+	if scope.AWSConfig == nil {
+		awsConfigs.Store(key, cfg)
+	}
+	return cfg, nil
+}
+
+// throttledCredentials routes the STS calls of a credentials provider
+// through the shared Throttle.
+type throttledCredentials struct {
+	provider aws.CredentialsProvider
+}
+
+func (c throttledCredentials) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	return callAPI(ctx, apiAWSSTS, func() (aws.Credentials, error) { return c.provider.Retrieve(ctx) })
+}
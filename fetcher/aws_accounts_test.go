@@ -0,0 +1,130 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const (
+	stsHost    = "sts.us-east-1.amazonaws.com"
+	awsIAMHost = "iam.amazonaws.com"
+)
+
+func TestFetchAWSAccounts(t *testing.T) {
+	const roleARN = "arn:aws:iam::444455556666:role/InfrakitReadOnly"
+
+	tests := []struct {
+		name     string
+		accounts []AWSAccount
+		only     string
+		setup    func(f *fakeCloud)
+		want     []StandardizedResource
+		wantErr  bool
+	}{
+		{
+			name: "default credentials",
+			setup: func(f *fakeCloud) {
+				f.handle(stsHost, "GetCallerIdentity", "aws/sts/get_caller_identity.xml")
+				f.handle(awsIAMHost, "ListAccountAliases", "aws/iam/list_account_aliases.xml")
+			},
+			want: []StandardizedResource{{
+				Provider: "aws", Service: "aws-account", Region: "global", ID: "111122223333", Name: "acme-prod",
+				Attributes: map[string]string{"account_alias": "acme-prod"},
+			}},
+		},
+		{
+			name:     "assumed role with configured alias",
+			accounts: []AWSAccount{{ID: "444455556666", Alias: "acme-staging", RoleARN: roleARN}},
+			setup: func(f *fakeCloud) {
+				f.handle(stsHost, "AssumeRole", "aws/sts/assume_role.xml")
+				f.handle(stsHost, "GetCallerIdentity", "aws/sts/get_caller_identity_assumed_role.xml")
+			},
+			want: []StandardizedResource{{
+				Provider: "aws", Service: "aws-account", Region: "global", ID: "444455556666", Name: "acme-staging",
+				Attributes: map[string]string{"account_alias": "acme-staging", "role_arn": roleARN},
+			}},
+		},
+		{
+			name: "alias lookup denied",
+			setup: func(f *fakeCloud) {
+				f.handle(stsHost, "GetCallerIdentity", "aws/sts/get_caller_identity.xml")
+				f.respond(awsIAMHost, "ListAccountAliases", http.StatusForbidden, "aws/errors/iam_access_denied.xml")
+			},
+			want: []StandardizedResource{{
+				Provider: "aws", Service: "aws-account", Region: "global", ID: "111122223333", Name: "111122223333",
+				Attributes: map[string]string{},
+			}},
+		},
+		{
+			name:     "credentials reach another account",
+			accounts: []AWSAccount{{ID: "999999999999", Alias: "acme-dev"}},
+			setup: func(f *fakeCloud) {
+				f.handle(stsHost, "GetCallerIdentity", "aws/sts/get_caller_identity.xml")
+			},
+			wantErr: true,
+		},
+		{
+			name:     "role cannot be assumed",
+			accounts: []AWSAccount{{Alias: "acme-staging", RoleARN: roleARN}},
+			setup: func(f *fakeCloud) {
+				f.respond(stsHost, "AssumeRole", http.StatusForbidden, "aws/errors/sts_access_denied.xml")
+			},
+			wantErr: true,
+		},
+		{
+			name: "targeted account not reachable",
+			only: "444455556666",
+			setup: func(f *fakeCloud) {
+				f.handle(stsHost, "GetCallerIdentity", "aws/sts/get_caller_identity.xml")
+				f.handle(awsIAMHost, "ListAccountAliases", "aws/iam/list_account_aliases.xml")
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeCloud(t)
+			tt.setup(f)
+			cfg := f.awsConfig("us-east-1")
+			scope := Scope{AWSConfig: &cfg, AWSAccounts: tt.accounts, Account: AWSAccount{ID: tt.only}}
+
+			got, err := FetchAWSAccounts(t.Context(), scope)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchAWSAccounts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FetchAWSAccounts() =\n %+v\nwant\n %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAWSFetchersStampAccount(t *testing.T) {
+	f := newFakeCloud(t)
+	f.handle("ec2.eu-west-1.amazonaws.com", "DescribeInstances", "aws/ec2/describe_instances_eu_west_1.xml")
+	cfg := f.awsConfig("us-east-1")
+
+	account := StandardizedResource{
+		Provider: "aws", Service: "aws-account", ID: "111122223333",
+		Attributes: map[string]string{"account_alias": "acme-prod"},
+	}
+	scope, ok := ChildScope(Scope{AWSConfig: &cfg, AWSRegions: []string{"eu-west-1"}}, account)
+	if !ok {
+		t.Fatal("ChildScope() did not accept an aws-account resource")
+	}
+	ec2Fetcher, _ := LookupFetcher("aws-ec2")
+	resources, err := ec2Fetcher.Fetch(t.Context(), scope)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(resources) == 0 {
+		t.Fatal("Expected instances")
+	}
+	for _, res := range resources {
+		if res.Attributes["account_id"] != "111122223333" || res.Attributes["account_alias"] != "acme-prod" {
+			t.Errorf("%s: account attributes = %q, %q", res.ID, res.Attributes["account_id"], res.Attributes["account_alias"])
+		}
+	}
+}
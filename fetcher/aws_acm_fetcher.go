@@ -0,0 +1,158 @@
+// fetcher/aws_acm_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+func init() {
+	Register(NewFetcher("aws-acm", "aws", ScopeProject, []string{"acmcertificate"}, regionalAWSFetcher(FetchACMCertificates)))
+}
+
+// FetchACMCertificates collects the ACM certificates in the region of cfg,
+// of every key algorithm. A certificate's ID is its ARN, as listed in the
+// "certificates" attribute of load balancer listeners, and "in_use_by" holds
+// the ARNs of the resources using it. Certificates that cannot be described
+// are reported as a ServiceError.
+func FetchACMCertificates(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	client := acm.NewFromConfig(cfg)
+	region := cfg.Region
+	log.Printf("   -> Fetching ACM certificates for AWS region: %s", region)
+
+	// Without a key type filter, only RSA 2048 certificates are listed.
+	var arns []string
+	paginator := acm.NewListCertificatesPaginator(client, &acm.ListCertificatesInput{
+		Includes: &types.Filters{KeyTypes: types.KeyAlgorithm("").Values()},
+	})
+	if err := eachPage(ctx, apiAWSACM, paginator.HasMorePages, paginator.NextPage, func(page *acm.ListCertificatesOutput) {
+		for _, summary := range page.CertificateSummaryList {
+			arns = append(arns, aws.ToString(summary.CertificateArn))
+		}
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list ACM certificates: %w", err)
+	}
+
+	var errs []error
+	for _, arn := range arns {
+		out, err := callAPI(ctx, apiAWSACM, func() (*acm.DescribeCertificateOutput, error) {
+			return client.DescribeCertificate(ctx, &acm.DescribeCertificateInput{CertificateArn: aws.String(arn)})
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not describe certificate %s: %w", arn, err))
+			continue
+		}
+		resources = append(resources, acmCertificateResource(out.Certificate, region))
+	}
+
+	log.Printf("Successfully fetched %d ACM certificates in %s.\n", len(resources), region)
+	if err := errors.Join(errs...); err != nil {
+		return resources, &ServiceError{Service: "acmcertificate", Err: err}
+	}
+	return resources, nil
+}
+
+func acmCertificateResource(cert *types.CertificateDetail, region string) StandardizedResource {
+	attributes := map[string]string{
+		"domains":             strings.Join(cert.SubjectAlternativeNames, ", "),
+		"status":              strings.ToLower(string(cert.Status)),
+		"type":                strings.ToLower(string(cert.Type)),
+		"issuer":              aws.ToString(cert.Issuer),
+		"key_algorithm":       string(cert.KeyAlgorithm),
+		"renewal_eligibility": strings.ToLower(string(cert.RenewalEligibility)),
+		"in_use":              fmt.Sprintf("%t", len(cert.InUseBy) > 0),
+		"in_use_by":           strings.Join(cert.InUseBy, ", "),
+	}
+	for key, value := range map[string]*time.Time{
+		"created":    cert.CreatedAt,
+		"issued":     cert.IssuedAt,
+		"imported":   cert.ImportedAt,
+		"not_before": cert.NotBefore,
+		"expires":    cert.NotAfter,
+	} {
+		if value != nil {
+			attributes[key] = value.UTC().Format(time.RFC3339)
+		}
+	}
+	if renewal := cert.RenewalSummary; renewal != nil {
+		attributes["renewal_status"] = strings.ToLower(string(renewal.RenewalStatus))
+	}
+
+	return StandardizedResource{
+		Provider:   "aws",
+		Service:    "acmcertificate",
+		Region:     region,
+		ID:         aws.ToString(cert.CertificateArn),
+		Name:       aws.ToString(cert.DomainName),
+		Attributes: dropEmpty(attributes),
+	}
+}
+
+// CertificateExpiry is a certificate that expires soon and what uses it.
+type CertificateExpiry struct {
+	Certificate StandardizedResource `json:"certificate"`
+	Expires     time.Time            `json:"expires"`
+	DaysLeft    int                  `json:"daysLeft"`
+	// InUseBy lists the ARNs of everything using the certificate, and
+	// AttachedTo those of them that are in the cache.
+	InUseBy    []string               `json:"inUseBy,omitempty"`
+	AttachedTo []StandardizedResource `json:"attachedTo,omitempty"`
+}
+
+// ExpiringCertificates returns the ACM certificates collected by
+// FetchACMCertificates that expire within the given duration, or have
+// already expired, soonest first. A certificate is in use by the resources
+// ACM reports and by the load balancers whose listeners serve it.
+func ExpiringCertificates(resources []StandardizedResource, within time.Duration) []CertificateExpiry {
+	byID := make(map[string]StandardizedResource)
+	listenersByCert := make(map[string][]string)
+	for _, res := range resources {
+		byID[res.ID] = res
+		if res.Service == "elblistener" {
+			for _, cert := range splitList(res.Attributes["certificates"]) {
+				listenersByCert[cert] = append(listenersByCert[cert], res.Attributes["parent_id"])
+			}
+		}
+	}
+
+	deadline := now().Add(within)
+	var expiring []CertificateExpiry
+	for _, res := range resources {
+		if res.Service != "acmcertificate" {
+			continue
+		}
+		expires, err := time.Parse(time.RFC3339, res.Attributes["expires"])
+		if err != nil || expires.After(deadline) {
+			continue
+		}
+		entry := CertificateExpiry{
+			Certificate: res,
+			Expires:     expires,
+			DaysLeft:    int(expires.Sub(now()).Hours() / 24),
+		}
+		seen := make(map[string]bool)
+		for _, arn := range append(splitList(res.Attributes["in_use_by"]), listenersByCert[res.ID]...) {
+			if arn == "" || seen[arn] {
+				continue
+			}
+			seen[arn] = true
+			entry.InUseBy = append(entry.InUseBy, arn)
+			if target, ok := byID[arn]; ok {
+				entry.AttachedTo = append(entry.AttachedTo, target)
+			}
+		}
+		expiring = append(expiring, entry)
+	}
+	sort.SliceStable(expiring, func(i, j int) bool { return expiring[i].Expires.Before(expiring[j].Expires) })
+	return expiring
+}
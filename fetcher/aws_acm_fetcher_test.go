@@ -0,0 +1,175 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const acmHost = "acm.us-east-1.amazonaws.com"
+
+// acmTarget is the X-Amz-Target prefix of the ACM JSON API.
+const acmTarget = "CertificateManager."
+
+const (
+	wwwCertARN = "arn:aws:acm:us-east-1:123456789012:certificate/3f6a1c2e-www"
+	apiCertARN = "arn:aws:acm:us-east-1:123456789012:certificate/9b2d4e6f-api"
+	cdnCertARN = "arn:aws:acm:us-east-1:123456789012:certificate/c4d5e6f7-cdn"
+	webALBARN  = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web-alb/50dc6c495c0c9188"
+)
+
+func serveACMCertificates(f *fakeCloud) {
+	f.handle(acmHost, acmTarget+"ListCertificates", "aws/acm/list_certificates_page1.json")
+	f.handle(acmHost, acmTarget+"ListCertificates?token=page-2", "aws/acm/list_certificates_page2.json")
+	// Certificates are described in the order they are listed.
+	f.handle(acmHost, acmTarget+"DescribeCertificate", "aws/acm/describe_certificate_www.json")
+	f.handle(acmHost, acmTarget+"DescribeCertificate", "aws/acm/describe_certificate_api.json")
+	f.handle(acmHost, acmTarget+"DescribeCertificate", "aws/acm/describe_certificate_cdn.json")
+}
+
+func TestFetchACMCertificates(t *testing.T) {
+	f := newFakeCloud(t)
+	serveACMCertificates(f)
+
+	resources, err := FetchACMCertificates(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchACMCertificates() error = %v", err)
+	}
+	index := resourceIndex(resources)
+
+	tests := []struct {
+		id    string
+		name  string
+		attrs map[string]string
+	}{
+		{wwwCertARN, "www.example.com", map[string]string{
+			"domains":             "www.example.com, example.com",
+			"status":              "issued",
+			"type":                "amazon_issued",
+			"issuer":              "Amazon",
+			"key_algorithm":       "RSA-2048",
+			"renewal_eligibility": "eligible",
+			"renewal_status":      "pending_validation",
+			"in_use":              "true",
+			"in_use_by":           webALBARN,
+			"created":             "2024-06-21T00:00:00Z",
+			"issued":              "2024-07-20T00:00:00Z",
+			"not_before":          "2024-07-20T00:00:00Z",
+			"expires":             "2025-07-20T23:59:59Z",
+		}},
+		{apiCertARN, "api.example.com", map[string]string{
+			"domains":             "api.example.com",
+			"status":              "issued",
+			"type":                "imported",
+			"issuer":              "Example Internal CA",
+			"key_algorithm":       "RSA-2048",
+			"renewal_eligibility": "ineligible",
+			"in_use":              "true",
+			"in_use_by":           webALBARN,
+			"created":             "2025-03-01T12:00:00Z",
+			"imported":            "2025-03-01T12:00:00Z",
+			"not_before":          "2025-03-01T12:00:00Z",
+			"expires":             "2025-07-15T00:00:00Z",
+		}},
+		{cdnCertARN, "cdn.example.com", map[string]string{
+			"domains":             "cdn.example.com",
+			"status":              "issued",
+			"type":                "amazon_issued",
+			"issuer":              "Amazon",
+			"key_algorithm":       "EC-prime256v1",
+			"renewal_eligibility": "eligible",
+			"in_use":              "true",
+			"in_use_by":           "arn:aws:cloudfront::123456789012:distribution/E2QWRUHEXAMPLE",
+			"created":             "2025-06-01T00:00:00Z",
+			"issued":              "2025-06-01T00:00:00Z",
+			"not_before":          "2025-06-01T00:00:00Z",
+			"expires":             "2026-06-30T23:59:59Z",
+		}},
+	}
+	if len(resources) != len(tests) {
+		t.Errorf("Expected %d certificates, got %v", len(tests), resourceKeys(resources))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, ok := index["acmcertificate/"+tt.id]
+			if !ok {
+				t.Fatalf("Missing certificate %s", tt.id)
+			}
+			if res.Provider != "aws" || res.Name != tt.name || res.Region != "us-east-1" {
+				t.Errorf("Unexpected resource %+v", res)
+			}
+			if !reflect.DeepEqual(res.Attributes, tt.attrs) {
+				t.Errorf("Attributes mismatch\n got: %v\nwant: %v", res.Attributes, tt.attrs)
+			}
+		})
+	}
+}
+
+func TestFetchACMCertificatesDescribeDenied(t *testing.T) {
+	f := newFakeCloud(t)
+	f.handle(acmHost, acmTarget+"ListCertificates", "aws/acm/list_certificates_page1.json")
+	f.handle(acmHost, acmTarget+"ListCertificates?token=page-2", "aws/acm/list_certificates_page2.json")
+	f.handle(acmHost, acmTarget+"DescribeCertificate", "aws/acm/describe_certificate_www.json")
+	f.respond(acmHost, acmTarget+"DescribeCertificate", http.StatusBadRequest, "aws/errors/acm_access_denied.json")
+
+	resources, err := FetchACMCertificates(t.Context(), f.awsConfig("us-east-1"))
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+	if failed := FailedServices(err); failed["acmcertificate"] == nil {
+		t.Errorf("Expected acmcertificate to fail, got %v", failed)
+	}
+	if len(resources) != 1 || resources[0].ID != wwwCertARN {
+		t.Errorf("Expected only the www certificate, got %v", resourceKeys(resources))
+	}
+}
+
+func TestExpiringCertificates(t *testing.T) {
+	useClock(t, "2025-07-01T00:00:00Z")
+	f := newFakeCloud(t)
+	serveACMCertificates(f)
+	resources, err := FetchACMCertificates(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchACMCertificates() error = %v", err)
+	}
+	lb := StandardizedResource{Provider: "aws", Service: "elb", Region: "us-east-1", ID: webALBARN, Name: "web-alb"}
+	// The listener of a load balancer ACM does not know about also uses the cdn certificate.
+	listener := StandardizedResource{Provider: "aws", Service: "elblistener", Region: "us-east-1", ID: "arn:aws:elasticloadbalancing:us-east-1:123456789012:listener/app/edge-alb/1/2", Name: "HTTPS:443",
+		Attributes: map[string]string{"parent_id": "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/edge-alb/1", "certificates": cdnCertARN}}
+	resources = append(resources, lb, listener)
+
+	tests := []struct {
+		within time.Duration
+		want   []string
+		days   []int
+	}{
+		{7 * 24 * time.Hour, nil, nil},
+		{30 * 24 * time.Hour, []string{apiCertARN, wwwCertARN}, []int{14, 19}},
+		{365 * 24 * time.Hour, []string{apiCertARN, wwwCertARN, cdnCertARN}, []int{14, 19, 364}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.within.String(), func(t *testing.T) {
+			expiring := ExpiringCertificates(resources, tt.within)
+			var got []string
+			var days []int
+			for _, e := range expiring {
+				got = append(got, e.Certificate.ID)
+				days = append(days, e.DaysLeft)
+			}
+			if !reflect.DeepEqual(got, tt.want) || !reflect.DeepEqual(days, tt.days) {
+				t.Errorf("ExpiringCertificates(%s) = %v %v, want %v %v", tt.within, got, days, tt.want, tt.days)
+			}
+		})
+	}
+
+	expiring := ExpiringCertificates(resources, 365*24*time.Hour)
+	if www := expiring[1]; len(www.AttachedTo) != 1 || www.AttachedTo[0].ID != webALBARN {
+		t.Errorf("Expected the www certificate to be attached to web-alb, got %+v", www.AttachedTo)
+	}
+	cdn := expiring[2]
+	wantInUse := []string{"arn:aws:cloudfront::123456789012:distribution/E2QWRUHEXAMPLE", "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/edge-alb/1"}
+	if !reflect.DeepEqual(cdn.InUseBy, wantInUse) || len(cdn.AttachedTo) != 0 {
+		t.Errorf("Unexpected use of the cdn certificate: %v, attached to %v", cdn.InUseBy, cdn.AttachedTo)
+	}
+}
@@ -0,0 +1,214 @@
+// fetcher/aws_ecs_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// Batch sizes accepted by DescribeClusters and DescribeServices.
+const (
+	ecsClusterBatch = 100
+	ecsServiceBatch = 10
+)
+
+func init() {
+	Register(NewFetcher("aws-ecs", "aws", ScopeProject, []string{"ecscluster", "ecsservice", "ecstaskdefinition"}, regionalAWSFetcher(FetchECSResources)))
+}
+
+// FetchECSResources collects the ECS clusters in the region of cfg, their
+// services, and the task definitions those services run. Only task
+// definitions in use are collected, since every registered revision stays
+// active until it is deregistered. All of them are identified by their ARN,
+// since names are only unique within a region. A service's "parent_id" is
+// the ID of its cluster and its "task_definition" the ID of its task
+// definition, which is named "<family>:<revision>". Failures are reported
+// per service as ServiceErrors.
+func FetchECSResources(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	client := ecs.NewFromConfig(cfg)
+	region := cfg.Region
+	log.Printf("   -> Fetching ECS resources for AWS region: %s", region)
+
+	var clusterARNs []string
+	clusters := ecs.NewListClustersPaginator(client, &ecs.ListClustersInput{})
+	if err := eachPage(ctx, apiAWSECS, clusters.HasMorePages, clusters.NextPage, func(page *ecs.ListClustersOutput) {
+		clusterARNs = append(clusterARNs, page.ClusterArns...)
+	}); err != nil {
+		// Services and task definitions are found through the clusters.
+		err = fmt.Errorf("could not list ECS clusters: %w", err)
+		return nil, errors.Join(&ServiceError{Service: "ecscluster", Err: err}, &ServiceError{Service: "ecsservice", Err: err}, &ServiceError{Service: "ecstaskdefinition", Err: err})
+	}
+
+	var clusterErrs, serviceErrs, taskErrs []error
+	for start := 0; start < len(clusterARNs); start += ecsClusterBatch {
+		batch := clusterARNs[start:min(start+ecsClusterBatch, len(clusterARNs))]
+		out, err := callAPI(ctx, apiAWSECS, func() (*ecs.DescribeClustersOutput, error) {
+			return client.DescribeClusters(ctx, &ecs.DescribeClustersInput{Clusters: batch, Include: []types.ClusterField{types.ClusterFieldTags}})
+		})
+		if err != nil {
+			clusterErrs = append(clusterErrs, fmt.Errorf("could not describe ECS clusters: %w", err))
+			continue
+		}
+		for _, cluster := range out.Clusters {
+			resources = append(resources, ecsClusterResource(cluster, region))
+		}
+	}
+
+	taskDefinitions := make(map[string]bool)
+	for _, clusterARN := range clusterARNs {
+		var serviceARNs []string
+		services := ecs.NewListServicesPaginator(client, &ecs.ListServicesInput{Cluster: aws.String(clusterARN)})
+		if err := eachPage(ctx, apiAWSECS, services.HasMorePages, services.NextPage, func(page *ecs.ListServicesOutput) {
+			serviceARNs = append(serviceARNs, page.ServiceArns...)
+		}); err != nil {
+			serviceErrs = append(serviceErrs, fmt.Errorf("could not list services of ECS cluster %s: %w", clusterARN, err))
+			continue
+		}
+		for start := 0; start < len(serviceARNs); start += ecsServiceBatch {
+			batch := serviceARNs[start:min(start+ecsServiceBatch, len(serviceARNs))]
+			out, err := callAPI(ctx, apiAWSECS, func() (*ecs.DescribeServicesOutput, error) {
+				return client.DescribeServices(ctx, &ecs.DescribeServicesInput{Cluster: aws.String(clusterARN), Services: batch, Include: []types.ServiceField{types.ServiceFieldTags}})
+			})
+			if err != nil {
+				serviceErrs = append(serviceErrs, fmt.Errorf("could not describe services of ECS cluster %s: %w", clusterARN, err))
+				continue
+			}
+			for _, service := range out.Services {
+				resources = append(resources, ecsServiceResource(service, region))
+				if arn := aws.ToString(service.TaskDefinition); arn != "" {
+					taskDefinitions[arn] = true
+				}
+			}
+		}
+	}
+	if len(serviceErrs) > 0 {
+		// The task definitions of services that could not be read are unknown.
+		taskErrs = append(taskErrs, errors.New("not every ECS service could be read"))
+	}
+
+	arns := make([]string, 0, len(taskDefinitions))
+	for arn := range taskDefinitions {
+		arns = append(arns, arn)
+	}
+	sort.Strings(arns)
+	for _, arn := range arns {
+		out, err := callAPI(ctx, apiAWSECS, func() (*ecs.DescribeTaskDefinitionOutput, error) {
+			return client.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{TaskDefinition: aws.String(arn), Include: []types.TaskDefinitionField{types.TaskDefinitionFieldTags}})
+		})
+		if err != nil {
+			taskErrs = append(taskErrs, fmt.Errorf("could not describe task definition %s: %w", arn, err))
+			continue
+		}
+		resources = append(resources, ecsTaskDefinitionResource(out.TaskDefinition, out.Tags, region))
+	}
+
+	var errs []error
+	if err := errors.Join(clusterErrs...); err != nil {
+		errs = append(errs, &ServiceError{Service: "ecscluster", Err: err})
+	}
+	if err := errors.Join(serviceErrs...); err != nil {
+		errs = append(errs, &ServiceError{Service: "ecsservice", Err: err})
+	}
+	if err := errors.Join(taskErrs...); err != nil {
+		errs = append(errs, &ServiceError{Service: "ecstaskdefinition", Err: err})
+	}
+	log.Printf("Successfully fetched %d ECS resources in %s.\n", len(resources), region)
+	return resources, errors.Join(errs...)
+}
+
+func ecsClusterResource(cluster types.Cluster, region string) StandardizedResource {
+	attributes := map[string]string{
+		"arn":                aws.ToString(cluster.ClusterArn),
+		"status":             strings.ToLower(aws.ToString(cluster.Status)),
+		"active_services":    fmt.Sprintf("%d", cluster.ActiveServicesCount),
+		"running_tasks":      fmt.Sprintf("%d", cluster.RunningTasksCount),
+		"capacity_providers": strings.Join(cluster.CapacityProviders, ", "),
+	}
+	attributes = dropEmpty(attributes)
+	setTagMap(attributes, ecsTagMap(cluster.Tags))
+
+	return StandardizedResource{Provider: "aws", Service: "ecscluster", Region: region, ID: aws.ToString(cluster.ClusterArn), Name: aws.ToString(cluster.ClusterName), Attributes: attributes}
+}
+
+func ecsServiceResource(service types.Service, region string) StandardizedResource {
+	var targetGroups []string
+	for _, lb := range service.LoadBalancers {
+		if arn := aws.ToString(lb.TargetGroupArn); arn != "" {
+			targetGroups = append(targetGroups, arn)
+		}
+	}
+	attributes := map[string]string{
+		"parent_id":        aws.ToString(service.ClusterArn),
+		"arn":              aws.ToString(service.ServiceArn),
+		"status":           strings.ToLower(aws.ToString(service.Status)),
+		"launch_type":      string(service.LaunchType),
+		"platform_version": aws.ToString(service.PlatformVersion),
+		"desired_count":    fmt.Sprintf("%d", service.DesiredCount),
+		"running_count":    fmt.Sprintf("%d", service.RunningCount),
+		"task_definition":  aws.ToString(service.TaskDefinition),
+		"target_groups":    strings.Join(targetGroups, ", "),
+	}
+	if network := service.NetworkConfiguration; network != nil && network.AwsvpcConfiguration != nil {
+		vpc := network.AwsvpcConfiguration
+		attributes["subnets"] = strings.Join(vpc.Subnets, ", ")
+		attributes["security_groups"] = strings.Join(vpc.SecurityGroups, ", ")
+		attributes["assign_public_ip"] = strings.ToLower(string(vpc.AssignPublicIp))
+	}
+	attributes = dropEmpty(attributes)
+	setTagMap(attributes, ecsTagMap(service.Tags))
+
+	return StandardizedResource{Provider: "aws", Service: "ecsservice", Region: region, ID: aws.ToString(service.ServiceArn), Name: aws.ToString(service.ServiceName), Attributes: attributes}
+}
+
+func ecsTaskDefinitionResource(task *types.TaskDefinition, tags []types.Tag, region string) StandardizedResource {
+	var images []string
+	for _, container := range task.ContainerDefinitions {
+		images = append(images, aws.ToString(container.Name)+"="+aws.ToString(container.Image))
+	}
+	var compatibilities []string
+	for _, c := range task.RequiresCompatibilities {
+		compatibilities = append(compatibilities, string(c))
+	}
+	attributes := map[string]string{
+		"arn":             aws.ToString(task.TaskDefinitionArn),
+		"family":          aws.ToString(task.Family),
+		"revision":        fmt.Sprintf("%d", task.Revision),
+		"status":          strings.ToLower(string(task.Status)),
+		"images":          strings.Join(images, ", "),
+		"cpu":             aws.ToString(task.Cpu),
+		"memory":          aws.ToString(task.Memory),
+		"network_mode":    string(task.NetworkMode),
+		"compatibilities": strings.Join(compatibilities, ", "),
+		"task_role":       aws.ToString(task.TaskRoleArn),
+		"execution_role":  aws.ToString(task.ExecutionRoleArn),
+	}
+	attributes = dropEmpty(attributes)
+	setTagMap(attributes, ecsTagMap(tags))
+
+	arn := aws.ToString(task.TaskDefinitionArn)
+	return StandardizedResource{Provider: "aws", Service: "ecstaskdefinition", Region: region, ID: arn, Name: ecsNameFromARN(arn), Attributes: attributes}
+}
+
+// ecsNameFromARN returns the last part of an ECS ARN: the cluster name of
+// "arn:aws:ecs:...:cluster/prod", or "web:42" of "arn:aws:ecs:...:task-definition/web:42".
+func ecsNameFromARN(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+// ecsTagMap converts an ECS tag list for setTagMap.
+func ecsTagMap(tags []types.Tag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		m[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return m
+}
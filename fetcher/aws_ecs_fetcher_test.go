@@ -0,0 +1,151 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const ecsHost = "ecs.us-east-1.amazonaws.com"
+
+// ecsTarget is the X-Amz-Target prefix of the ECS JSON API.
+const ecsTarget = "AmazonEC2ContainerServiceV20141113."
+
+const (
+	ecsARN          = "arn:aws:ecs:us-east-1:123456789012:"
+	ecsWebCluster   = ecsARN + "cluster/web"
+	ecsFrontend     = ecsARN + "service/web/frontend"
+	ecsWorker       = ecsARN + "service/web/worker"
+	ecsFrontendTask = ecsARN + "task-definition/frontend:42"
+	ecsWorkerTask   = ecsARN + "task-definition/worker:7"
+)
+
+func serveECSServices(f *fakeCloud) {
+	f.handle(ecsHost, ecsTarget+"ListClusters", "aws/ecs/list_clusters.json")
+	f.handle(ecsHost, ecsTarget+"DescribeClusters", "aws/ecs/describe_clusters.json")
+	f.handle(ecsHost, ecsTarget+"ListServices", "aws/ecs/list_services.json")
+	f.handle(ecsHost, ecsTarget+"DescribeServices", "aws/ecs/describe_services.json")
+}
+
+func TestFetchECSResources(t *testing.T) {
+	f := newFakeCloud(t)
+	serveECSServices(f)
+	// Task definitions are described in order of their ARNs.
+	f.handle(ecsHost, ecsTarget+"DescribeTaskDefinition", "aws/ecs/describe_task_definition_frontend.json")
+	f.handle(ecsHost, ecsTarget+"DescribeTaskDefinition", "aws/ecs/describe_task_definition_worker.json")
+
+	resources, err := FetchECSResources(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchECSResources() error = %v", err)
+	}
+	index := resourceIndex(resources)
+
+	tests := []struct {
+		key   string
+		name  string
+		attrs map[string]string
+	}{
+		{"ecscluster/" + ecsWebCluster, "web", map[string]string{
+			"arn":                ecsWebCluster,
+			"status":             "active",
+			"active_services":    "2",
+			"running_tasks":      "4",
+			"capacity_providers": "FARGATE, FARGATE_SPOT",
+			"tag:team":           "web",
+		}},
+		{"ecsservice/" + ecsFrontend, "frontend", map[string]string{
+			"parent_id":        ecsWebCluster,
+			"arn":              ecsFrontend,
+			"status":           "active",
+			"launch_type":      "FARGATE",
+			"platform_version": "1.4.0",
+			"desired_count":    "3",
+			"running_count":    "3",
+			"task_definition":  ecsFrontendTask,
+			"target_groups":    "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/frontend/73e2d6bc24d8a067",
+			"subnets":          "subnet-0aaa000000000001, subnet-0bbb000000000002",
+			"security_groups":  "sg-0web000000000001",
+			"assign_public_ip": "disabled",
+		}},
+		{"ecsservice/" + ecsWorker, "worker", map[string]string{
+			"parent_id":        ecsWebCluster,
+			"arn":              ecsWorker,
+			"status":           "active",
+			"launch_type":      "FARGATE",
+			"platform_version": "LATEST",
+			"desired_count":    "1",
+			"running_count":    "1",
+			"task_definition":  ecsWorkerTask,
+			"tag:queue":        "orders",
+		}},
+		{"ecstaskdefinition/" + ecsFrontendTask, "frontend:42", map[string]string{
+			"arn":             ecsFrontendTask,
+			"family":          "frontend",
+			"revision":        "42",
+			"status":          "active",
+			"images":          "web=123456789012.dkr.ecr.us-east-1.amazonaws.com/frontend:1.8.2, envoy=public.ecr.aws/appmesh/aws-appmesh-envoy:v1.27.2.0-prod",
+			"cpu":             "512",
+			"memory":          "1024",
+			"network_mode":    "awsvpc",
+			"compatibilities": "FARGATE",
+			"task_role":       "arn:aws:iam::123456789012:role/frontend-task",
+			"execution_role":  "arn:aws:iam::123456789012:role/ecsTaskExecutionRole",
+			"tag:team":        "web",
+		}},
+		{"ecstaskdefinition/" + ecsWorkerTask, "worker:7", map[string]string{
+			"arn":             ecsWorkerTask,
+			"family":          "worker",
+			"revision":        "7",
+			"status":          "active",
+			"images":          "worker=123456789012.dkr.ecr.us-east-1.amazonaws.com/worker:2.0.1",
+			"cpu":             "256",
+			"memory":          "512",
+			"network_mode":    "awsvpc",
+			"compatibilities": "FARGATE",
+			"execution_role":  "arn:aws:iam::123456789012:role/ecsTaskExecutionRole",
+		}},
+	}
+	if len(resources) != len(tests) {
+		t.Errorf("Expected %d resources, got %v", len(tests), resourceKeys(resources))
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			res, ok := index[tt.key]
+			if !ok {
+				t.Fatalf("Missing resource %s", tt.key)
+			}
+			if res.Provider != "aws" || res.Name != tt.name || res.Region != "us-east-1" {
+				t.Errorf("Unexpected resource %+v", res)
+			}
+			if !reflect.DeepEqual(res.Attributes, tt.attrs) {
+				t.Errorf("Attributes mismatch\n got: %v\nwant: %v", res.Attributes, tt.attrs)
+			}
+			// Container environment values can hold secrets.
+			for key, value := range res.Attributes {
+				if strings.Contains(value, "do-not-cache") {
+					t.Errorf("Attribute %s leaks an environment value: %q", key, value)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchECSResourcesTaskDefinitionsDenied(t *testing.T) {
+	f := newFakeCloud(t)
+	serveECSServices(f)
+	f.respond(ecsHost, ecsTarget+"DescribeTaskDefinition", http.StatusBadRequest, "aws/errors/ecs_access_denied.json")
+
+	resources, err := FetchECSResources(t.Context(), f.awsConfig("us-east-1"))
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+	if failed := FailedServices(err); len(failed) != 1 || failed["ecstaskdefinition"] == nil {
+		t.Errorf("Expected only ecstaskdefinition to fail, got %v", failed)
+	}
+	// The cluster and its services are still returned.
+	want := []string{"ecscluster/" + ecsWebCluster, "ecsservice/" + ecsFrontend, "ecsservice/" + ecsWorker}
+	if got := resourceKeys(resources); !reflect.DeepEqual(got, want) {
+		t.Errorf("Resources mismatch\n got: %v\nwant: %v", got, want)
+	}
+}
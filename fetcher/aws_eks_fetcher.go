@@ -0,0 +1,131 @@
+// fetcher/aws_eks_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+func init() {
+	Register(NewFetcher("aws-eks", "aws", ScopeProject, []string{"ekscluster", "eksnodegroup"}, regionalAWSFetcher(FetchEKSClusters)))
+}
+
+// FetchEKSClusters collects the EKS clusters in the region of cfg and their
+// managed node groups, identified by their ARN since names are only unique
+// within a region. A node group's "parent_id" attribute is the ID of its
+// cluster. Failures are reported per service as ServiceErrors.
+func FetchEKSClusters(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	var errs []error
+	client := eks.NewFromConfig(cfg)
+	region := cfg.Region
+	log.Printf("   -> Fetching EKS clusters for AWS region: %s", region)
+
+	var names []string
+	clusters := eks.NewListClustersPaginator(client, &eks.ListClustersInput{})
+	if err := eachPage(ctx, apiAWSEKS, clusters.HasMorePages, clusters.NextPage, func(page *eks.ListClustersOutput) {
+		names = append(names, page.Clusters...)
+	}); err != nil {
+		// Without the clusters there are no node groups to list either.
+		err = fmt.Errorf("could not list EKS clusters: %w", err)
+		return nil, errors.Join(&ServiceError{Service: "ekscluster", Err: err}, &ServiceError{Service: "eksnodegroup", Err: err})
+	}
+
+	var clusterErrs, nodegroupErrs []error
+	for _, name := range names {
+		out, err := callAPI(ctx, apiAWSEKS, func() (*eks.DescribeClusterOutput, error) {
+			return client.DescribeCluster(ctx, &eks.DescribeClusterInput{Name: aws.String(name)})
+		})
+		if err != nil {
+			clusterErrs = append(clusterErrs, fmt.Errorf("could not describe EKS cluster %s: %w", name, err))
+			continue
+		}
+		resources = append(resources, eksClusterResource(out.Cluster, region))
+		clusterARN := aws.ToString(out.Cluster.Arn)
+
+		nodegroups := eks.NewListNodegroupsPaginator(client, &eks.ListNodegroupsInput{ClusterName: aws.String(name)})
+		var nodegroupNames []string
+		if err := eachPage(ctx, apiAWSEKS, nodegroups.HasMorePages, nodegroups.NextPage, func(page *eks.ListNodegroupsOutput) {
+			nodegroupNames = append(nodegroupNames, page.Nodegroups...)
+		}); err != nil {
+			nodegroupErrs = append(nodegroupErrs, fmt.Errorf("could not list node groups of EKS cluster %s: %w", name, err))
+			continue
+		}
+		for _, nodegroup := range nodegroupNames {
+			out, err := callAPI(ctx, apiAWSEKS, func() (*eks.DescribeNodegroupOutput, error) {
+				return client.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{ClusterName: aws.String(name), NodegroupName: aws.String(nodegroup)})
+			})
+			if err != nil {
+				nodegroupErrs = append(nodegroupErrs, fmt.Errorf("could not describe node group %s/%s: %w", name, nodegroup, err))
+				continue
+			}
+			resources = append(resources, eksNodegroupResource(out.Nodegroup, clusterARN, region))
+		}
+	}
+	if err := errors.Join(clusterErrs...); err != nil {
+		errs = append(errs, &ServiceError{Service: "ekscluster", Err: err})
+	}
+	if err := errors.Join(nodegroupErrs...); err != nil {
+		errs = append(errs, &ServiceError{Service: "eksnodegroup", Err: err})
+	}
+
+	log.Printf("Successfully fetched %d EKS resources in %s.\n", len(resources), region)
+	return resources, errors.Join(errs...)
+}
+
+func eksClusterResource(cluster *types.Cluster, region string) StandardizedResource {
+	attributes := map[string]string{
+		"arn":              aws.ToString(cluster.Arn),
+		"version":          aws.ToString(cluster.Version),
+		"platform_version": aws.ToString(cluster.PlatformVersion),
+		"status":           strings.ToLower(string(cluster.Status)),
+		"endpoint":         aws.ToString(cluster.Endpoint),
+		"role":             aws.ToString(cluster.RoleArn),
+	}
+	if vpc := cluster.ResourcesVpcConfig; vpc != nil {
+		attributes["vpc_id"] = aws.ToString(vpc.VpcId)
+		attributes["subnets"] = strings.Join(vpc.SubnetIds, ", ")
+		groups := vpc.SecurityGroupIds
+		if id := aws.ToString(vpc.ClusterSecurityGroupId); id != "" {
+			groups = append([]string{id}, groups...)
+		}
+		attributes["security_groups"] = strings.Join(groups, ", ")
+		attributes["public_endpoint"] = fmt.Sprintf("%t", vpc.EndpointPublicAccess)
+		attributes["private_endpoint"] = fmt.Sprintf("%t", vpc.EndpointPrivateAccess)
+	}
+	attributes = dropEmpty(attributes)
+	setTagMap(attributes, cluster.Tags)
+
+	return StandardizedResource{Provider: "aws", Service: "ekscluster", Region: region, ID: aws.ToString(cluster.Arn), Name: aws.ToString(cluster.Name), Attributes: attributes}
+}
+
+func eksNodegroupResource(nodegroup *types.Nodegroup, clusterARN, region string) StandardizedResource {
+	attributes := map[string]string{
+		"parent_id":       clusterARN,
+		"arn":             aws.ToString(nodegroup.NodegroupArn),
+		"version":         aws.ToString(nodegroup.Version),
+		"release_version": aws.ToString(nodegroup.ReleaseVersion),
+		"status":          strings.ToLower(string(nodegroup.Status)),
+		"instance_types":  strings.Join(nodegroup.InstanceTypes, ", "),
+		"capacity_type":   string(nodegroup.CapacityType),
+		"ami_type":        string(nodegroup.AmiType),
+		"subnets":         strings.Join(nodegroup.Subnets, ", "),
+		"role":            aws.ToString(nodegroup.NodeRole),
+	}
+	if scaling := nodegroup.ScalingConfig; scaling != nil {
+		attributes["min_size"] = fmt.Sprintf("%d", aws.ToInt32(scaling.MinSize))
+		attributes["desired_size"] = fmt.Sprintf("%d", aws.ToInt32(scaling.DesiredSize))
+		attributes["max_size"] = fmt.Sprintf("%d", aws.ToInt32(scaling.MaxSize))
+	}
+	attributes = dropEmpty(attributes)
+	setTagMap(attributes, nodegroup.Tags)
+
+	return StandardizedResource{Provider: "aws", Service: "eksnodegroup", Region: region, ID: aws.ToString(nodegroup.NodegroupArn), Name: aws.ToString(nodegroup.NodegroupName), Attributes: attributes}
+}
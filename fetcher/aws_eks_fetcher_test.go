@@ -0,0 +1,124 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const eksHost = "eks.us-east-1.amazonaws.com"
+
+const (
+	eksARN         = "arn:aws:eks:us-east-1:123456789012:"
+	eksProdCluster = eksARN + "cluster/prod"
+	eksGeneral     = eksARN + "nodegroup/prod/general/0ac6f2f1-example"
+	eksSpot        = eksARN + "nodegroup/prod/spot/1bd7a3c2-example"
+)
+
+func serveEKS(f *fakeCloud) {
+	f.handle(eksHost, "GET /clusters", "aws/eks/list_clusters.json")
+	f.handle(eksHost, "GET /clusters/prod", "aws/eks/describe_cluster.json")
+	f.handle(eksHost, "GET /clusters/prod/node-groups", "aws/eks/list_nodegroups.json")
+	f.handle(eksHost, "GET /clusters/prod/node-groups/general", "aws/eks/describe_nodegroup_general.json")
+	f.handle(eksHost, "GET /clusters/prod/node-groups/spot", "aws/eks/describe_nodegroup_spot.json")
+}
+
+func TestFetchEKSClusters(t *testing.T) {
+	f := newFakeCloud(t)
+	serveEKS(f)
+
+	resources, err := FetchEKSClusters(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchEKSClusters() error = %v", err)
+	}
+	index := resourceIndex(resources)
+
+	tests := []struct {
+		key   string
+		name  string
+		attrs map[string]string
+	}{
+		{"ekscluster/" + eksProdCluster, "prod", map[string]string{
+			"arn":              eksProdCluster,
+			"version":          "1.29",
+			"platform_version": "eks.8",
+			"status":           "active",
+			"endpoint":         "https://A1B2C3D4E5F6.gr7.us-east-1.eks.amazonaws.com",
+			"role":             "arn:aws:iam::123456789012:role/eks-cluster-role",
+			"vpc_id":           "vpc-0a1b2c3d4e5f60001",
+			"subnets":          "subnet-0aaa000000000001, subnet-0bbb000000000002",
+			"security_groups":  "sg-0ekscluster00001, sg-0eks000000000001",
+			"public_endpoint":  "true",
+			"private_endpoint": "false",
+			"tag:team":         "platform",
+		}},
+		{"eksnodegroup/" + eksGeneral, "general", map[string]string{
+			"parent_id":       eksProdCluster,
+			"arn":             eksGeneral,
+			"version":         "1.29",
+			"release_version": "1.29.0-20240129",
+			"status":          "active",
+			"instance_types":  "m5.large",
+			"capacity_type":   "ON_DEMAND",
+			"ami_type":        "AL2_x86_64",
+			"subnets":         "subnet-0aaa000000000001, subnet-0bbb000000000002",
+			"role":            "arn:aws:iam::123456789012:role/eks-node-role",
+			"min_size":        "2",
+			"desired_size":    "3",
+			"max_size":        "6",
+		}},
+		{"eksnodegroup/" + eksSpot, "spot", map[string]string{
+			"parent_id":       eksProdCluster,
+			"arn":             eksSpot,
+			"version":         "1.28",
+			"release_version": "1.28.5-20240110",
+			"status":          "degraded",
+			"instance_types":  "c5.large, c5a.large",
+			"capacity_type":   "SPOT",
+			"ami_type":        "BOTTLEROCKET_x86_64",
+			"subnets":         "subnet-0bbb000000000002",
+			"role":            "arn:aws:iam::123456789012:role/eks-node-role",
+			"min_size":        "0",
+			"desired_size":    "0",
+			"max_size":        "10",
+			"tag:cost-center": "batch",
+		}},
+	}
+	if len(resources) != len(tests) {
+		t.Errorf("Expected %d resources, got %v", len(tests), resourceKeys(resources))
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			res, ok := index[tt.key]
+			if !ok {
+				t.Fatalf("Missing resource %s", tt.key)
+			}
+			if res.Provider != "aws" || res.Name != tt.name || res.Region != "us-east-1" {
+				t.Errorf("Unexpected resource %+v", res)
+			}
+			if !reflect.DeepEqual(res.Attributes, tt.attrs) {
+				t.Errorf("Attributes mismatch\n got: %v\nwant: %v", res.Attributes, tt.attrs)
+			}
+		})
+	}
+}
+
+func TestFetchEKSClustersNodegroupsDenied(t *testing.T) {
+	f := newFakeCloud(t)
+	f.handle(eksHost, "GET /clusters", "aws/eks/list_clusters.json")
+	f.handle(eksHost, "GET /clusters/prod", "aws/eks/describe_cluster.json")
+	f.respond(eksHost, "GET /clusters/prod/node-groups", http.StatusForbidden, "aws/errors/eks_access_denied.json")
+
+	resources, err := FetchEKSClusters(t.Context(), f.awsConfig("us-east-1"))
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+	if failed := FailedServices(err); len(failed) != 1 || failed["eksnodegroup"] == nil {
+		t.Errorf("Expected only eksnodegroup to fail, got %v", failed)
+	}
+	// The cluster is still returned.
+	want := []string{"ekscluster/" + eksProdCluster}
+	if got := resourceKeys(resources); !reflect.DeepEqual(got, want) {
+		t.Errorf("Resources mismatch\n got: %v\nwant: %v", got, want)
+	}
+}
@@ -0,0 +1,603 @@
+// fetcher/aws_elb_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	elb "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/wafv2"
+	waftypes "github.com/aws/aws-sdk-go-v2/service/wafv2/types"
+)
+
+func init() {
+	Register(NewFetcher("aws-elb", "aws", ScopeProject, []string{"elb", "elblistener", "elbrule", "elbtargetgroup", "wafwebacl"}, regionalAWSFetcher(FetchLoadBalancers)))
+}
+
+// FetchLoadBalancers collects the Application, Network and Gateway Load
+// Balancers in the region of cfg with their listeners, listener rules and
+// target groups, and the regional WAF web ACLs. All of them are identified
+// by their ARN. A listener's "parent_id" is its load balancer, a rule's its
+// listener; "target_groups" attributes hold target group ARNs and a web
+// ACL's "resources" the load balancers it protects. Failures are reported
+// per service as ServiceErrors.
+func FetchLoadBalancers(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	var errs []error
+	client := elb.NewFromConfig(cfg)
+	region := cfg.Region
+	log.Printf("   -> Fetching load balancers for AWS region: %s", region)
+
+	var loadBalancers []elbtypes.LoadBalancer
+	lbPages := elb.NewDescribeLoadBalancersPaginator(client, &elb.DescribeLoadBalancersInput{})
+	if err := eachPage(ctx, apiAWSELB, lbPages.HasMorePages, lbPages.NextPage, func(page *elb.DescribeLoadBalancersOutput) {
+		loadBalancers = append(loadBalancers, page.LoadBalancers...)
+	}); err != nil {
+		// Listeners and their rules are found through the load balancers.
+		err = fmt.Errorf("could not describe load balancers: %w", err)
+		errs = append(errs, &ServiceError{Service: "elb", Err: err}, &ServiceError{Service: "elblistener", Err: err}, &ServiceError{Service: "elbrule", Err: err})
+	}
+
+	var listenerErrs, ruleErrs []error
+	for _, lb := range loadBalancers {
+		resources = append(resources, elbLoadBalancerResource(lb, region))
+
+		var listeners []elbtypes.Listener
+		listenerPages := elb.NewDescribeListenersPaginator(client, &elb.DescribeListenersInput{LoadBalancerArn: lb.LoadBalancerArn})
+		if err := eachPage(ctx, apiAWSELB, listenerPages.HasMorePages, listenerPages.NextPage, func(page *elb.DescribeListenersOutput) {
+			listeners = append(listeners, page.Listeners...)
+		}); err != nil {
+			listenerErrs = append(listenerErrs, fmt.Errorf("could not describe listeners of %s: %w", aws.ToString(lb.LoadBalancerName), err))
+			ruleErrs = append(ruleErrs, fmt.Errorf("listeners of %s are unknown", aws.ToString(lb.LoadBalancerName)))
+			continue
+		}
+		for _, listener := range listeners {
+			// DescribeListeners only returns the default certificate, which
+			// stands in for the full list when that cannot be described.
+			certificates := listener.Certificates
+			if listener.Protocol == elbtypes.ProtocolEnumHttps || listener.Protocol == elbtypes.ProtocolEnumTls {
+				var all []elbtypes.Certificate
+				certPages := elb.NewDescribeListenerCertificatesPaginator(client, &elb.DescribeListenerCertificatesInput{ListenerArn: listener.ListenerArn})
+				if err := eachPage(ctx, apiAWSELB, certPages.HasMorePages, certPages.NextPage, func(page *elb.DescribeListenerCertificatesOutput) {
+					all = append(all, page.Certificates...)
+				}); err != nil {
+					listenerErrs = append(listenerErrs, fmt.Errorf("could not describe certificates of listener %s: %w", aws.ToString(listener.ListenerArn), err))
+				} else {
+					certificates = all
+				}
+			}
+			resources = append(resources, elbListenerResource(listener, certificates, region))
+
+			// Only Application Load Balancers have rules beyond the default action.
+			if lb.Type != elbtypes.LoadBalancerTypeEnumApplication {
+				continue
+			}
+			rulePages := elb.NewDescribeRulesPaginator(client, &elb.DescribeRulesInput{ListenerArn: listener.ListenerArn})
+			if err := eachPage(ctx, apiAWSELB, rulePages.HasMorePages, rulePages.NextPage, func(page *elb.DescribeRulesOutput) {
+				for _, rule := range page.Rules {
+					// The default rule repeats the listener's default action.
+					if !aws.ToBool(rule.IsDefault) {
+						resources = append(resources, elbRuleResource(rule, aws.ToString(listener.ListenerArn), region))
+					}
+				}
+			}); err != nil {
+				ruleErrs = append(ruleErrs, fmt.Errorf("could not describe rules of listener %s: %w", aws.ToString(listener.ListenerArn), err))
+			}
+		}
+	}
+	if err := errors.Join(listenerErrs...); err != nil {
+		errs = append(errs, &ServiceError{Service: "elblistener", Err: err})
+	}
+	if err := errors.Join(ruleErrs...); err != nil {
+		errs = append(errs, &ServiceError{Service: "elbrule", Err: err})
+	}
+
+	var targetGroupErrs []error
+	targetGroups := elb.NewDescribeTargetGroupsPaginator(client, &elb.DescribeTargetGroupsInput{})
+	if err := eachPage(ctx, apiAWSELB, targetGroups.HasMorePages, targetGroups.NextPage, func(page *elb.DescribeTargetGroupsOutput) {
+		for _, group := range page.TargetGroups {
+			health, err := callAPI(ctx, apiAWSELB, func() (*elb.DescribeTargetHealthOutput, error) {
+				return client.DescribeTargetHealth(ctx, &elb.DescribeTargetHealthInput{TargetGroupArn: group.TargetGroupArn})
+			})
+			if err != nil {
+				targetGroupErrs = append(targetGroupErrs, fmt.Errorf("could not describe targets of %s: %w", aws.ToString(group.TargetGroupName), err))
+				continue
+			}
+			resources = append(resources, elbTargetGroupResource(group, health.TargetHealthDescriptions, region))
+		}
+	}); err != nil {
+		targetGroupErrs = append(targetGroupErrs, fmt.Errorf("could not describe target groups: %w", err))
+	}
+	if err := errors.Join(targetGroupErrs...); err != nil {
+		errs = append(errs, &ServiceError{Service: "elbtargetgroup", Err: err})
+	}
+
+	acls, err := fetchWebACLs(ctx, wafv2.NewFromConfig(cfg), region)
+	resources = append(resources, acls...)
+	if err != nil {
+		errs = append(errs, &ServiceError{Service: "wafwebacl", Err: err})
+	}
+
+	log.Printf("Successfully fetched %d load balancer resources in %s.\n", len(resources), region)
+	return resources, errors.Join(errs...)
+}
+
+// fetchWebACLs collects the regional WAF web ACLs with their rules and the
+// Application Load Balancers they are associated with.
+func fetchWebACLs(ctx context.Context, client *wafv2.Client, region string) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	var errs []error
+	input := &wafv2.ListWebACLsInput{Scope: waftypes.ScopeRegional}
+	for {
+		page, err := callAPI(ctx, apiAWSWAF, func() (*wafv2.ListWebACLsOutput, error) {
+			return client.ListWebACLs(ctx, input)
+		})
+		if err != nil {
+			return resources, fmt.Errorf("could not list web ACLs: %w", err)
+		}
+		for _, summary := range page.WebACLs {
+			acl, err := callAPI(ctx, apiAWSWAF, func() (*wafv2.GetWebACLOutput, error) {
+				return client.GetWebACL(ctx, &wafv2.GetWebACLInput{Name: summary.Name, Id: summary.Id, Scope: waftypes.ScopeRegional})
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("could not get web ACL %s: %w", aws.ToString(summary.Name), err))
+				continue
+			}
+			associated, err := callAPI(ctx, apiAWSWAF, func() (*wafv2.ListResourcesForWebACLOutput, error) {
+				return client.ListResourcesForWebACL(ctx, &wafv2.ListResourcesForWebACLInput{WebACLArn: summary.ARN, ResourceType: waftypes.ResourceTypeApplicationLoadBalancer})
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("could not list resources of web ACL %s: %w", aws.ToString(summary.Name), err))
+				continue
+			}
+			resources = append(resources, wafWebACLResource(acl.WebACL, associated.ResourceArns, region))
+		}
+		if aws.ToString(page.NextMarker) == "" {
+			break
+		}
+		input.NextMarker = page.NextMarker
+	}
+	return resources, errors.Join(errs...)
+}
+
+func elbLoadBalancerResource(lb elbtypes.LoadBalancer, region string) StandardizedResource {
+	var subnets, zones, addresses []string
+	for _, zone := range lb.AvailabilityZones {
+		subnets = append(subnets, aws.ToString(zone.SubnetId))
+		zones = append(zones, aws.ToString(zone.ZoneName))
+		for _, address := range zone.LoadBalancerAddresses {
+			// Internet-facing NLBs have Elastic IPs, internal ones private addresses.
+			if ip := aws.ToString(address.IpAddress); ip != "" {
+				addresses = append(addresses, ip)
+			} else if ip := aws.ToString(address.PrivateIPv4Address); ip != "" {
+				addresses = append(addresses, ip)
+			}
+		}
+	}
+	attributes := map[string]string{
+		"type":               string(lb.Type),
+		"scheme":             string(lb.Scheme),
+		"dns_name":           aws.ToString(lb.DNSName),
+		"ip_address_type":    string(lb.IpAddressType),
+		"ip_addresses":       strings.Join(addresses, ", "),
+		"vpc_id":             aws.ToString(lb.VpcId),
+		"subnets":            strings.Join(subnets, ", "),
+		"availability_zones": strings.Join(zones, ", "),
+		"security_groups":    strings.Join(lb.SecurityGroups, ", "),
+	}
+	if lb.State != nil {
+		attributes["state"] = string(lb.State.Code)
+	}
+	if lb.CreatedTime != nil {
+		attributes["created"] = lb.CreatedTime.UTC().Format(time.RFC3339)
+	}
+	return StandardizedResource{Provider: "aws", Service: "elb", Region: region, ID: aws.ToString(lb.LoadBalancerArn), Name: aws.ToString(lb.LoadBalancerName), Attributes: dropEmpty(attributes)}
+}
+
+func elbListenerResource(listener elbtypes.Listener, certificates []elbtypes.Certificate, region string) StandardizedResource {
+	// The default certificate is listed first.
+	sort.SliceStable(certificates, func(i, j int) bool {
+		return aws.ToBool(certificates[i].IsDefault) && !aws.ToBool(certificates[j].IsDefault)
+	})
+	var certs []string
+	for _, cert := range certificates {
+		certs = append(certs, aws.ToString(cert.CertificateArn))
+	}
+	action, targetGroups := elbActions(listener.DefaultActions)
+	port := ""
+	if listener.Port != nil {
+		port = strconv.Itoa(int(*listener.Port))
+	}
+	attributes := map[string]string{
+		"parent_id":      aws.ToString(listener.LoadBalancerArn),
+		"protocol":       string(listener.Protocol),
+		"port":           port,
+		"certificates":   strings.Join(certs, ", "),
+		"ssl_policy":     aws.ToString(listener.SslPolicy),
+		"default_action": action,
+		"target_groups":  strings.Join(targetGroups, ", "),
+	}
+	name := string(listener.Protocol)
+	if port != "" {
+		name += ":" + port
+	}
+	return StandardizedResource{Provider: "aws", Service: "elblistener", Region: region, ID: aws.ToString(listener.ListenerArn), Name: name, Attributes: dropEmpty(attributes)}
+}
+
+func elbRuleResource(rule elbtypes.Rule, listenerARN, region string) StandardizedResource {
+	var hosts, paths, others []string
+	for _, condition := range rule.Conditions {
+		switch field := aws.ToString(condition.Field); {
+		case field == "host-header" && condition.HostHeaderConfig != nil:
+			hosts = append(hosts, condition.HostHeaderConfig.Values...)
+		case field == "path-pattern" && condition.PathPatternConfig != nil:
+			paths = append(paths, condition.PathPatternConfig.Values...)
+		case field == "http-header" && condition.HttpHeaderConfig != nil:
+			others = append(others, aws.ToString(condition.HttpHeaderConfig.HttpHeaderName)+"="+strings.Join(condition.HttpHeaderConfig.Values, "|"))
+		case field == "http-request-method" && condition.HttpRequestMethodConfig != nil:
+			others = append(others, "method="+strings.Join(condition.HttpRequestMethodConfig.Values, "|"))
+		case field == "source-ip" && condition.SourceIpConfig != nil:
+			others = append(others, "source-ip="+strings.Join(condition.SourceIpConfig.Values, "|"))
+		case field == "query-string" && condition.QueryStringConfig != nil:
+			var pairs []string
+			for _, pair := range condition.QueryStringConfig.Values {
+				pairs = append(pairs, aws.ToString(pair.Key)+"="+aws.ToString(pair.Value))
+			}
+			others = append(others, "query="+strings.Join(pairs, "&"))
+		default:
+			// Conditions of older rules only carry Values.
+			if field == "host-header" {
+				hosts = append(hosts, condition.Values...)
+			} else if field == "path-pattern" {
+				paths = append(paths, condition.Values...)
+			}
+		}
+	}
+	action, targetGroups := elbActions(rule.Actions)
+	attributes := map[string]string{
+		"parent_id":     listenerARN,
+		"priority":      aws.ToString(rule.Priority),
+		"hosts":         strings.Join(hosts, ", "),
+		"paths":         strings.Join(paths, ", "),
+		"conditions":    strings.Join(others, ", "),
+		"action":        action,
+		"target_groups": strings.Join(targetGroups, ", "),
+	}
+	return StandardizedResource{Provider: "aws", Service: "elbrule", Region: region, ID: aws.ToString(rule.RuleArn), Name: aws.ToString(rule.Priority), Attributes: dropEmpty(attributes)}
+}
+
+func elbTargetGroupResource(group elbtypes.TargetGroup, health []elbtypes.TargetHealthDescription, region string) StandardizedResource {
+	var targets []string
+	for _, target := range health {
+		if target.Target == nil {
+			continue
+		}
+		id := aws.ToString(target.Target.Id)
+		if target.Target.Port != nil {
+			id += ":" + strconv.Itoa(int(*target.Target.Port))
+		}
+		if target.TargetHealth != nil && target.TargetHealth.State != "" {
+			id += " (" + string(target.TargetHealth.State) + ")"
+		}
+		targets = append(targets, id)
+	}
+	attributes := map[string]string{
+		"protocol":       string(group.Protocol),
+		"target_type":    string(group.TargetType),
+		"vpc_id":         aws.ToString(group.VpcId),
+		"load_balancers": strings.Join(group.LoadBalancerArns, ", "),
+		"targets":        strings.Join(targets, ", "),
+	}
+	if group.Port != nil {
+		attributes["port"] = strconv.Itoa(int(*group.Port))
+	}
+	if aws.ToBool(group.HealthCheckEnabled) {
+		attributes["health_check"] = strings.TrimSpace(string(group.HealthCheckProtocol) + " " + aws.ToString(group.HealthCheckPath))
+	}
+	return StandardizedResource{Provider: "aws", Service: "elbtargetgroup", Region: region, ID: aws.ToString(group.TargetGroupArn), Name: aws.ToString(group.TargetGroupName), Attributes: dropEmpty(attributes)}
+}
+
+// wafWebACLResource records each rule as "rule:<priority>:name",
+// "rule:<priority>:action" and "rule:<priority>:match" attributes.
+func wafWebACLResource(acl *waftypes.WebACL, associated []string, region string) StandardizedResource {
+	attributes := map[string]string{
+		"resources": strings.Join(associated, ", "),
+	}
+	if acl.DefaultAction != nil {
+		attributes["default_action"] = "allow"
+		if acl.DefaultAction.Block != nil {
+			attributes["default_action"] = "block"
+		}
+	}
+	for _, rule := range acl.Rules {
+		prefix := fmt.Sprintf("rule:%d:", rule.Priority)
+		attributes[prefix+"name"] = aws.ToString(rule.Name)
+		attributes[prefix+"action"] = wafRuleAction(rule)
+		attributes[prefix+"match"] = wafStatement(rule.Statement)
+	}
+	return StandardizedResource{Provider: "aws", Service: "wafwebacl", Region: region, ID: aws.ToString(acl.ARN), Name: aws.ToString(acl.Name), Attributes: dropEmpty(attributes)}
+}
+
+// elbActions describes the actions of a listener or rule, e.g.
+// "authenticate-oidc, forward" or "redirect to HTTPS:443", and returns the
+// ARNs of the target groups it forwards to.
+func elbActions(actions []elbtypes.Action) (string, []string) {
+	sort.SliceStable(actions, func(i, j int) bool {
+		return aws.ToInt32(actions[i].Order) < aws.ToInt32(actions[j].Order)
+	})
+	var parts, targetGroups []string
+	for _, action := range actions {
+		part := string(action.Type)
+		switch action.Type {
+		case elbtypes.ActionTypeEnumForward:
+			if arn := aws.ToString(action.TargetGroupArn); arn != "" {
+				targetGroups = append(targetGroups, arn)
+			} else if action.ForwardConfig != nil {
+				for _, group := range action.ForwardConfig.TargetGroups {
+					targetGroups = append(targetGroups, aws.ToString(group.TargetGroupArn))
+				}
+			}
+		case elbtypes.ActionTypeEnumRedirect:
+			if redirect := action.RedirectConfig; redirect != nil {
+				part += " to " + strings.TrimSuffix(aws.ToString(redirect.Protocol)+":"+aws.ToString(redirect.Port), ":")
+			}
+		case elbtypes.ActionTypeEnumFixedResponse:
+			if response := action.FixedResponseConfig; response != nil {
+				part += " " + aws.ToString(response.StatusCode)
+			}
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", "), targetGroups
+}
+
+// wafRuleAction returns the action of a rule, or how the actions of the rule
+// group it references are overridden.
+func wafRuleAction(rule waftypes.Rule) string {
+	if action := rule.Action; action != nil {
+		switch {
+		case action.Allow != nil:
+			return "allow"
+		case action.Block != nil:
+			return "block"
+		case action.Count != nil:
+			return "count"
+		case action.Captcha != nil:
+			return "captcha"
+		case action.Challenge != nil:
+			return "challenge"
+		}
+	}
+	if override := rule.OverrideAction; override != nil && override.Count != nil {
+		return "count"
+	}
+	return "rule group"
+}
+
+// wafStatement summarizes what a rule statement matches.
+func wafStatement(statement *waftypes.Statement) string {
+	switch {
+	case statement == nil:
+		return ""
+	case statement.ManagedRuleGroupStatement != nil:
+		group := statement.ManagedRuleGroupStatement
+		return "managed rule group " + aws.ToString(group.VendorName) + "/" + aws.ToString(group.Name)
+	case statement.RuleGroupReferenceStatement != nil:
+		return "rule group " + nameFromARN(aws.ToString(statement.RuleGroupReferenceStatement.ARN))
+	case statement.RateBasedStatement != nil:
+		rate := statement.RateBasedStatement
+		window := rate.EvaluationWindowSec
+		if window == 0 {
+			window = 300
+		}
+		return fmt.Sprintf("more than %d requests per %ds by %s", aws.ToInt64(rate.Limit), window, rate.AggregateKeyType)
+	case statement.IPSetReferenceStatement != nil:
+		return "IP set " + nameFromARN(aws.ToString(statement.IPSetReferenceStatement.ARN))
+	case statement.GeoMatchStatement != nil:
+		var countries []string
+		for _, code := range statement.GeoMatchStatement.CountryCodes {
+			countries = append(countries, string(code))
+		}
+		return "country in " + strings.Join(countries, ", ")
+	case statement.NotStatement != nil:
+		return "not (" + wafStatement(statement.NotStatement.Statement) + ")"
+	case statement.AndStatement != nil:
+		return wafStatements(statement.AndStatement.Statements, " and ")
+	case statement.OrStatement != nil:
+		return wafStatements(statement.OrStatement.Statements, " or ")
+	case statement.ByteMatchStatement != nil:
+		return "byte match"
+	case statement.RegexMatchStatement != nil, statement.RegexPatternSetReferenceStatement != nil:
+		return "regex match"
+	case statement.SqliMatchStatement != nil:
+		return "SQL injection"
+	case statement.XssMatchStatement != nil:
+		return "cross-site scripting"
+	case statement.SizeConstraintStatement != nil:
+		return "size constraint"
+	case statement.LabelMatchStatement != nil:
+		return "label " + aws.ToString(statement.LabelMatchStatement.Key)
+	case statement.AsnMatchStatement != nil:
+		return "ASN match"
+	}
+	return ""
+}
+
+func wafStatements(statements []waftypes.Statement, separator string) string {
+	var parts []string
+	for i := range statements {
+		parts = append(parts, "("+wafStatement(&statements[i])+")")
+	}
+	return strings.Join(parts, separator)
+}
+
+// nameFromARN returns the name in an ARN ending in "<name>/<id>", such as
+// "arn:aws:wafv2:us-east-1:123456789012:regional/ipset/blocked/a1b2c3" or
+// "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/web/73e2d6bc24d8a067".
+func nameFromARN(arn string) string {
+	parts := strings.Split(arn, "/")
+	if len(parts) < 2 {
+		return arn
+	}
+	return parts[len(parts)-2]
+}
+
+// AWSLoadBalancerFlows traces the load balancers of an AWS account, as
+// collected by FetchLoadBalancers, into one LoadBalancerFlow per listener:
+// the listener is the frontend, its rules the routing rules and the target
+// group of its default action the backend. Targets are resolved to the ECS
+// services and Lambda functions they belong to, and a web ACL protecting
+// the load balancer is reported as its CloudArmor policy.
+func AWSLoadBalancerFlows(resources []StandardizedResource, accountID string) []LoadBalancerFlow {
+	byID := make(map[string]StandardizedResource)
+	children := make(map[string][]StandardizedResource)
+	webACLs := make(map[string]StandardizedResource)
+	ecsServices := make(map[string]StandardizedResource)
+	var listeners []StandardizedResource
+	for _, res := range resources {
+		if res.Provider != "aws" || res.Attributes["account_id"] != accountID {
+			continue
+		}
+		switch res.Service {
+		case "elb", "elbtargetgroup", "lambda":
+			byID[res.ID] = res
+			if arn := res.Attributes["arn"]; arn != "" {
+				byID[arn] = res
+			}
+		case "elblistener":
+			listeners = append(listeners, res)
+		case "elbrule":
+			children[res.Attributes["parent_id"]] = append(children[res.Attributes["parent_id"]], res)
+		case "wafwebacl":
+			for _, arn := range splitList(res.Attributes["resources"]) {
+				webACLs[arn] = res
+			}
+		case "ecsservice":
+			for _, arn := range splitList(res.Attributes["target_groups"]) {
+				ecsServices[arn] = res
+			}
+		}
+	}
+
+	backend := func(targetGroups string) *BackendConfig {
+		arns := splitList(targetGroups)
+		if len(arns) == 0 {
+			return nil
+		}
+		// Of weighted target groups, the first one is traced.
+		group, ok := byID[arns[0]]
+		if !ok {
+			return &BackendConfig{Name: nameFromARN(arns[0])}
+		}
+		config := &BackendConfig{Name: group.Name, Region: group.Region, Targets: splitList(group.Attributes["targets"])}
+		switch group.Attributes["target_type"] {
+		case "instance":
+			config.Type = "EC2"
+		case "ip":
+			config.Type = "IP"
+		case "lambda":
+			config.Type = "Lambda"
+			if len(config.Targets) > 0 {
+				if function, ok := byID[strings.Fields(config.Targets[0])[0]]; ok {
+					config.ServiceName = function.Name
+				}
+			}
+		case "alb":
+			config.Type = "ALB"
+		}
+		if service, ok := ecsServices[group.ID]; ok {
+			config.Type = "ECS"
+			config.ServiceName = ecsNameFromARN(service.Attributes["parent_id"]) + "/" + service.Name
+		}
+		return config
+	}
+
+	var flows []LoadBalancerFlow
+	for _, listener := range listeners {
+		lb, ok := byID[listener.Attributes["parent_id"]]
+		if !ok {
+			continue
+		}
+		flow := LoadBalancerFlow{
+			Name:      lb.Name + " " + listener.Name,
+			Provider:  "aws",
+			ProjectID: accountID,
+			Frontend: FrontendConfig{
+				IPAddress:           lb.Attributes["ip_addresses"],
+				DNSName:             lb.Attributes["dns_name"],
+				PortRange:           listener.Attributes["port"],
+				Protocol:            listener.Attributes["protocol"],
+				Certificates:        splitList(listener.Attributes["certificates"]),
+				SSLPolicy:           listener.Attributes["ssl_policy"],
+				LoadBalancingScheme: lb.Attributes["scheme"],
+			},
+		}
+		rules := children[listener.ID]
+		sort.Slice(rules, func(i, j int) bool {
+			a, _ := strconv.Atoi(rules[i].Attributes["priority"])
+			b, _ := strconv.Atoi(rules[j].Attributes["priority"])
+			return a < b
+		})
+		for _, rule := range rules {
+			flow.RoutingRules = append(flow.RoutingRules, RoutingRule{
+				Hosts:    splitList(rule.Attributes["hosts"]),
+				Paths:    splitList(rule.Attributes["paths"]),
+				Priority: rule.Attributes["priority"],
+				Action:   rule.Attributes["action"],
+				Backend:  backend(rule.Attributes["target_groups"]),
+			})
+		}
+		// Requests no rule matches take the listener's default action.
+		defaultRule := RoutingRule{Priority: "default", Action: listener.Attributes["default_action"], Backend: backend(listener.Attributes["target_groups"])}
+		flow.RoutingRules = append(flow.RoutingRules, defaultRule)
+		if defaultRule.Backend != nil {
+			flow.Backend = *defaultRule.Backend
+		}
+		if acl, ok := webACLs[lb.ID]; ok {
+			flow.CloudArmor = webACLPolicy(acl)
+		}
+		flows = append(flows, flow)
+	}
+	sort.Slice(flows, func(i, j int) bool { return flows[i].Name < flows[j].Name })
+	return flows
+}
+
+// webACLPolicy converts a web ACL collected by FetchLoadBalancers into a
+// CloudArmorPolicy with its rules in order of priority.
+func webACLPolicy(acl StandardizedResource) CloudArmorPolicy {
+	policy := CloudArmorPolicy{Name: acl.Name, DefaultAction: acl.Attributes["default_action"]}
+	for key, name := range acl.Attributes {
+		rest, ok := strings.CutPrefix(key, "rule:")
+		if !ok {
+			continue
+		}
+		priority, ok := strings.CutSuffix(rest, ":name")
+		if !ok {
+			continue
+		}
+		p, err := strconv.ParseInt(priority, 10, 64)
+		if err != nil {
+			continue
+		}
+		prefix := "rule:" + priority + ":"
+		policy.Rules = append(policy.Rules, CloudArmorRule{Priority: p, Action: acl.Attributes[prefix+"action"], Description: name, Match: acl.Attributes[prefix+"match"]})
+	}
+	sort.Slice(policy.Rules, func(i, j int) bool { return policy.Rules[i].Priority < policy.Rules[j].Priority })
+	return policy
+}
+
+// splitList splits an attribute joined with ", ".
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ", ")
+}
@@ -0,0 +1,319 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const (
+	elbHost = "elasticloadbalancing.us-east-1.amazonaws.com"
+	wafHost = "wafv2.us-east-1.amazonaws.com"
+
+	elbARN        = "arn:aws:elasticloadbalancing:us-east-1:123456789012:"
+	webALB        = elbARN + "loadbalancer/app/web-alb/50dc6c495c0c9188"
+	dbNLB         = elbARN + "loadbalancer/net/db-nlb/7a1f2e3d4c5b6a79"
+	httpListener  = elbARN + "listener/app/web-alb/50dc6c495c0c9188/f2f7dc8efc522ab2"
+	httpsListener = elbARN + "listener/app/web-alb/50dc6c495c0c9188/0467ef3c8400ae65"
+	tcpListener   = elbARN + "listener/net/db-nlb/7a1f2e3d4c5b6a79/9c8b7a6f5e4d3c2b"
+	apiRule       = elbARN + "listener-rule/app/web-alb/50dc6c495c0c9188/0467ef3c8400ae65/3b8e2a9c1d4f5e67"
+	maintRule     = elbARN + "listener-rule/app/web-alb/50dc6c495c0c9188/0467ef3c8400ae65/9683b2d02a6cabee"
+	webTG         = elbARN + "targetgroup/web/73e2d6bc24d8a067"
+	apiTG         = elbARN + "targetgroup/api/5a4b3c2d1e0f9a8b"
+	dbProxyTG     = elbARN + "targetgroup/db-proxy/1f2e3d4c5b6a7980"
+	webACL        = "arn:aws:wafv2:us-east-1:123456789012:regional/webacl/web-acl/a1b2c3d4-5678-90ab-cdef-111111111111"
+	wwwCert       = "arn:aws:acm:us-east-1:123456789012:certificate/3f6a1c2e-www"
+	apiCert       = "arn:aws:acm:us-east-1:123456789012:certificate/9b2d4e6f-api"
+)
+
+// serveELB serves two load balancers: web-alb with an HTTP listener that
+// redirects to its HTTPS listener, and db-nlb with a TCP listener.
+func serveELB(f *fakeCloud) {
+	f.handle(elbHost, "DescribeLoadBalancers", "aws/elb/describe_load_balancers.xml")
+	// Listeners, rules and targets are described in the order of their parents.
+	f.handle(elbHost, "DescribeListeners", "aws/elb/describe_listeners_web_alb.xml")
+	f.handle(elbHost, "DescribeListeners", "aws/elb/describe_listeners_db_nlb.xml")
+	f.handle(elbHost, "DescribeRules", "aws/elb/describe_rules_http.xml")
+	f.handle(elbHost, "DescribeRules", "aws/elb/describe_rules_https.xml")
+	f.handle(elbHost, "DescribeListenerCertificates", "aws/elb/describe_listener_certificates.xml")
+	f.handle(elbHost, "DescribeTargetGroups", "aws/elb/describe_target_groups.xml")
+	f.handle(elbHost, "DescribeTargetHealth", "aws/elb/describe_target_health_web.xml")
+	f.handle(elbHost, "DescribeTargetHealth", "aws/elb/describe_target_health_api.xml")
+	f.handle(elbHost, "DescribeTargetHealth", "aws/elb/describe_target_health_db_proxy.xml")
+}
+
+func serveWAF(f *fakeCloud) {
+	f.handle(wafHost, "AWSWAF_20190729.ListWebACLs", "aws/wafv2/list_web_acls.json")
+	f.handle(wafHost, "AWSWAF_20190729.GetWebACL", "aws/wafv2/get_web_acl.json")
+	f.handle(wafHost, "AWSWAF_20190729.ListResourcesForWebACL", "aws/wafv2/list_resources_for_web_acl.json")
+}
+
+func TestFetchLoadBalancers(t *testing.T) {
+	f := newFakeCloud(t)
+	serveELB(f)
+	serveWAF(f)
+
+	resources, err := FetchLoadBalancers(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchLoadBalancers() error = %v", err)
+	}
+	index := resourceIndex(resources)
+
+	tests := []struct {
+		key   string
+		name  string
+		attrs map[string]string
+	}{
+		{"elb/" + webALB, "web-alb", map[string]string{
+			"type":               "application",
+			"scheme":             "internet-facing",
+			"dns_name":           "web-alb-1234567890.us-east-1.elb.amazonaws.com",
+			"ip_address_type":    "ipv4",
+			"vpc_id":             "vpc-0a1b2c3d4e5f60001",
+			"subnets":            "subnet-0aaa000000000001, subnet-0bbb000000000002",
+			"availability_zones": "us-east-1a, us-east-1b",
+			"security_groups":    "sg-0alb000000000001",
+			"state":              "active",
+			"created":            "2023-04-12T09:30:00Z",
+		}},
+		{"elb/" + dbNLB, "db-nlb", map[string]string{
+			"type":               "network",
+			"scheme":             "internal",
+			"dns_name":           "db-nlb-7a1f2e3d4c5b6a79.elb.us-east-1.amazonaws.com",
+			"ip_address_type":    "ipv4",
+			"ip_addresses":       "10.0.1.200",
+			"vpc_id":             "vpc-0a1b2c3d4e5f60001",
+			"subnets":            "subnet-0aaa000000000001",
+			"availability_zones": "us-east-1a",
+			"state":              "active",
+			"created":            "2022-10-03T14:00:00Z",
+		}},
+		{"elblistener/" + httpListener, "HTTP:80", map[string]string{
+			"parent_id":      webALB,
+			"protocol":       "HTTP",
+			"port":           "80",
+			"default_action": "redirect to HTTPS:443",
+		}},
+		{"elblistener/" + httpsListener, "HTTPS:443", map[string]string{
+			"parent_id":      webALB,
+			"protocol":       "HTTPS",
+			"port":           "443",
+			"certificates":   wwwCert + ", " + apiCert,
+			"ssl_policy":     "ELBSecurityPolicy-TLS13-1-2-2021-06",
+			"default_action": "forward",
+			"target_groups":  webTG,
+		}},
+		{"elblistener/" + tcpListener, "TCP:5432", map[string]string{
+			"parent_id":      dbNLB,
+			"protocol":       "TCP",
+			"port":           "5432",
+			"default_action": "forward",
+			"target_groups":  dbProxyTG,
+		}},
+		{"elbrule/" + apiRule, "10", map[string]string{
+			"parent_id":     httpsListener,
+			"priority":      "10",
+			"hosts":         "api.example.com",
+			"paths":         "/v1/*, /v2/*",
+			"conditions":    "method=GET|POST",
+			"action":        "forward",
+			"target_groups": apiTG,
+		}},
+		{"elbrule/" + maintRule, "20", map[string]string{
+			"parent_id": httpsListener,
+			"priority":  "20",
+			"paths":     "/maintenance",
+			"action":    "fixed-response 503",
+		}},
+		{"elbtargetgroup/" + webTG, "web", map[string]string{
+			"protocol":       "HTTP",
+			"port":           "8080",
+			"target_type":    "ip",
+			"vpc_id":         "vpc-0a1b2c3d4e5f60001",
+			"health_check":   "HTTP /healthz",
+			"load_balancers": webALB,
+			"targets":        "10.0.1.15:8080 (healthy), 10.0.2.31:8080 (unhealthy)",
+		}},
+		{"elbtargetgroup/" + apiTG, "api", map[string]string{
+			"target_type":    "lambda",
+			"load_balancers": webALB,
+			"targets":        "arn:aws:lambda:us-east-1:123456789012:function:orders-api (unavailable)",
+		}},
+		{"elbtargetgroup/" + dbProxyTG, "db-proxy", map[string]string{
+			"protocol":       "TCP",
+			"port":           "5432",
+			"target_type":    "instance",
+			"vpc_id":         "vpc-0a1b2c3d4e5f60001",
+			"health_check":   "TCP",
+			"load_balancers": dbNLB,
+			"targets":        "i-0abc123def4567890:6432 (healthy)",
+		}},
+		{"wafwebacl/" + webACL, "web-acl", map[string]string{
+			"resources":      webALB,
+			"default_action": "allow",
+			"rule:0:name":    "block-bad-ips",
+			"rule:0:action":  "block",
+			"rule:0:match":   "IP set blocked",
+			"rule:2:name":    "rate-limit",
+			"rule:2:action":  "count",
+			"rule:2:match":   "more than 2000 requests per 300s by IP",
+			"rule:10:name":   "AWS-AWSManagedRulesCommonRuleSet",
+			"rule:10:action": "rule group",
+			"rule:10:match":  "managed rule group AWS/AWSManagedRulesCommonRuleSet",
+		}},
+	}
+	if len(resources) != len(tests) {
+		t.Errorf("Expected %d resources, got %v", len(tests), resourceKeys(resources))
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			res, ok := index[tt.key]
+			if !ok {
+				t.Fatalf("Missing resource %s", tt.key)
+			}
+			if res.Provider != "aws" || res.Name != tt.name || res.Region != "us-east-1" {
+				t.Errorf("Unexpected resource %+v", res)
+			}
+			if !reflect.DeepEqual(res.Attributes, tt.attrs) {
+				t.Errorf("Attributes mismatch\n got: %v\nwant: %v", res.Attributes, tt.attrs)
+			}
+		})
+	}
+}
+
+func TestFetchLoadBalancersWAFDenied(t *testing.T) {
+	f := newFakeCloud(t)
+	serveELB(f)
+	f.respond(wafHost, "AWSWAF_20190729.ListWebACLs", http.StatusBadRequest, "aws/errors/wafv2_access_denied.json")
+
+	resources, err := FetchLoadBalancers(t.Context(), f.awsConfig("us-east-1"))
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+	if failed := FailedServices(err); len(failed) != 1 || failed["wafwebacl"] == nil {
+		t.Errorf("Expected only wafwebacl to fail, got %v", failed)
+	}
+	// The load balancers are still returned.
+	if len(resources) != 10 {
+		t.Errorf("Expected 10 load balancer resources, got %v", resourceKeys(resources))
+	}
+}
+
+func TestFetchLoadBalancersListenerCertificatesDenied(t *testing.T) {
+	f := newFakeCloud(t)
+	f.respond(elbHost, "DescribeListenerCertificates", http.StatusForbidden, "aws/errors/elb_access_denied.xml")
+	serveELB(f)
+	serveWAF(f)
+
+	resources, err := FetchLoadBalancers(t.Context(), f.awsConfig("us-east-1"))
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+	if failed := FailedServices(err); len(failed) != 1 || failed["elblistener"] == nil {
+		t.Errorf("Expected only elblistener to fail, got %v", failed)
+	}
+	// The listener keeps its default certificate and its rules are still collected.
+	index := resourceIndex(resources)
+	if got := index["elblistener/"+httpsListener].Attributes["certificates"]; got != wwwCert {
+		t.Errorf("certificates = %q, want %q", got, wwwCert)
+	}
+	for _, rule := range []string{apiRule, maintRule} {
+		if _, ok := index["elbrule/"+rule]; !ok {
+			t.Errorf("Expected rule %s, got %v", rule, resourceKeys(resources))
+		}
+	}
+}
+
+func TestFetchLoadBalancersDenied(t *testing.T) {
+	f := newFakeCloud(t)
+	f.respond(elbHost, "DescribeLoadBalancers", http.StatusForbidden, "aws/errors/elb_access_denied.xml")
+	f.respond(elbHost, "DescribeTargetGroups", http.StatusForbidden, "aws/errors/elb_access_denied.xml")
+	serveWAF(f)
+
+	resources, err := FetchLoadBalancers(t.Context(), f.awsConfig("us-east-1"))
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+	failed := FailedServices(err)
+	for _, service := range []string{"elb", "elblistener", "elbrule", "elbtargetgroup"} {
+		if failed[service] == nil {
+			t.Errorf("Expected %s to fail, got %v", service, failed)
+		}
+	}
+	// The web ACL is still returned.
+	want := []string{"wafwebacl/" + webACL}
+	if got := resourceKeys(resources); !reflect.DeepEqual(got, want) {
+		t.Errorf("Resources mismatch\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func TestAWSLoadBalancerFlows(t *testing.T) {
+	f := newFakeCloud(t)
+	serveELB(f)
+	serveWAF(f)
+
+	resources, err := FetchLoadBalancers(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchLoadBalancers() error = %v", err)
+	}
+	resources = append(resources,
+		StandardizedResource{Provider: "aws", Service: "lambda", Region: "us-east-1", ID: "arn:aws:lambda:us-east-1:123456789012:function:orders-api", Name: "orders-api",
+			Attributes: map[string]string{"arn": "arn:aws:lambda:us-east-1:123456789012:function:orders-api"}},
+		StandardizedResource{Provider: "aws", Service: "ecsservice", Region: "us-east-1", ID: "arn:aws:ecs:us-east-1:123456789012:service/web/frontend", Name: "frontend",
+			Attributes: map[string]string{"parent_id": "arn:aws:ecs:us-east-1:123456789012:cluster/web", "target_groups": webTG}},
+	)
+	for i := range resources {
+		resources[i].Attributes["account_id"] = "123456789012"
+	}
+	// Resources of other accounts are ignored.
+	resources = append(resources, StandardizedResource{Provider: "aws", Service: "elblistener", ID: "other", Name: "HTTP:8080",
+		Attributes: map[string]string{"account_id": "444455556666", "parent_id": webALB}})
+
+	flows := AWSLoadBalancerFlows(resources, "123456789012")
+	var names []string
+	for _, flow := range flows {
+		names = append(names, flow.Name)
+	}
+	if want := []string{"db-nlb TCP:5432", "web-alb HTTP:80", "web-alb HTTPS:443"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Flows mismatch\n got: %v\nwant: %v", names, want)
+	}
+
+	web := &BackendConfig{Name: "web", Type: "ECS", ServiceName: "web/frontend", Region: "us-east-1", Targets: []string{"10.0.1.15:8080 (healthy)", "10.0.2.31:8080 (unhealthy)"}}
+	want := LoadBalancerFlow{
+		Name:      "web-alb HTTPS:443",
+		Provider:  "aws",
+		ProjectID: "123456789012",
+		Frontend: FrontendConfig{
+			DNSName:             "web-alb-1234567890.us-east-1.elb.amazonaws.com",
+			PortRange:           "443",
+			Protocol:            "HTTPS",
+			Certificates:        []string{wwwCert, apiCert},
+			SSLPolicy:           "ELBSecurityPolicy-TLS13-1-2-2021-06",
+			LoadBalancingScheme: "internet-facing",
+		},
+		RoutingRules: []RoutingRule{
+			{Hosts: []string{"api.example.com"}, Paths: []string{"/v1/*", "/v2/*"}, Priority: "10", Action: "forward",
+				Backend: &BackendConfig{Name: "api", Type: "Lambda", ServiceName: "orders-api", Region: "us-east-1",
+					Targets: []string{"arn:aws:lambda:us-east-1:123456789012:function:orders-api (unavailable)"}}},
+			{Paths: []string{"/maintenance"}, Priority: "20", Action: "fixed-response 503"},
+			{Priority: "default", Action: "forward", Backend: web},
+		},
+		Backend: *web,
+		CloudArmor: CloudArmorPolicy{Name: "web-acl", DefaultAction: "allow", Rules: []CloudArmorRule{
+			{Priority: 0, Action: "block", Description: "block-bad-ips", Match: "IP set blocked"},
+			{Priority: 2, Action: "count", Description: "rate-limit", Match: "more than 2000 requests per 300s by IP"},
+			{Priority: 10, Action: "rule group", Description: "AWS-AWSManagedRulesCommonRuleSet", Match: "managed rule group AWS/AWSManagedRulesCommonRuleSet"},
+		}},
+	}
+	if !reflect.DeepEqual(flows[2], want) {
+		t.Errorf("Flow mismatch\n got: %+v\nwant: %+v", flows[2], want)
+	}
+
+	// The redirecting listener has no backend, and the NLB no web ACL.
+	if redirect := flows[1]; redirect.Backend.Name != "" || len(redirect.RoutingRules) != 1 || redirect.RoutingRules[0].Action != "redirect to HTTPS:443" {
+		t.Errorf("Unexpected HTTP flow %+v", redirect)
+	}
+	if nlb := flows[0]; nlb.Frontend.IPAddress != "10.0.1.200" || nlb.Backend.Type != "EC2" || nlb.CloudArmor.Name != "" {
+		t.Errorf("Unexpected NLB flow %+v", nlb)
+	}
+}
@@ -6,20 +6,20 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
-	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
-		"github.com/aws/aws-sdk-go-v2/service/iam" // <-- This is the corrected line
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 )
 
-// FetchEC2Instances contains the logic to fetch all EC2 instances.
-func FetchEC2Instances() ([]StandardizedResource, error) {
-	var resources []StandardizedResource
+func init() {
+	Register(NewFetcher("aws-ec2", "aws", ScopeProject, []string{"ec2"}, regionalAWSFetcher(FetchEC2Instances)))
+}
 
-	cfg, err := config.LoadDefaultConfig(context.TODO())
-	if err != nil {
-		return nil, fmt.Errorf("failed to load configuration: %w", err)
-	}
+// FetchEC2Instances contains the logic to fetch all EC2 instances in the region of cfg.
+func FetchEC2Instances(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
 
 	client := ec2.NewFromConfig(cfg)
 	paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{})
@@ -27,33 +27,14 @@ func FetchEC2Instances() ([]StandardizedResource, error) {
 	log.Println("Fetching EC2 instances...")
 
 	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(context.TODO())
+		page, err := awsPage(ctx, apiAWSEC2, paginator.NextPage)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get a page of EC2 instances: %w", err)
 		}
 
 		for _, reservation := range page.Reservations {
 			for _, instance := range reservation.Instances {
-				instanceName := "N/A"
-				for _, tag := range instance.Tags {
-					if *tag.Key == "Name" {
-						instanceName = *tag.Value
-						break
-					}
-				}
-
-				resource := StandardizedResource{
-					Provider: "aws",
-					Service:  "ec2",
-					Region:   cfg.Region,
-					ID:       *instance.InstanceId,
-					Name:     instanceName,
-					Attributes: map[string]string{
-						"instance_type": string(instance.InstanceType),
-						"state":         string(instance.State.Name),
-					},
-				}
-				resources = append(resources, resource)
+				resources = append(resources, ec2InstanceResource(instance, cfg.Region))
 			}
 		}
 	}
@@ -61,71 +42,100 @@ func FetchEC2Instances() ([]StandardizedResource, error) {
 	return resources, nil
 }
 
-// FetchIAMRoles contains the logic to fetch all IAM roles and their policies.
-func FetchIAMRoles() ([]StandardizedResource, error) {
-	var resources []StandardizedResource
-
-	cfg, err := config.LoadDefaultConfig(context.TODO())
-	if err != nil {
-		return nil, fmt.Errorf("failed to load configuration: %w", err)
+// ec2InstanceResource converts an EC2 instance, recording its addresses,
+// placement, launch details and every tag (as "tag:<key>" attributes).
+func ec2InstanceResource(instance types.Instance, region string) StandardizedResource {
+	id := aws.ToString(instance.InstanceId)
+	var groups []string
+	for _, group := range instance.SecurityGroups {
+		groups = append(groups, aws.ToString(group.GroupId))
 	}
-
-	client := iam.NewFromConfig(cfg)
-	paginator := iam.NewListRolesPaginator(client, &iam.ListRolesInput{})
-
-	log.Println("Fetching IAM roles...")
-
-	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(context.TODO())
-		if err != nil {
-			return nil, fmt.Errorf("failed to get a page of IAM roles: %w", err)
+	attributes := map[string]string{
+		"instance_type":     string(instance.InstanceType),
+		"state":             "unknown",
+		"private_ip":        aws.ToString(instance.PrivateIpAddress),
+		"public_ip":         aws.ToString(instance.PublicIpAddress),
+		"private_dns":       aws.ToString(instance.PrivateDnsName),
+		"public_dns":        aws.ToString(instance.PublicDnsName),
+		"vpc_id":            aws.ToString(instance.VpcId),
+		"subnet_id":         aws.ToString(instance.SubnetId),
+		"security_groups":   strings.Join(groups, ", "),
+		"ami":               aws.ToString(instance.ImageId),
+		"key_name":          aws.ToString(instance.KeyName),
+		"platform":          aws.ToString(instance.PlatformDetails),
+		"architecture":      string(instance.Architecture),
+		"availability_zone": "",
+		"launch_time":       "",
+		"instance_profile":  "",
+	}
+	if instance.State != nil {
+		attributes["state"] = string(instance.State.Name)
+	}
+	if attributes["platform"] == "" {
+		// PlatformDetails is missing from older API responses; Platform is only set for Windows.
+		attributes["platform"] = "Linux/UNIX"
+		if instance.Platform != "" {
+			attributes["platform"] = string(instance.Platform)
 		}
+	}
+	if instance.Placement != nil {
+		attributes["availability_zone"] = aws.ToString(instance.Placement.AvailabilityZone)
+	}
+	if instance.LaunchTime != nil {
+		attributes["launch_time"] = instance.LaunchTime.UTC().Format(time.RFC3339)
+	}
+	if instance.IamInstanceProfile != nil {
+		attributes["instance_profile"] = aws.ToString(instance.IamInstanceProfile.Arn)
+	}
+	attributes = dropEmpty(attributes)
+	for _, tag := range instance.Tags {
+		attributes["tag:"+aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
 
-		for _, role := range page.Roles {
-			var policyNames []string
+	return StandardizedResource{
+		Provider:   "aws",
+		Service:    "ec2",
+		Region:     region,
+		ID:         id,
+		Name:       ec2InstanceName(instance),
+		Attributes: attributes,
+	}
+}
 
-			attachedPaginator := iam.NewListAttachedRolePoliciesPaginator(client, &iam.ListAttachedRolePoliciesInput{
-				RoleName: role.RoleName,
-			})
-			for attachedPaginator.HasMorePages() {
-				attachedPage, err := attachedPaginator.NextPage(context.TODO())
-				if err != nil {
-					log.Printf("could not list attached policies for role %s: %v", *role.RoleName, err)
-					continue
-				}
-				for _, policy := range attachedPage.AttachedPolicies {
-					policyNames = append(policyNames, *policy.PolicyName)
-				}
-			}
+// dropEmpty removes the attributes the API left unset, so that they are
+// missing from the cache rather than recorded as empty strings.
+func dropEmpty(attributes map[string]string) map[string]string {
+	for key, value := range attributes {
+		if value == "" {
+			delete(attributes, key)
+		}
+	}
+	return attributes
+}
 
-			inlinePaginator := iam.NewListRolePoliciesPaginator(client, &iam.ListRolePoliciesInput{
-				RoleName: role.RoleName,
-			})
-			for inlinePaginator.HasMorePages() {
-				inlinePage, err := inlinePaginator.NextPage(context.TODO())
-				if err != nil {
-					log.Printf("could not list inline policies for role %s: %v", *role.RoleName, err)
-					continue
-				}
-				for _, policyName := range inlinePage.PolicyNames {
-					policyNames = append(policyNames, policyName)
-				}
-			}
+// setTagMap records resource tags as "tag:<key>" attributes. Callers whose
+// SDK returns a list of key/value pairs convert it to a map first.
+func setTagMap(attributes map[string]string, tags map[string]string) {
+	for key, value := range tags {
+		attributes["tag:"+key] = value
+	}
+}
 
-			resource := StandardizedResource{
-				Provider: "aws",
-				Service:  "iam",
-				Region:   "global",
-				ID:       *role.Arn,
-				Name:     *role.RoleName,
-				Attributes: map[string]string{
-					"policies": strings.Join(policyNames, ", "),
-				},
-			}
-			resources = append(resources, resource)
+// ec2InstanceName picks a findable name for an instance: its Name tag, then
+// the Auto Scaling group or EKS node group that launched it, then its private
+// DNS name, and finally its ID.
+func ec2InstanceName(instance types.Instance) string {
+	tags := make(map[string]string)
+	for _, tag := range instance.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	for _, key := range []string{"Name", "aws:autoscaling:groupName", "eks:nodegroup-name"} {
+		if tags[key] != "" {
+			return tags[key]
 		}
 	}
-
-	log.Printf("Successfully fetched %d IAM roles.\n", len(resources))
-	return resources, nil
-}
\ No newline at end of file
+	if dns := aws.ToString(instance.PrivateDnsName); dns != "" {
+		return dns
+	}
+	return aws.ToString(instance.InstanceId)
+}
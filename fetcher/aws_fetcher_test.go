@@ -0,0 +1,189 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+const ec2Host = "ec2.us-east-1.amazonaws.com"
+
+func TestFetchEC2Instances(t *testing.T) {
+	type response struct {
+		operation string
+		status    int
+		file      string
+	}
+
+	tests := []struct {
+		name      string
+		responses []response
+		wantErr   bool
+		wantKeys  []string
+		wantCalls int
+	}{
+		{
+			name: "paginated",
+			responses: []response{
+				{"DescribeInstances", http.StatusOK, "aws/ec2/describe_instances_page1.xml"},
+				{"DescribeInstances?token=page-2", http.StatusOK, "aws/ec2/describe_instances_page2.xml"},
+			},
+			wantKeys:  []string{"ec2/i-0aaa1111bbbb22220", "ec2/i-0aaa1111bbbb22221", "ec2/i-0ccc3333dddd44440"},
+			wantCalls: 2,
+		},
+		{
+			name: "no instances",
+			responses: []response{
+				{"DescribeInstances", http.StatusOK, "aws/ec2/describe_instances_empty.xml"},
+			},
+			wantCalls: 1,
+		},
+		{
+			name: "throttled then succeeds",
+			responses: []response{
+				{"DescribeInstances", http.StatusServiceUnavailable, "aws/errors/ec2_request_limit_exceeded.xml"},
+				{"DescribeInstances", http.StatusOK, "aws/ec2/describe_instances_page1.xml"},
+				{"DescribeInstances?token=page-2", http.StatusOK, "aws/ec2/describe_instances_page2.xml"},
+			},
+			wantKeys:  []string{"ec2/i-0aaa1111bbbb22220", "ec2/i-0aaa1111bbbb22221", "ec2/i-0ccc3333dddd44440"},
+			wantCalls: 3,
+		},
+		{
+			name: "access denied",
+			responses: []response{
+				{"DescribeInstances", http.StatusForbidden, "aws/errors/ec2_unauthorized.xml"},
+			},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeCloud(t)
+			for _, r := range tt.responses {
+				f.respond(ec2Host, r.operation, r.status, r.file)
+			}
+
+			resources, err := FetchEC2Instances(t.Context(), f.awsConfig("us-east-1"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchEC2Instances() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !IsPermissionDenied(err) {
+				t.Errorf("Expected a permission error, got %v", err)
+			}
+			if got := resourceKeys(resources); !reflect.DeepEqual(got, tt.wantKeys) {
+				t.Errorf("Resources mismatch\n got: %v\nwant: %v", got, tt.wantKeys)
+			}
+			calls := f.calls(ec2Host, "DescribeInstances") + f.calls(ec2Host, "DescribeInstances?token=page-2")
+			if calls != tt.wantCalls {
+				t.Errorf("Expected %d DescribeInstances calls, got %d", tt.wantCalls, calls)
+			}
+		})
+	}
+}
+
+func TestFetchEC2InstancesAttributes(t *testing.T) {
+	f := newFakeCloud(t)
+	f.handle(ec2Host, "DescribeInstances", "aws/ec2/describe_instances_page1.xml")
+	f.handle(ec2Host, "DescribeInstances?token=page-2", "aws/ec2/describe_instances_page2.xml")
+
+	resources, err := FetchEC2Instances(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchEC2Instances() failed: %v", err)
+	}
+	index := resourceIndex(resources)
+
+	tests := []struct {
+		id, name, region, instanceType, state string
+	}{
+		{"i-0aaa1111bbbb22220", "web-1", "us-east-1", "t3.micro", "running"},
+		{"i-0aaa1111bbbb22221", "ip-10-0-2-20.ec2.internal", "us-east-1", "t3.small", "stopped"},
+		{"i-0ccc3333dddd44440", "batch-worker", "us-east-1", "m5.large", "running"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			res := index["ec2/"+tt.id]
+			if res.Provider != "aws" || res.Name != tt.name || res.Region != tt.region {
+				t.Errorf("Unexpected resource %+v", res)
+			}
+			if res.Attributes["instance_type"] != tt.instanceType || res.Attributes["state"] != tt.state {
+				t.Errorf("Unexpected attributes %v", res.Attributes)
+			}
+		})
+	}
+}
+
+func TestFetchEC2InstancesDetails(t *testing.T) {
+	f := newFakeCloud(t)
+	f.handle(ec2Host, "DescribeInstances", "aws/ec2/describe_instances_page1.xml")
+	f.handle(ec2Host, "DescribeInstances?token=page-2", "aws/ec2/describe_instances_page2.xml")
+
+	resources, err := FetchEC2Instances(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchEC2Instances() failed: %v", err)
+	}
+	index := resourceIndex(resources)
+
+	want := map[string]string{
+		"instance_type":     "t3.micro",
+		"state":             "running",
+		"private_ip":        "10.0.1.15",
+		"public_ip":         "54.210.12.34",
+		"private_dns":       "ip-10-0-1-15.ec2.internal",
+		"public_dns":        "ec2-54-210-12-34.compute-1.amazonaws.com",
+		"vpc_id":            "vpc-0aaa1111",
+		"subnet_id":         "subnet-0aaa1111",
+		"security_groups":   "sg-0aaa1111, sg-0bbb2222",
+		"ami":               "ami-0abcdef1234567890",
+		"key_name":          "ops-key",
+		"platform":          "Linux/UNIX",
+		"architecture":      "x86_64",
+		"availability_zone": "us-east-1a",
+		"launch_time":       "2024-03-01T09:30:00Z",
+		"instance_profile":  "arn:aws:iam::123456789012:instance-profile/web",
+		"tag:env":           "prod",
+		"tag:Name":          "web-1",
+	}
+	if got := index["ec2/i-0aaa1111bbbb22220"].Attributes; !reflect.DeepEqual(got, want) {
+		t.Errorf("Attributes mismatch\n got: %v\nwant: %v", got, want)
+	}
+
+	// Attributes the API did not return are left out rather than recorded empty.
+	want = map[string]string{
+		"instance_type": "t3.small",
+		"state":         "stopped",
+		"private_ip":    "10.0.2.20",
+		"private_dns":   "ip-10-0-2-20.ec2.internal",
+		"platform":      "windows",
+	}
+	if got := index["ec2/i-0aaa1111bbbb22221"].Attributes; !reflect.DeepEqual(got, want) {
+		t.Errorf("Attributes mismatch\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func TestEC2InstanceName(t *testing.T) {
+	tag := func(key, value string) types.Tag { return types.Tag{Key: aws.String(key), Value: aws.String(value)} }
+
+	tests := []struct {
+		name     string
+		instance types.Instance
+		want     string
+	}{
+		{"name tag", types.Instance{Tags: []types.Tag{tag("aws:autoscaling:groupName", "web-asg"), tag("Name", "web-1")}}, "web-1"},
+		{"auto scaling group", types.Instance{Tags: []types.Tag{tag("aws:autoscaling:groupName", "web-asg")}}, "web-asg"},
+		{"eks node group", types.Instance{Tags: []types.Tag{tag("eks:nodegroup-name", "general")}}, "general"},
+		{"empty name tag", types.Instance{Tags: []types.Tag{tag("Name", "")}, PrivateDnsName: aws.String("ip-10-0-0-1.ec2.internal")}, "ip-10-0-0-1.ec2.internal"},
+		{"instance id", types.Instance{InstanceId: aws.String("i-0123"), PrivateDnsName: aws.String("")}, "i-0123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ec2InstanceName(tt.instance); got != tt.want {
+				t.Errorf("ec2InstanceName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,329 @@
+// fetcher/aws_iam_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// now is the clock used for ages and expiry; tests replace it.
+var now = time.Now
+
+func init() {
+	Register(NewFetcher("aws-iam", "aws", ScopeProject, []string{"iam", "iamuser", "iamgroup", "iampolicy"}, globalAWSFetcher(FetchIAMResources)))
+}
+
+// FetchIAMResources collects the IAM roles (service "iam"), users, groups
+// and customer-managed policies of the account, all identified by their
+// ARN, from GetAccountAuthorizationDetails. Policy documents are stored as
+// compact JSON: a policy's default version in "document", inline policies
+// as "policy:<name>" and a role's trust policy in "trust_policy", whose
+// principals are summarized in "trusted_principals" and "trusted_accounts".
+// "managed_policies" lists the ARNs of attached policies; the AWS-managed
+// ones among them are collected too, with "aws_managed" set. Users also
+// record console access, MFA devices and, per access key, its status,
+// creation date, age in days at sync time and last use. Failures to read
+// those details or the AWS-managed policies are reported as ServiceErrors.
+func FetchIAMResources(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	var errs []error
+	client := iam.NewFromConfig(cfg)
+	log.Println("Fetching IAM roles, users, groups and policies...")
+
+	details := &iam.GetAccountAuthorizationDetailsOutput{}
+	input := &iam.GetAccountAuthorizationDetailsInput{
+		Filter: []types.EntityType{types.EntityTypeRole, types.EntityTypeUser, types.EntityTypeGroup, types.EntityTypeLocalManagedPolicy},
+	}
+	pages := iam.NewGetAccountAuthorizationDetailsPaginator(client, input)
+	if err := eachPage(ctx, apiAWSIAM, pages.HasMorePages, pages.NextPage, func(page *iam.GetAccountAuthorizationDetailsOutput) {
+		details.RoleDetailList = append(details.RoleDetailList, page.RoleDetailList...)
+		details.UserDetailList = append(details.UserDetailList, page.UserDetailList...)
+		details.GroupDetailList = append(details.GroupDetailList, page.GroupDetailList...)
+		details.Policies = append(details.Policies, page.Policies...)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get IAM authorization details: %w", err)
+	}
+
+	for _, role := range details.RoleDetailList {
+		resources = append(resources, iamRoleResource(role))
+	}
+
+	members := make(map[string][]string)
+	var userErrs []error
+	for _, user := range details.UserDetailList {
+		for _, group := range user.GroupList {
+			members[group] = append(members[group], aws.ToString(user.UserName))
+		}
+		res := iamUserResource(user)
+		if err := describeIAMUserCredentials(ctx, client, res.Attributes, user.UserName); err != nil {
+			userErrs = append(userErrs, fmt.Errorf("user %s: %w", res.Name, err))
+		}
+		resources = append(resources, res)
+	}
+	if err := errors.Join(userErrs...); err != nil {
+		errs = append(errs, &ServiceError{Service: "iamuser", Err: err})
+	}
+
+	for _, group := range details.GroupDetailList {
+		resources = append(resources, iamGroupResource(group, members[aws.ToString(group.GroupName)]))
+	}
+	for _, policy := range details.Policies {
+		resources = append(resources, iamPolicyResource(policy))
+	}
+
+	awsManaged, err := fetchAWSManagedPolicies(ctx, client)
+	resources = append(resources, awsManaged...)
+	if err != nil {
+		errs = append(errs, &ServiceError{Service: "iampolicy", Err: err})
+	}
+
+	log.Printf("Successfully fetched %d IAM resources.\n", len(resources))
+	return resources, errors.Join(errs...)
+}
+
+// fetchAWSManagedPolicies collects the default versions of the AWS-managed
+// policies attached in the account. GetAccountAuthorizationDetails would
+// return every AWS-managed policy in existence, so they are looked up one by one.
+func fetchAWSManagedPolicies(ctx context.Context, client *iam.Client) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	var errs []error
+	pages := iam.NewListPoliciesPaginator(client, &iam.ListPoliciesInput{Scope: types.PolicyScopeTypeAws, OnlyAttached: true})
+	if err := eachPage(ctx, apiAWSIAM, pages.HasMorePages, pages.NextPage, func(page *iam.ListPoliciesOutput) {
+		for _, policy := range page.Policies {
+			version, err := callAPI(ctx, apiAWSIAM, func() (*iam.GetPolicyVersionOutput, error) {
+				return client.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{PolicyArn: policy.Arn, VersionId: policy.DefaultVersionId})
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("could not get the document of %s: %w", aws.ToString(policy.PolicyName), err))
+				continue
+			}
+			detail := types.ManagedPolicyDetail{
+				Arn:               policy.Arn,
+				PolicyName:        policy.PolicyName,
+				Path:              policy.Path,
+				DefaultVersionId:  policy.DefaultVersionId,
+				AttachmentCount:   policy.AttachmentCount,
+				CreateDate:        policy.CreateDate,
+				UpdateDate:        policy.UpdateDate,
+				PolicyVersionList: []types.PolicyVersion{*version.PolicyVersion},
+			}
+			res := iamPolicyResource(detail)
+			res.Attributes["aws_managed"] = "true"
+			resources = append(resources, res)
+		}
+	}); err != nil {
+		errs = append(errs, fmt.Errorf("could not list AWS-managed policies: %w", err))
+	}
+	return resources, errors.Join(errs...)
+}
+
+// describeIAMUserCredentials records whether a user can sign in to the
+// console, their MFA devices and their access keys.
+func describeIAMUserCredentials(ctx context.Context, client *iam.Client, attributes map[string]string, userName *string) error {
+	_, err := callAPI(ctx, apiAWSIAM, func() (*iam.GetLoginProfileOutput, error) {
+		return client.GetLoginProfile(ctx, &iam.GetLoginProfileInput{UserName: userName})
+	})
+	switch {
+	case err == nil:
+		attributes["console_access"] = "true"
+	case hasAWSErrorCode(err, "NoSuchEntity"):
+		attributes["console_access"] = "false"
+	default:
+		return fmt.Errorf("could not get login profile: %w", err)
+	}
+
+	var devices []string
+	mfaPages := iam.NewListMFADevicesPaginator(client, &iam.ListMFADevicesInput{UserName: userName})
+	if err := eachPage(ctx, apiAWSIAM, mfaPages.HasMorePages, mfaPages.NextPage, func(page *iam.ListMFADevicesOutput) {
+		for _, device := range page.MFADevices {
+			devices = append(devices, aws.ToString(device.SerialNumber))
+		}
+	}); err != nil {
+		return fmt.Errorf("could not list MFA devices: %w", err)
+	}
+	attributes["mfa_enabled"] = fmt.Sprintf("%t", len(devices) > 0)
+	if len(devices) > 0 {
+		attributes["mfa_devices"] = strings.Join(devices, ", ")
+	}
+
+	var keys []types.AccessKeyMetadata
+	keyPages := iam.NewListAccessKeysPaginator(client, &iam.ListAccessKeysInput{UserName: userName})
+	if err := eachPage(ctx, apiAWSIAM, keyPages.HasMorePages, keyPages.NextPage, func(page *iam.ListAccessKeysOutput) {
+		keys = append(keys, page.AccessKeyMetadata...)
+	}); err != nil {
+		return fmt.Errorf("could not list access keys: %w", err)
+	}
+	oldest := -1
+	for _, key := range keys {
+		prefix := "access_key:" + aws.ToString(key.AccessKeyId) + ":"
+		attributes[prefix+"status"] = string(key.Status)
+		if key.CreateDate != nil {
+			age := int(now().Sub(*key.CreateDate).Hours() / 24)
+			attributes[prefix+"created"] = key.CreateDate.UTC().Format(time.RFC3339)
+			attributes[prefix+"age_days"] = strconv.Itoa(age)
+			if key.Status == types.StatusTypeActive && age > oldest {
+				oldest = age
+			}
+		}
+		lastUsed, err := callAPI(ctx, apiAWSIAM, func() (*iam.GetAccessKeyLastUsedOutput, error) {
+			return client.GetAccessKeyLastUsed(ctx, &iam.GetAccessKeyLastUsedInput{AccessKeyId: key.AccessKeyId})
+		})
+		if err != nil {
+			return fmt.Errorf("could not get last use of access key %s: %w", aws.ToString(key.AccessKeyId), err)
+		}
+		if used := lastUsed.AccessKeyLastUsed; used != nil && used.LastUsedDate != nil {
+			attributes[prefix+"last_used"] = used.LastUsedDate.UTC().Format(time.RFC3339)
+			attributes[prefix+"last_used_service"] = aws.ToString(used.ServiceName)
+		}
+	}
+	attributes["access_keys"] = strconv.Itoa(len(keys))
+	if oldest >= 0 {
+		attributes["oldest_access_key_days"] = strconv.Itoa(oldest)
+	}
+	return nil
+}
+
+func iamRoleResource(role types.RoleDetail) StandardizedResource {
+	attributes := map[string]string{
+		"path":         aws.ToString(role.Path),
+		"trust_policy": policyJSON(aws.ToString(role.AssumeRolePolicyDocument)),
+	}
+	if role.CreateDate != nil {
+		attributes["created"] = role.CreateDate.UTC().Format(time.RFC3339)
+	}
+	if used := role.RoleLastUsed; used != nil && used.LastUsedDate != nil {
+		attributes["last_used"] = used.LastUsedDate.UTC().Format(time.RFC3339)
+	}
+	if boundary := role.PermissionsBoundary; boundary != nil {
+		attributes["permissions_boundary"] = aws.ToString(boundary.PermissionsBoundaryArn)
+	}
+	var profiles []string
+	for _, profile := range role.InstanceProfileList {
+		profiles = append(profiles, aws.ToString(profile.InstanceProfileName))
+	}
+	attributes["instance_profiles"] = strings.Join(profiles, ", ")
+	principals, accounts := trustedPrincipals(attributes["trust_policy"])
+	attributes["trusted_principals"] = strings.Join(principals, ", ")
+	attributes["trusted_accounts"] = strings.Join(accounts, ", ")
+
+	// "policies" keeps the names of every attached and inline policy, as
+	// earlier versions recorded them.
+	names := setIAMPolicies(attributes, role.AttachedManagedPolicies, role.RolePolicyList)
+	attributes["policies"] = strings.Join(names, ", ")
+	attributes = dropEmpty(attributes)
+	for _, tag := range role.Tags {
+		attributes["tag:"+aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return StandardizedResource{
+		Provider:   "aws",
+		Service:    "iam",
+		Region:     "global",
+		ID:         aws.ToString(role.Arn),
+		Name:       aws.ToString(role.RoleName),
+		Attributes: attributes,
+	}
+}
+
+func iamUserResource(user types.UserDetail) StandardizedResource {
+	attributes := map[string]string{
+		"path":   aws.ToString(user.Path),
+		"groups": strings.Join(user.GroupList, ", "),
+	}
+	if user.CreateDate != nil {
+		attributes["created"] = user.CreateDate.UTC().Format(time.RFC3339)
+	}
+	if boundary := user.PermissionsBoundary; boundary != nil {
+		attributes["permissions_boundary"] = aws.ToString(boundary.PermissionsBoundaryArn)
+	}
+	setIAMPolicies(attributes, user.AttachedManagedPolicies, user.UserPolicyList)
+	attributes = dropEmpty(attributes)
+	for _, tag := range user.Tags {
+		attributes["tag:"+aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return StandardizedResource{
+		Provider:   "aws",
+		Service:    "iamuser",
+		Region:     "global",
+		ID:         aws.ToString(user.Arn),
+		Name:       aws.ToString(user.UserName),
+		Attributes: attributes,
+	}
+}
+
+func iamGroupResource(group types.GroupDetail, members []string) StandardizedResource {
+	sort.Strings(members)
+	attributes := map[string]string{
+		"path":    aws.ToString(group.Path),
+		"members": strings.Join(members, ", "),
+	}
+	if group.CreateDate != nil {
+		attributes["created"] = group.CreateDate.UTC().Format(time.RFC3339)
+	}
+	setIAMPolicies(attributes, group.AttachedManagedPolicies, group.GroupPolicyList)
+	return StandardizedResource{
+		Provider:   "aws",
+		Service:    "iamgroup",
+		Region:     "global",
+		ID:         aws.ToString(group.Arn),
+		Name:       aws.ToString(group.GroupName),
+		Attributes: dropEmpty(attributes),
+	}
+}
+
+func iamPolicyResource(policy types.ManagedPolicyDetail) StandardizedResource {
+	attributes := map[string]string{
+		"path":             aws.ToString(policy.Path),
+		"description":      aws.ToString(policy.Description),
+		"default_version":  aws.ToString(policy.DefaultVersionId),
+		"attachment_count": strconv.Itoa(int(aws.ToInt32(policy.AttachmentCount))),
+	}
+	if policy.CreateDate != nil {
+		attributes["created"] = policy.CreateDate.UTC().Format(time.RFC3339)
+	}
+	if policy.UpdateDate != nil {
+		attributes["updated"] = policy.UpdateDate.UTC().Format(time.RFC3339)
+	}
+	for _, version := range policy.PolicyVersionList {
+		if version.IsDefaultVersion || aws.ToString(version.VersionId) == attributes["default_version"] {
+			attributes["document"] = policyJSON(aws.ToString(version.Document))
+		}
+	}
+	return StandardizedResource{
+		Provider:   "aws",
+		Service:    "iampolicy",
+		Region:     "global",
+		ID:         aws.ToString(policy.Arn),
+		Name:       aws.ToString(policy.PolicyName),
+		Attributes: dropEmpty(attributes),
+	}
+}
+
+// setIAMPolicies records the attached policies of a role, user or group in
+// "managed_policies" and its inline policies as "policy:<name>", and
+// returns the names of both.
+func setIAMPolicies(attributes map[string]string, managed []types.AttachedPolicy, inline []types.PolicyDetail) []string {
+	var names, arns, inlineNames []string
+	for _, policy := range managed {
+		names = append(names, aws.ToString(policy.PolicyName))
+		arns = append(arns, aws.ToString(policy.PolicyArn))
+	}
+	for _, policy := range inline {
+		name := aws.ToString(policy.PolicyName)
+		names = append(names, name)
+		inlineNames = append(inlineNames, name)
+		attributes["policy:"+name] = policyJSON(aws.ToString(policy.PolicyDocument))
+	}
+	attributes["managed_policies"] = strings.Join(arns, ", ")
+	attributes["inline_policies"] = strings.Join(inlineNames, ", ")
+	return names
+}
@@ -0,0 +1,217 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	iamARN      = "arn:aws:iam::123456789012:"
+	adminPolicy = "arn:aws:iam::aws:policy/AdministratorAccess"
+	basicPolicy = "arn:aws:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole"
+)
+
+// serveIAM records three roles, the users alice (in admins, with MFA and
+// console access) and ci-bot (in developers), the customer-managed policy
+// bucket-admin and the two AWS-managed policies attached in the account.
+func serveIAM(f *fakeCloud) {
+	f.handle(awsIAMHost, "GetAccountAuthorizationDetails", "aws/iam/get_account_authorization_details.xml")
+	// Credentials are read user by user: alice, then ci-bot.
+	f.handle(awsIAMHost, "GetLoginProfile", "aws/iam/get_login_profile_alice.xml")
+	f.respond(awsIAMHost, "GetLoginProfile", http.StatusNotFound, "aws/errors/iam_no_such_entity.xml")
+	f.handle(awsIAMHost, "ListMFADevices", "aws/iam/list_mfa_devices_alice.xml")
+	f.handle(awsIAMHost, "ListMFADevices", "aws/iam/list_mfa_devices_empty.xml")
+	f.handle(awsIAMHost, "ListAccessKeys", "aws/iam/list_access_keys_alice.xml")
+	f.handle(awsIAMHost, "ListAccessKeys", "aws/iam/list_access_keys_ci_bot.xml")
+	f.handle(awsIAMHost, "GetAccessKeyLastUsed", "aws/iam/get_access_key_last_used_alice_old.xml")
+	f.handle(awsIAMHost, "GetAccessKeyLastUsed", "aws/iam/get_access_key_last_used_never.xml")
+	f.handle(awsIAMHost, "GetAccessKeyLastUsed", "aws/iam/get_access_key_last_used_ci_bot.xml")
+	f.handle(awsIAMHost, "ListPolicies", "aws/iam/list_policies_aws_attached.xml")
+	f.handle(awsIAMHost, "GetPolicyVersion", "aws/iam/get_policy_version_admin.xml")
+	f.handle(awsIAMHost, "GetPolicyVersion", "aws/iam/get_policy_version_lambda_basic.xml")
+}
+
+// useClock fixes the time FetchIAMResources measures ages against.
+func useClock(t *testing.T, at string) {
+	fixed, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := now
+	now = func() time.Time { return fixed }
+	t.Cleanup(func() { now = previous })
+}
+
+func TestFetchIAMResources(t *testing.T) {
+	useClock(t, "2025-07-01T00:00:00Z")
+	f := newFakeCloud(t)
+	serveIAM(f)
+
+	resources, err := FetchIAMResources(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchIAMResources() error = %v", err)
+	}
+	lambdaTrust := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"lambda.amazonaws.com"},"Action":"sts:AssumeRole"}]}`
+
+	tests := []struct {
+		key   string
+		name  string
+		attrs map[string]string
+	}{
+		{"iam/" + iamARN + "role/service-role/orders-api-role", "orders-api-role", map[string]string{
+			"path":               "/service-role/",
+			"created":            "2024-02-28T10:00:00Z",
+			"last_used":          "2025-06-01T12:00:00Z",
+			"trust_policy":       lambdaTrust,
+			"trusted_principals": "Service:lambda.amazonaws.com",
+			"managed_policies":   basicPolicy,
+			"policies":           "AWSLambdaBasicExecutionRole",
+		}},
+		{"iam/" + iamARN + "role/thumbnailer", "thumbnailer", map[string]string{
+			"path":                     "/",
+			"created":                  "2023-12-01T08:00:00Z",
+			"trust_policy":             lambdaTrust,
+			"trusted_principals":       "Service:lambda.amazonaws.com",
+			"managed_policies":         basicPolicy,
+			"inline_policies":          "read-orders-table",
+			"policy:read-orders-table": `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":["dynamodb:GetItem","dynamodb:Query"],"Resource":"arn:aws:dynamodb:us-east-1:123456789012:table/orders"}}`,
+			"policies":                 "AWSLambdaBasicExecutionRole, read-orders-table",
+		}},
+		{"iam/" + iamARN + "role/ci-deployer", "ci-deployer", map[string]string{
+			"path":               "/",
+			"created":            "2024-05-10T09:00:00Z",
+			"trust_policy":       `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam::444455556666:root","arn:aws:iam::777788889999:role/deployer"]},"Action":"sts:AssumeRole","Condition":{"StringEquals":{"sts:ExternalId":"ci-external-id"}}}]}`,
+			"trusted_principals": "AWS:arn:aws:iam::444455556666:root, AWS:arn:aws:iam::777788889999:role/deployer",
+			"trusted_accounts":   "444455556666, 777788889999",
+			"managed_policies":   iamARN + "policy/bucket-admin",
+			"policies":           "bucket-admin",
+			"tag:team":           "platform",
+		}},
+		{"iamuser/" + iamARN + "user/alice", "alice", map[string]string{
+			"path":                                   "/",
+			"created":                                "2023-01-10T09:00:00Z",
+			"groups":                                 "admins",
+			"console_access":                         "true",
+			"mfa_enabled":                            "true",
+			"mfa_devices":                            iamARN + "mfa/alice-phone",
+			"access_keys":                            "2",
+			"oldest_access_key_days":                 "365",
+			"access_key:AKIAALICEOLD00000001:status": "Active",
+			"access_key:AKIAALICEOLD00000001:created":           "2024-07-01T00:00:00Z",
+			"access_key:AKIAALICEOLD00000001:age_days":          "365",
+			"access_key:AKIAALICEOLD00000001:last_used":         "2025-06-28T16:20:00Z",
+			"access_key:AKIAALICEOLD00000001:last_used_service": "s3",
+			"access_key:AKIAALICENEW00000002:status":            "Inactive",
+			"access_key:AKIAALICENEW00000002:created":           "2025-05-01T00:00:00Z",
+			"access_key:AKIAALICENEW00000002:age_days":          "61",
+		}},
+		{"iamuser/" + iamARN + "user/automation/ci-bot", "ci-bot", map[string]string{
+			"path":                                   "/automation/",
+			"created":                                "2024-03-01T09:00:00Z",
+			"groups":                                 "developers",
+			"inline_policies":                        "upload-artifacts",
+			"policy:upload-artifacts":                `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:PutObject","Resource":"arn:aws:s3:::acme-artifacts/*"}]}`,
+			"console_access":                         "false",
+			"mfa_enabled":                            "false",
+			"access_keys":                            "1",
+			"oldest_access_key_days":                 "90",
+			"access_key:AKIACIBOT00000000003:status": "Active",
+			"access_key:AKIACIBOT00000000003:created":           "2025-04-01T12:00:00Z",
+			"access_key:AKIACIBOT00000000003:age_days":          "90",
+			"access_key:AKIACIBOT00000000003:last_used":         "2025-06-30T02:00:00Z",
+			"access_key:AKIACIBOT00000000003:last_used_service": "s3",
+		}},
+		{"iamgroup/" + iamARN + "group/admins", "admins", map[string]string{
+			"path":             "/",
+			"created":          "2023-01-10T08:00:00Z",
+			"members":          "alice",
+			"managed_policies": adminPolicy,
+		}},
+		{"iamgroup/" + iamARN + "group/developers", "developers", map[string]string{
+			"path":             "/",
+			"created":          "2023-01-10T08:05:00Z",
+			"members":          "ci-bot",
+			"managed_policies": iamARN + "policy/bucket-admin",
+		}},
+		{"iampolicy/" + iamARN + "policy/bucket-admin", "bucket-admin", map[string]string{
+			"path":             "/",
+			"description":      "Full access to the acme buckets",
+			"default_version":  "v2",
+			"attachment_count": "2",
+			"created":          "2024-01-05T10:00:00Z",
+			"updated":          "2024-04-20T15:30:00Z",
+			"document":         `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":["arn:aws:s3:::acme-*","arn:aws:s3:::acme-*/*"]},{"Effect":"Deny","Action":"s3:DeleteBucket","Resource":"arn:aws:s3:::acme-audit-logs"}]}`,
+		}},
+		{"iampolicy/" + adminPolicy, "AdministratorAccess", map[string]string{
+			"path":             "/",
+			"default_version":  "v1",
+			"attachment_count": "1",
+			"created":          "2015-02-06T18:39:46Z",
+			"updated":          "2015-02-06T18:39:46Z",
+			"document":         `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`,
+			"aws_managed":      "true",
+		}},
+		{"iampolicy/" + basicPolicy, "AWSLambdaBasicExecutionRole", map[string]string{
+			"path":             "/service-role/",
+			"default_version":  "v1",
+			"attachment_count": "2",
+			"created":          "2015-04-09T15:03:43Z",
+			"updated":          "2015-04-09T15:03:43Z",
+			"document":         `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["logs:CreateLogGroup","logs:CreateLogStream","logs:PutLogEvents"],"Resource":"*"}]}`,
+			"aws_managed":      "true",
+		}},
+	}
+	if len(resources) != len(tests) {
+		t.Errorf("Expected %d resources, got %v", len(tests), resourceKeys(resources))
+	}
+	index := resourceIndex(resources)
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			res, ok := index[tt.key]
+			if !ok {
+				t.Fatalf("Missing resource %s", tt.key)
+			}
+			if res.Provider != "aws" || res.Name != tt.name || res.Region != "global" {
+				t.Errorf("Unexpected resource %+v", res)
+			}
+			if !reflect.DeepEqual(res.Attributes, tt.attrs) {
+				t.Errorf("Attributes mismatch\n got: %v\nwant: %v", res.Attributes, tt.attrs)
+			}
+		})
+	}
+}
+
+func TestFetchIAMResourcesCredentialsDenied(t *testing.T) {
+	f := newFakeCloud(t)
+	f.respond(awsIAMHost, "ListAccessKeys", http.StatusForbidden, "aws/errors/iam_access_denied.xml")
+	f.respond(awsIAMHost, "ListPolicies", http.StatusForbidden, "aws/errors/iam_access_denied.xml")
+	serveIAM(f)
+
+	resources, err := FetchIAMResources(t.Context(), f.awsConfig("us-east-1"))
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+	failed := FailedServices(err)
+	if len(failed) != 2 || failed["iamuser"] == nil || failed["iampolicy"] == nil {
+		t.Errorf("Expected iamuser and iampolicy to fail, got %v", failed)
+	}
+	// Everything from the authorization details is still returned.
+	if len(resources) != 8 {
+		t.Errorf("Expected 8 resources, got %v", resourceKeys(resources))
+	}
+}
+
+func TestFetchIAMResourcesDenied(t *testing.T) {
+	f := newFakeCloud(t)
+	f.respond(awsIAMHost, "GetAccountAuthorizationDetails", http.StatusForbidden, "aws/errors/iam_access_denied.xml")
+
+	resources, err := FetchIAMResources(t.Context(), f.awsConfig("us-east-1"))
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+	if len(resources) != 0 {
+		t.Errorf("Expected no resources, got %v", resourceKeys(resources))
+	}
+}
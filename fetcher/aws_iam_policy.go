@@ -0,0 +1,245 @@
+// fetcher/aws_iam_policy.go
+package fetcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// iamPolicyDocument is the part of an IAM policy document that the cache
+// summarizes and IAMPrincipalsAllowing evaluates.
+type iamPolicyDocument struct {
+	Statement iamStatements `json:"Statement"`
+}
+
+type iamStatement struct {
+	Effect    string          `json:"Effect"`
+	Principal json.RawMessage `json:"Principal"`
+	Action    iamStringList   `json:"Action"`
+	NotAction iamStringList   `json:"NotAction"`
+	Resource  iamStringList   `json:"Resource"`
+	Condition json.RawMessage `json:"Condition"`
+}
+
+// iamStatements accepts a single statement as well as a list of them.
+type iamStatements []iamStatement
+
+func (s *iamStatements) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var statement iamStatement
+		if err := json.Unmarshal(data, &statement); err != nil {
+			return err
+		}
+		*s = iamStatements{statement}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]iamStatement)(s))
+}
+
+// iamStringList accepts a single string as well as a list of them.
+type iamStringList []string
+
+func (l *iamStringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = iamStringList{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(l))
+}
+
+// accountARN matches the account ID in an IAM principal ARN.
+var accountARN = regexp.MustCompile(`^arn:aws[\w-]*:(?:iam|sts)::(\d{12}):`)
+
+// policyJSON decodes a policy document as IAM returns it, URL-encoded, and
+// compacts it. A document that is not valid JSON is returned decoded.
+func policyJSON(document string) string {
+	if decoded, err := url.PathUnescape(document); err == nil {
+		document = decoded
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(document)); err != nil {
+		return document
+	}
+	return compact.String()
+}
+
+// trustedPrincipals lists the principals a trust policy allows to assume
+// the role, as "<type>:<principal>" such as "Service:lambda.amazonaws.com",
+// and the AWS accounts among them. A principal of "*" trusts everyone.
+func trustedPrincipals(document string) (principals, accounts []string) {
+	var policy iamPolicyDocument
+	if json.Unmarshal([]byte(document), &policy) != nil {
+		return nil, nil
+	}
+	add := func(list *[]string, value string) {
+		for _, existing := range *list {
+			if existing == value {
+				return
+			}
+		}
+		*list = append(*list, value)
+	}
+	for _, statement := range policy.Statement {
+		if statement.Effect != "Allow" || len(statement.Principal) == 0 {
+			continue
+		}
+		var everyone string
+		if json.Unmarshal(statement.Principal, &everyone) == nil {
+			add(&principals, everyone)
+			add(&accounts, everyone)
+			continue
+		}
+		var byType map[string]iamStringList
+		if json.Unmarshal(statement.Principal, &byType) != nil {
+			continue
+		}
+		principalTypes := make([]string, 0, len(byType))
+		for principalType := range byType {
+			principalTypes = append(principalTypes, principalType)
+		}
+		sort.Strings(principalTypes)
+		for _, principalType := range principalTypes {
+			for _, principal := range byType[principalType] {
+				add(&principals, principalType+":"+principal)
+				if principalType != "AWS" {
+					continue
+				}
+				if m := accountARN.FindStringSubmatch(principal); m != nil {
+					add(&accounts, m[1])
+				} else if len(principal) == 12 || principal == "*" {
+					add(&accounts, principal)
+				}
+			}
+		}
+	}
+	return principals, accounts
+}
+
+// IAMPrincipalsAllowing returns the AWS IAM roles, users and groups whose
+// policies allow action, such as "s3:DeleteBucket", as collected by
+// FetchIAMResources. A principal's policies are its inline policies, the
+// managed policies attached to it and, for a user, those of its groups.
+// An action is allowed when an Allow statement grants it, through Action or
+// NotAction and wildcards, and no Deny statement on every resource revokes
+// it. Resources and conditions are not evaluated otherwise, so the answer
+// is who may be able to, not who certainly can.
+func IAMPrincipalsAllowing(resources []StandardizedResource, action string) []StandardizedResource {
+	policies := make(map[string]string)
+	groups := make(map[string]StandardizedResource)
+	for _, res := range resources {
+		switch res.Service {
+		case "iampolicy":
+			policies[res.ID] = res.Attributes["document"]
+		case "iamgroup":
+			groups[res.Attributes["account_id"]+"/"+res.Name] = res
+		}
+	}
+	documents := func(res StandardizedResource) []string {
+		var docs []string
+		for key, value := range res.Attributes {
+			if strings.HasPrefix(key, "policy:") {
+				docs = append(docs, value)
+			}
+		}
+		for _, arn := range splitList(res.Attributes["managed_policies"]) {
+			if doc, ok := policies[arn]; ok {
+				docs = append(docs, doc)
+			}
+		}
+		return docs
+	}
+
+	var allowed []StandardizedResource
+	for _, res := range resources {
+		if res.Provider != "aws" || (res.Service != "iam" && res.Service != "iamuser" && res.Service != "iamgroup") {
+			continue
+		}
+		docs := documents(res)
+		if res.Service == "iamuser" {
+			for _, group := range splitList(res.Attributes["groups"]) {
+				if g, ok := groups[res.Attributes["account_id"]+"/"+group]; ok {
+					docs = append(docs, documents(g)...)
+				}
+			}
+		}
+		if policiesAllow(docs, action) {
+			allowed = append(allowed, res)
+		}
+	}
+	return allowed
+}
+
+// policiesAllow evaluates action against a set of policy documents, as
+// described on IAMPrincipalsAllowing.
+func policiesAllow(documents []string, action string) bool {
+	allow := false
+	for _, document := range documents {
+		var policy iamPolicyDocument
+		if json.Unmarshal([]byte(document), &policy) != nil {
+			continue
+		}
+		for _, statement := range policy.Statement {
+			matches := len(statement.Action) > 0 && matchesAny(statement.Action, action)
+			if len(statement.Action) == 0 && len(statement.NotAction) > 0 {
+				matches = !matchesAny(statement.NotAction, action)
+			}
+			if !matches {
+				continue
+			}
+			switch statement.Effect {
+			case "Allow":
+				allow = true
+			case "Deny":
+				if len(statement.Condition) == 0 && matchesAny(statement.Resource, "*") {
+					return false
+				}
+			}
+		}
+	}
+	return allow
+}
+
+// matchesAny reports whether value matches one of the IAM patterns, which
+// are case-insensitive and may use "*" and "?" wildcards.
+func matchesAny(patterns []string, value string) bool {
+	value = strings.ToLower(value)
+	for _, pattern := range patterns {
+		if matchWildcard(strings.ToLower(pattern), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWildcard reports whether value matches pattern, where "*" matches any
+// run of characters and "?" any single one. On a mismatch it backtracks to
+// the last "*", letting it absorb one more character.
+func matchWildcard(pattern, value string) bool {
+	p, v := []rune(pattern), []rune(value)
+	pi, vi := 0, 0
+	star, mark := -1, 0
+	for vi < len(v) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == v[vi]):
+			pi++
+			vi++
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, vi
+			pi++
+		case star >= 0:
+			mark++
+			pi, vi = star+1, mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
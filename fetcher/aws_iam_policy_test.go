@@ -0,0 +1,90 @@
+package fetcher
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIAMPrincipalsAllowing(t *testing.T) {
+	f := newFakeCloud(t)
+	serveIAM(f)
+	resources, err := FetchIAMResources(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchIAMResources() error = %v", err)
+	}
+
+	tests := []struct {
+		action string
+		want   []string
+	}{
+		// bucket-admin allows s3:* but denies deleting the audit log bucket
+		// only, so it still allows s3:DeleteBucket elsewhere.
+		{"s3:DeleteBucket", []string{
+			"iam/" + iamARN + "role/ci-deployer",
+			"iamgroup/" + iamARN + "group/admins",
+			"iamgroup/" + iamARN + "group/developers",
+			"iamuser/" + iamARN + "user/alice",
+			"iamuser/" + iamARN + "user/automation/ci-bot",
+		}},
+		{"DynamoDB:getitem", []string{
+			"iam/" + iamARN + "role/thumbnailer",
+			"iamgroup/" + iamARN + "group/admins",
+			"iamuser/" + iamARN + "user/alice",
+		}},
+		{"logs:PutLogEvents", []string{
+			"iam/" + iamARN + "role/service-role/orders-api-role",
+			"iam/" + iamARN + "role/thumbnailer",
+			"iamgroup/" + iamARN + "group/admins",
+			"iamuser/" + iamARN + "user/alice",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			got := resourceKeys(IAMPrincipalsAllowing(resources, tt.action))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IAMPrincipalsAllowing(%q)\n got: %v\nwant: %v", tt.action, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoliciesAllow(t *testing.T) {
+	tests := []struct {
+		name      string
+		documents []string
+		action    string
+		want      bool
+	}{
+		{"no policies", nil, "s3:GetObject", false},
+		{"wildcard action", []string{`{"Statement":{"Effect":"Allow","Action":"s3:Get*","Resource":"*"}}`}, "s3:GetObject", true},
+		{"other service", []string{`{"Statement":{"Effect":"Allow","Action":"s3:*","Resource":"*"}}`}, "ec2:RunInstances", false},
+		{"wildcard inside", []string{`{"Statement":{"Effect":"Allow","Action":"ec2:*Instances","Resource":"*"}}`}, "ec2:RunInstances", true},
+		{"single character", []string{`{"Statement":{"Effect":"Allow","Action":"s3:Get?bject","Resource":"*"}}`}, "s3:GetObject", true},
+		{"case insensitive", []string{`{"Statement":{"Effect":"Allow","Action":"S3:getobject","Resource":"*"}}`}, "s3:GetObject", true},
+		{"wildcard needs a match", []string{`{"Statement":{"Effect":"Allow","Action":"s3:Get*Acl","Resource":"*"}}`}, "s3:GetObject", false},
+		{"not action", []string{`{"Statement":{"Effect":"Allow","NotAction":"iam:*","Resource":"*"}}`}, "iam:CreateUser", false},
+		{"deny everywhere", []string{
+			`{"Statement":{"Effect":"Allow","Action":"*","Resource":"*"}}`,
+			`{"Statement":{"Effect":"Deny","Action":"s3:DeleteBucket","Resource":"*"}}`,
+		}, "s3:DeleteBucket", false},
+		{"conditional deny", []string{
+			`{"Statement":[{"Effect":"Allow","Action":"*","Resource":"*"},{"Effect":"Deny","Action":"*","Resource":"*","Condition":{"Bool":{"aws:MultiFactorAuthPresent":"false"}}}]}`,
+		}, "s3:DeleteBucket", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policiesAllow(tt.documents, tt.action); got != tt.want {
+				t.Errorf("policiesAllow(%q) = %t, want %t", tt.action, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyJSON(t *testing.T) {
+	// IAM percent-encodes documents; a literal "+" is not a space.
+	document := "%7B%22Condition%22%3A%20%7B%22StringEquals%22%3A%20%7B%22aws%3ARequestTag%2Fphone%22%3A%20%22+1%20555%22%7D%7D%7D"
+	want := `{"Condition":{"StringEquals":{"aws:RequestTag/phone":"+1 555"}}}`
+	if got := policyJSON(document); got != want {
+		t.Errorf("policyJSON() = %s, want %s", got, want)
+	}
+}
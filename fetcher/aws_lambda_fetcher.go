@@ -0,0 +1,84 @@
+// fetcher/aws_lambda_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// lambdaTimeLayout is the format of a function's LastModified timestamp.
+const lambdaTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+func init() {
+	Register(NewFetcher("aws-lambda", "aws", ScopeProject, []string{"lambda"}, regionalAWSFetcher(FetchLambdaFunctions)))
+}
+
+// FetchLambdaFunctions collects the Lambda functions in the region of cfg,
+// identified by their ARN since a function name is only unique within a
+// region. The "role" attribute holds the execution role's ARN, which is the
+// ID of that role as collected by FetchIAMResources. Of the environment, only
+// variable names are recorded; their values can hold secrets and never reach
+// the cache.
+func FetchLambdaFunctions(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	client := lambda.NewFromConfig(cfg)
+	region := cfg.Region
+	log.Printf("   -> Fetching Lambda functions for AWS region: %s", region)
+
+	paginator := lambda.NewListFunctionsPaginator(client, &lambda.ListFunctionsInput{})
+	if err := eachPage(ctx, apiAWSLambda, paginator.HasMorePages, paginator.NextPage, func(page *lambda.ListFunctionsOutput) {
+		for _, function := range page.Functions {
+			resources = append(resources, lambdaFunctionResource(function, region))
+		}
+	}); err != nil {
+		return resources, fmt.Errorf("failed to list Lambda functions: %w", err)
+	}
+
+	log.Printf("Successfully fetched %d Lambda functions in %s.\n", len(resources), region)
+	return resources, nil
+}
+
+func lambdaFunctionResource(function types.FunctionConfiguration, region string) StandardizedResource {
+	var architectures []string
+	for _, arch := range function.Architectures {
+		architectures = append(architectures, string(arch))
+	}
+	attributes := map[string]string{
+		"arn":           aws.ToString(function.FunctionArn),
+		"runtime":       string(function.Runtime),
+		"handler":       aws.ToString(function.Handler),
+		"package_type":  string(function.PackageType),
+		"architecture":  strings.Join(architectures, ", "),
+		"memory_mb":     fmt.Sprintf("%d", aws.ToInt32(function.MemorySize)),
+		"timeout_s":     fmt.Sprintf("%d", aws.ToInt32(function.Timeout)),
+		"role":          aws.ToString(function.Role),
+		"last_modified": aws.ToString(function.LastModified),
+		"state":         strings.ToLower(string(function.State)),
+	}
+	if modified, err := time.Parse(lambdaTimeLayout, attributes["last_modified"]); err == nil {
+		attributes["last_modified"] = modified.UTC().Format(time.RFC3339)
+	}
+	if vpc := function.VpcConfig; vpc != nil && aws.ToString(vpc.VpcId) != "" {
+		attributes["vpc_id"] = aws.ToString(vpc.VpcId)
+		attributes["subnets"] = strings.Join(vpc.SubnetIds, ", ")
+		attributes["security_groups"] = strings.Join(vpc.SecurityGroupIds, ", ")
+	}
+	if env := function.Environment; env != nil && len(env.Variables) > 0 {
+		names := make([]string, 0, len(env.Variables))
+		for name := range env.Variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		attributes["env_vars"] = strings.Join(names, ", ")
+	}
+
+	return StandardizedResource{Provider: "aws", Service: "lambda", Region: region, ID: aws.ToString(function.FunctionArn), Name: aws.ToString(function.FunctionName), Attributes: dropEmpty(attributes)}
+}
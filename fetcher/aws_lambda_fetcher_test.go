@@ -0,0 +1,105 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const lambdaHost = "lambda.us-east-1.amazonaws.com"
+
+func TestFetchLambdaFunctions(t *testing.T) {
+	f := newFakeCloud(t)
+	f.handle(lambdaHost, "GET /2015-03-31/functions", "aws/lambda/list_functions_page1.json")
+	f.handle(lambdaHost, "GET /2015-03-31/functions?token=page-2", "aws/lambda/list_functions_page2.json")
+
+	resources, err := FetchLambdaFunctions(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchLambdaFunctions() error = %v", err)
+	}
+	index := resourceIndex(resources)
+
+	tests := []struct {
+		id    string
+		attrs map[string]string
+	}{
+		{"orders-api", map[string]string{
+			"arn":             "arn:aws:lambda:us-east-1:123456789012:function:orders-api",
+			"runtime":         "python3.12",
+			"handler":         "app.handler",
+			"package_type":    "Zip",
+			"architecture":    "arm64",
+			"memory_mb":       "512",
+			"timeout_s":       "30",
+			"role":            "arn:aws:iam::123456789012:role/service-role/orders-api-role",
+			"last_modified":   "2024-03-01T09:30:00Z",
+			"state":           "active",
+			"vpc_id":          "vpc-0a1b2c3d4e5f60001",
+			"subnets":         "subnet-0aaa000000000001, subnet-0bbb000000000002",
+			"security_groups": "sg-0web000000000001",
+			"env_vars":        "DB_PASSWORD, LOG_LEVEL, TABLE_NAME",
+		}},
+		{"thumbnailer", map[string]string{
+			"arn":           "arn:aws:lambda:us-east-1:123456789012:function:thumbnailer",
+			"package_type":  "Image",
+			"architecture":  "x86_64",
+			"memory_mb":     "2048",
+			"timeout_s":     "900",
+			"role":          "arn:aws:iam::123456789012:role/thumbnailer",
+			"last_modified": "2023-12-24T17:00:00Z",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			res, ok := index["lambda/"+tt.attrs["arn"]]
+			if !ok {
+				t.Fatalf("Missing function %s", tt.id)
+			}
+			if res.Provider != "aws" || res.Name != tt.id || res.Region != "us-east-1" {
+				t.Errorf("Unexpected resource %+v", res)
+			}
+			if !reflect.DeepEqual(res.Attributes, tt.attrs) {
+				t.Errorf("Attributes mismatch\n got: %v\nwant: %v", res.Attributes, tt.attrs)
+			}
+			for key, value := range res.Attributes {
+				if strings.Contains(value, "hunter2") {
+					t.Errorf("Attribute %s leaks an environment variable value: %q", key, value)
+				}
+			}
+		})
+	}
+}
+
+// The role of a function is found under the ID FetchIAMResources gives that role.
+func TestLambdaRoleMatchesIAMRoleID(t *testing.T) {
+	f := newFakeCloud(t)
+	f.handle(lambdaHost, "GET /2015-03-31/functions", "aws/lambda/list_functions_page1.json")
+	f.handle(lambdaHost, "GET /2015-03-31/functions?token=page-2", "aws/lambda/list_functions_page2.json")
+	serveIAM(f)
+
+	functions, err := FetchLambdaFunctions(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchLambdaFunctions() error = %v", err)
+	}
+	roles, err := FetchIAMResources(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchIAMResources() error = %v", err)
+	}
+	index := resourceIndex(roles)
+	for _, function := range functions {
+		if _, ok := index["iam/"+function.Attributes["role"]]; !ok {
+			t.Errorf("Role %q of %s not found among %v", function.Attributes["role"], function.ID, resourceKeys(roles))
+		}
+	}
+}
+
+func TestFetchLambdaFunctionsDenied(t *testing.T) {
+	f := newFakeCloud(t)
+	f.respond(lambdaHost, "GET /2015-03-31/functions", http.StatusForbidden, "aws/errors/lambda_access_denied.json")
+
+	_, err := FetchLambdaFunctions(t.Context(), f.awsConfig("us-east-1"))
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+}
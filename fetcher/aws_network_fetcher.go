@@ -0,0 +1,271 @@
+// fetcher/aws_network_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func init() {
+	Register(NewFetcher("aws-network", "aws", ScopeProject,
+		[]string{"vpc", "subnet", "routetable", "internetgateway", "natgateway", "securitygroup"},
+		regionalAWSFetcher(FetchAWSNetworkResources)))
+}
+
+// FetchAWSNetworkResources collects the VPCs, subnets, route tables, internet
+// and NAT gateways and security groups in the region of cfg. The service names
+// match the GCP network fetcher, so both clouds answer the same questions.
+// A failed listing is reported as a ServiceError; the other services are still returned.
+func FetchAWSNetworkResources(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	var errs []error
+	client := ec2.NewFromConfig(cfg)
+	region := cfg.Region
+	log.Printf("   -> Fetching network resources for AWS region: %s", region)
+
+	vpcs := ec2.NewDescribeVpcsPaginator(client, &ec2.DescribeVpcsInput{})
+	if err := eachPage(ctx, apiAWSEC2, vpcs.HasMorePages, vpcs.NextPage, func(page *ec2.DescribeVpcsOutput) {
+		for _, vpc := range page.Vpcs {
+			var cidrs []string
+			for _, assoc := range vpc.CidrBlockAssociationSet {
+				cidrs = append(cidrs, aws.ToString(assoc.CidrBlock))
+			}
+			for _, assoc := range vpc.Ipv6CidrBlockAssociationSet {
+				cidrs = append(cidrs, aws.ToString(assoc.Ipv6CidrBlock))
+			}
+			id := aws.ToString(vpc.VpcId)
+			resources = append(resources, StandardizedResource{Provider: "aws", Service: "vpc", Region: region, ID: id, Name: ec2TagName(vpc.Tags, id), Attributes: map[string]string{
+				"cidr_block": strings.Join(cidrs, ", "),
+				"state":      string(vpc.State),
+				"is_default": fmt.Sprintf("%t", aws.ToBool(vpc.IsDefault)),
+			}})
+		}
+	}); err != nil {
+		errs = append(errs, &ServiceError{Service: "vpc", Err: fmt.Errorf("could not describe VPCs: %w", err)})
+	}
+
+	subnets := ec2.NewDescribeSubnetsPaginator(client, &ec2.DescribeSubnetsInput{})
+	if err := eachPage(ctx, apiAWSEC2, subnets.HasMorePages, subnets.NextPage, func(page *ec2.DescribeSubnetsOutput) {
+		for _, subnet := range page.Subnets {
+			var ipv6 []string
+			for _, assoc := range subnet.Ipv6CidrBlockAssociationSet {
+				ipv6 = append(ipv6, aws.ToString(assoc.Ipv6CidrBlock))
+			}
+			id := aws.ToString(subnet.SubnetId)
+			resources = append(resources, StandardizedResource{Provider: "aws", Service: "subnet", Region: region, ID: id, Name: ec2TagName(subnet.Tags, id), Attributes: map[string]string{
+				"vpc":               aws.ToString(subnet.VpcId),
+				"cidr_range":        aws.ToString(subnet.CidrBlock),
+				"ipv6_cidr_range":   strings.Join(ipv6, ", "),
+				"availability_zone": aws.ToString(subnet.AvailabilityZone),
+				"available_ips":     fmt.Sprintf("%d", aws.ToInt32(subnet.AvailableIpAddressCount)),
+				"map_public_ip":     fmt.Sprintf("%t", aws.ToBool(subnet.MapPublicIpOnLaunch)),
+				"default_for_az":    fmt.Sprintf("%t", aws.ToBool(subnet.DefaultForAz)),
+			}})
+		}
+	}); err != nil {
+		errs = append(errs, &ServiceError{Service: "subnet", Err: fmt.Errorf("could not describe subnets: %w", err)})
+	}
+
+	routeTables := ec2.NewDescribeRouteTablesPaginator(client, &ec2.DescribeRouteTablesInput{})
+	if err := eachPage(ctx, apiAWSEC2, routeTables.HasMorePages, routeTables.NextPage, func(page *ec2.DescribeRouteTablesOutput) {
+		for _, table := range page.RouteTables {
+			main := false
+			var associated []string
+			for _, assoc := range table.Associations {
+				if aws.ToBool(assoc.Main) {
+					main = true
+				}
+				if assoc.SubnetId != nil {
+					associated = append(associated, *assoc.SubnetId)
+				}
+			}
+			id := aws.ToString(table.RouteTableId)
+			resources = append(resources, StandardizedResource{Provider: "aws", Service: "routetable", Region: region, ID: id, Name: ec2TagName(table.Tags, id), Attributes: map[string]string{
+				"vpc":     aws.ToString(table.VpcId),
+				"main":    fmt.Sprintf("%t", main),
+				"subnets": strings.Join(associated, ", "),
+				"routes":  formatRoutes(table.Routes),
+			}})
+		}
+	}); err != nil {
+		errs = append(errs, &ServiceError{Service: "routetable", Err: fmt.Errorf("could not describe route tables: %w", err)})
+	}
+
+	igws := ec2.NewDescribeInternetGatewaysPaginator(client, &ec2.DescribeInternetGatewaysInput{})
+	if err := eachPage(ctx, apiAWSEC2, igws.HasMorePages, igws.NextPage, func(page *ec2.DescribeInternetGatewaysOutput) {
+		for _, igw := range page.InternetGateways {
+			var vpcIDs, states []string
+			for _, attachment := range igw.Attachments {
+				vpcIDs = append(vpcIDs, aws.ToString(attachment.VpcId))
+				states = append(states, string(attachment.State))
+			}
+			state := strings.Join(states, ", ")
+			if state == "" {
+				state = "detached"
+			}
+			id := aws.ToString(igw.InternetGatewayId)
+			resources = append(resources, StandardizedResource{Provider: "aws", Service: "internetgateway", Region: region, ID: id, Name: ec2TagName(igw.Tags, id), Attributes: map[string]string{
+				"vpc":   strings.Join(vpcIDs, ", "),
+				"state": state,
+			}})
+		}
+	}); err != nil {
+		errs = append(errs, &ServiceError{Service: "internetgateway", Err: fmt.Errorf("could not describe internet gateways: %w", err)})
+	}
+
+	natgws := ec2.NewDescribeNatGatewaysPaginator(client, &ec2.DescribeNatGatewaysInput{})
+	if err := eachPage(ctx, apiAWSEC2, natgws.HasMorePages, natgws.NextPage, func(page *ec2.DescribeNatGatewaysOutput) {
+		for _, nat := range page.NatGateways {
+			var publicIPs, privateIPs []string
+			for _, addr := range nat.NatGatewayAddresses {
+				if addr.PublicIp != nil {
+					publicIPs = append(publicIPs, *addr.PublicIp)
+				}
+				if addr.PrivateIp != nil {
+					privateIPs = append(privateIPs, *addr.PrivateIp)
+				}
+			}
+			id := aws.ToString(nat.NatGatewayId)
+			resources = append(resources, StandardizedResource{Provider: "aws", Service: "natgateway", Region: region, ID: id, Name: ec2TagName(nat.Tags, id), Attributes: map[string]string{
+				"vpc":               aws.ToString(nat.VpcId),
+				"subnet":            aws.ToString(nat.SubnetId),
+				"state":             string(nat.State),
+				"connectivity_type": string(nat.ConnectivityType),
+				"public_ip":         strings.Join(publicIPs, ", "),
+				"private_ip":        strings.Join(privateIPs, ", "),
+			}})
+		}
+	}); err != nil {
+		errs = append(errs, &ServiceError{Service: "natgateway", Err: fmt.Errorf("could not describe NAT gateways: %w", err)})
+	}
+
+	groups := ec2.NewDescribeSecurityGroupsPaginator(client, &ec2.DescribeSecurityGroupsInput{})
+	if err := eachPage(ctx, apiAWSEC2, groups.HasMorePages, groups.NextPage, func(page *ec2.DescribeSecurityGroupsOutput) {
+		for _, group := range page.SecurityGroups {
+			resources = append(resources, StandardizedResource{Provider: "aws", Service: "securitygroup", Region: region, ID: aws.ToString(group.GroupId), Name: aws.ToString(group.GroupName), Attributes: map[string]string{
+				"vpc":         aws.ToString(group.VpcId),
+				"description": aws.ToString(group.Description),
+				"ingress":     formatIPPermissions(group.IpPermissions, "from"),
+				"egress":      formatIPPermissions(group.IpPermissionsEgress, "to"),
+			}})
+		}
+	}); err != nil {
+		errs = append(errs, &ServiceError{Service: "securitygroup", Err: fmt.Errorf("could not describe security groups: %w", err)})
+	}
+
+	return resources, errors.Join(errs...)
+}
+
+// eachPage hands every page of an AWS paginator to handle, fetching each
+// through the active Throttle.
+func eachPage[T any, O any](ctx context.Context, api string, hasMore func() bool, nextPage func(context.Context, ...O) (T, error), handle func(T)) error {
+	for hasMore() {
+		page, err := awsPage(ctx, api, nextPage)
+		if err != nil {
+			return err
+		}
+		handle(page)
+	}
+	return nil
+}
+
+// ec2TagName returns the Name tag, or fallback when there is none.
+func ec2TagName(tags []types.Tag, fallback string) string {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == "Name" && aws.ToString(tag.Value) != "" {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return fallback
+}
+
+// formatRoutes renders route table entries as "destination -> target",
+// e.g. "0.0.0.0/0 -> igw-0abc; 10.0.0.0/16 -> local".
+func formatRoutes(routes []types.Route) string {
+	var parts []string
+	for _, route := range routes {
+		dest := aws.ToString(route.DestinationCidrBlock)
+		if dest == "" {
+			dest = aws.ToString(route.DestinationIpv6CidrBlock)
+		}
+		if dest == "" {
+			dest = aws.ToString(route.DestinationPrefixListId)
+		}
+		target := "unknown"
+		for _, id := range []*string{route.GatewayId, route.NatGatewayId, route.TransitGatewayId, route.VpcPeeringConnectionId,
+			route.NetworkInterfaceId, route.InstanceId, route.EgressOnlyInternetGatewayId, route.LocalGatewayId, route.CarrierGatewayId} {
+			if aws.ToString(id) != "" {
+				target = *id
+				break
+			}
+		}
+		entry := dest + " -> " + target
+		if route.State == types.RouteStateBlackhole {
+			entry += " (blackhole)"
+		}
+		parts = append(parts, entry)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// formatIPPermissions renders security group rules in the style of the GCP
+// firewall "allowed" attribute, adding the peers of each rule:
+// "tcp:22 from 10.0.0.0/8, sg-0abc; all from 0.0.0.0/0".
+func formatIPPermissions(permissions []types.IpPermission, direction string) string {
+	var parts []string
+	for _, perm := range permissions {
+		rule := aws.ToString(perm.IpProtocol)
+		switch rule {
+		case "-1":
+			rule = "all"
+		case "6":
+			rule = "tcp"
+		case "17":
+			rule = "udp"
+		case "1":
+			rule = "icmp"
+		}
+		if rule != "all" && perm.FromPort != nil {
+			from, to := aws.ToInt32(perm.FromPort), aws.ToInt32(perm.ToPort)
+			switch {
+			case rule == "icmp" || rule == "icmpv6":
+				if from != -1 {
+					rule += fmt.Sprintf(":type %d", from)
+				}
+			case from == to:
+				rule += fmt.Sprintf(":%d", from)
+			case from == 0 && to == 65535:
+			default:
+				rule += fmt.Sprintf(":%d-%d", from, to)
+			}
+		}
+
+		var peers []string
+		for _, r := range perm.IpRanges {
+			peers = append(peers, aws.ToString(r.CidrIp))
+		}
+		for _, r := range perm.Ipv6Ranges {
+			peers = append(peers, aws.ToString(r.CidrIpv6))
+		}
+		for _, p := range perm.PrefixListIds {
+			peers = append(peers, aws.ToString(p.PrefixListId))
+		}
+		for _, g := range perm.UserIdGroupPairs {
+			peers = append(peers, aws.ToString(g.GroupId))
+		}
+		sort.Strings(peers)
+		if len(peers) > 0 {
+			rule += " " + direction + " " + strings.Join(peers, ", ")
+		}
+		parts = append(parts, rule)
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,147 @@
+package fetcher
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// awsNetworkRoutes are the responses of the example network in us-east-1.
+var awsNetworkRoutes = map[string]string{
+	"DescribeVpcs":             "aws/ec2/describe_vpcs.xml",
+	"DescribeSubnets":          "aws/ec2/describe_subnets.xml",
+	"DescribeRouteTables":      "aws/ec2/describe_route_tables.xml",
+	"DescribeInternetGateways": "aws/ec2/describe_internet_gateways.xml",
+	"DescribeNatGateways":      "aws/ec2/describe_nat_gateways.xml",
+	"DescribeSecurityGroups":   "aws/ec2/describe_security_groups.xml",
+}
+
+// serveAWSNetwork records the example network, replacing the responses of
+// the operations in overrides.
+func serveAWSNetwork(f *fakeCloud, overrides map[string]fakeResponse) {
+	for operation, file := range awsNetworkRoutes {
+		if o, ok := overrides[operation]; ok {
+			f.respond(ec2Host, operation, o.status, o.file)
+			continue
+		}
+		f.handle(ec2Host, operation, file)
+	}
+}
+
+func TestFetchAWSNetworkResources(t *testing.T) {
+	f := newFakeCloud(t)
+	serveAWSNetwork(f, nil)
+
+	resources, err := FetchAWSNetworkResources(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchAWSNetworkResources() error = %v", err)
+	}
+	index := resourceIndex(resources)
+
+	tests := []struct {
+		key   string
+		name  string
+		attrs map[string]string
+	}{
+		{"vpc/vpc-0a1b2c3d4e5f60001", "prod-vpc", map[string]string{"cidr_block": "10.0.0.0/16", "is_default": "false", "state": "available"}},
+		{"vpc/vpc-0default000000001", "vpc-0default000000001", map[string]string{"is_default": "true"}},
+		{"subnet/subnet-0aaa000000000001", "prod-public-a", map[string]string{
+			"vpc": "vpc-0a1b2c3d4e5f60001", "cidr_range": "10.0.1.0/24", "availability_zone": "us-east-1a",
+			"available_ips": "250", "map_public_ip": "true",
+		}},
+		{"routetable/rtb-0public000000001", "prod-public", map[string]string{
+			"main": "false", "subnets": "subnet-0aaa000000000001",
+			"routes": "10.0.0.0/16 -> local; 0.0.0.0/0 -> igw-0abc000000000001",
+		}},
+		{"routetable/rtb-0main00000000001", "rtb-0main00000000001", map[string]string{
+			"main":   "true",
+			"routes": "10.0.0.0/16 -> local; 0.0.0.0/0 -> nat-0abc000000000001; 192.168.0.0/16 -> pcx-0abc000000000001 (blackhole)",
+		}},
+		{"internetgateway/igw-0abc000000000001", "prod-igw", map[string]string{"vpc": "vpc-0a1b2c3d4e5f60001", "state": "available"}},
+		{"natgateway/nat-0abc000000000001", "nat-0abc000000000001", map[string]string{
+			"subnet": "subnet-0aaa000000000001", "public_ip": "203.0.113.10", "private_ip": "10.0.1.10", "connectivity_type": "public",
+		}},
+		{"securitygroup/sg-0web000000000001", "web", map[string]string{
+			"vpc":     "vpc-0a1b2c3d4e5f60001",
+			"ingress": "tcp:443 from 0.0.0.0/0, ::/0; tcp:8000-8100 from sg-0lb0000000000001",
+			"egress":  "all to 0.0.0.0/0",
+		}},
+	}
+	for _, tt := range tests {
+		res, ok := index[tt.key]
+		if !ok {
+			t.Errorf("Missing %s", tt.key)
+			continue
+		}
+		if res.Name != tt.name || res.Region != "us-east-1" {
+			t.Errorf("%s: name %q region %q, want %q us-east-1", tt.key, res.Name, res.Region, tt.name)
+		}
+		for k, want := range tt.attrs {
+			if got := res.Attributes[k]; got != want {
+				t.Errorf("%s: %s = %q, want %q", tt.key, k, got, want)
+			}
+		}
+	}
+}
+
+func TestFetchAWSNetworkResourcesPartialFailure(t *testing.T) {
+	f := newFakeCloud(t)
+	serveAWSNetwork(f, map[string]fakeResponse{
+		"DescribeNatGateways": {http.StatusForbidden, "aws/errors/ec2_unauthorized.xml"},
+	})
+
+	resources, err := FetchAWSNetworkResources(t.Context(), f.awsConfig("us-east-1"))
+	failed := FailedServices(err)
+	if len(failed) != 1 || !IsPermissionDenied(failed["natgateway"]) {
+		t.Fatalf("Expected only natgateway to be denied, got %v", err)
+	}
+	if len(resources) != 8 {
+		t.Errorf("Expected the other services' 8 resources, got %d", len(resources))
+	}
+}
+
+func TestFormatIPPermissions(t *testing.T) {
+	tests := []struct {
+		name string
+		perm types.IpPermission
+		want string
+	}{
+		{
+			name: "all traffic",
+			perm: types.IpPermission{IpProtocol: aws.String("-1"), IpRanges: []types.IpRange{{CidrIp: aws.String("10.0.0.0/8")}}},
+			want: "all from 10.0.0.0/8",
+		},
+		{
+			name: "single port",
+			perm: types.IpPermission{IpProtocol: aws.String("tcp"), FromPort: aws.Int32(22), ToPort: aws.Int32(22), IpRanges: []types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}},
+			want: "tcp:22 from 0.0.0.0/0",
+		},
+		{
+			name: "every port",
+			perm: types.IpPermission{IpProtocol: aws.String("udp"), FromPort: aws.Int32(0), ToPort: aws.Int32(65535), PrefixListIds: []types.PrefixListId{{PrefixListId: aws.String("pl-0001")}}},
+			want: "udp from pl-0001",
+		},
+		{
+			name: "icmp type",
+			perm: types.IpPermission{IpProtocol: aws.String("icmp"), FromPort: aws.Int32(8), ToPort: aws.Int32(-1), UserIdGroupPairs: []types.UserIdGroupPair{{GroupId: aws.String("sg-1")}}},
+			want: "icmp:type 8 from sg-1",
+		},
+		{
+			name: "any icmp",
+			perm: types.IpPermission{IpProtocol: aws.String("icmp"), FromPort: aws.Int32(-1), ToPort: aws.Int32(-1)},
+			want: "icmp",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatIPPermissions([]types.IpPermission{tt.perm}, "from"); got != tt.want {
+				t.Errorf("formatIPPermissions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if got := formatIPPermissions(nil, "from"); got != "" {
+		t.Errorf("formatIPPermissions(nil) = %q", got)
+	}
+}
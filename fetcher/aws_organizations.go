@@ -0,0 +1,146 @@
+// fetcher/aws_organizations.go
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+)
+
+// AWSOrganization configures discovery of the accounts and organizational
+// units of an AWS organization.
+type AWSOrganization struct {
+	// Discover turns on AWS Organizations discovery.
+	Discover bool `yaml:"discover"`
+	// Profile names the credentials used to call AWS Organizations, usually
+	// those of the management or a delegated administrator account. The
+	// default credentials are used when it is empty.
+	Profile string `yaml:"profile"`
+	// RoleName, when set, is assumed (from Profile's credentials) into every
+	// active member account, so that each one is collected like a configured account.
+	RoleName   string `yaml:"role_name"`
+	ExternalID string `yaml:"external_id"`
+}
+
+// FetchAWSOrganization lists the roots, organizational units and accounts of
+// the organization that cfg's credentials belong to. Roots and OUs have the
+// service "aws-ou", accounts "aws-account"; each carries the ID of the root
+// or OU that contains it in a "parent_id" attribute.
+func FetchAWSOrganization(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error) {
+	client := organizations.NewFromConfig(cfg)
+
+	log.Println("   -> Fetching AWS Organization...")
+	org, err := callAPI(ctx, apiAWSOrganizations, func() (*organizations.DescribeOrganizationOutput, error) {
+		return client.DescribeOrganization(ctx, &organizations.DescribeOrganizationInput{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe organization: %w", err)
+	}
+	orgID := aws.ToString(org.Organization.Id)
+
+	var resources []StandardizedResource
+	var parents []string
+	roots := organizations.NewListRootsPaginator(client, &organizations.ListRootsInput{})
+	for roots.HasMorePages() {
+		page, err := awsPage(ctx, apiAWSOrganizations, roots.NextPage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list organization roots: %w", err)
+		}
+		for _, root := range page.Roots {
+			resources = append(resources, StandardizedResource{
+				Provider: "aws",
+				Service:  "aws-ou",
+				Region:   "global",
+				ID:       aws.ToString(root.Id),
+				Name:     aws.ToString(root.Name),
+				Attributes: map[string]string{
+					"type":            "root",
+					"arn":             aws.ToString(root.Arn),
+					"organization_id": orgID,
+				},
+			})
+			parents = append(parents, aws.ToString(root.Id))
+		}
+	}
+
+	// Walk the tree breadth first; every parent is asked for its OUs and accounts.
+	for len(parents) > 0 {
+		parent := parents[0]
+		parents = parents[1:]
+
+		ous := organizations.NewListOrganizationalUnitsForParentPaginator(client, &organizations.ListOrganizationalUnitsForParentInput{ParentId: aws.String(parent)})
+		for ous.HasMorePages() {
+			page, err := awsPage(ctx, apiAWSOrganizations, ous.NextPage)
+			if err != nil {
+				return resources, fmt.Errorf("failed to list organizational units of %s: %w", parent, err)
+			}
+			for _, ou := range page.OrganizationalUnits {
+				resources = append(resources, StandardizedResource{
+					Provider: "aws",
+					Service:  "aws-ou",
+					Region:   "global",
+					ID:       aws.ToString(ou.Id),
+					Name:     aws.ToString(ou.Name),
+					Attributes: map[string]string{
+						"type":            "organizational_unit",
+						"arn":             aws.ToString(ou.Arn),
+						"parent_id":       parent,
+						"organization_id": orgID,
+					},
+				})
+				parents = append(parents, aws.ToString(ou.Id))
+			}
+		}
+
+		accounts := organizations.NewListAccountsForParentPaginator(client, &organizations.ListAccountsForParentInput{ParentId: aws.String(parent)})
+		for accounts.HasMorePages() {
+			page, err := awsPage(ctx, apiAWSOrganizations, accounts.NextPage)
+			if err != nil {
+				return resources, fmt.Errorf("failed to list accounts of %s: %w", parent, err)
+			}
+			for _, account := range page.Accounts {
+				state := strings.ToLower(string(account.State))
+				if state == "" {
+					state = strings.ToLower(string(account.Status))
+				}
+				attributes := map[string]string{
+					"arn":             aws.ToString(account.Arn),
+					"email":           aws.ToString(account.Email),
+					"state":           state,
+					"parent_id":       parent,
+					"organization_id": orgID,
+				}
+				if account.JoinedTimestamp != nil {
+					attributes["joined"] = account.JoinedTimestamp.UTC().Format(time.RFC3339)
+				}
+				resources = append(resources, StandardizedResource{
+					Provider:   "aws",
+					Service:    "aws-account",
+					Region:     "global",
+					ID:         aws.ToString(account.Id),
+					Name:       aws.ToString(account.Name),
+					Attributes: attributes,
+				})
+			}
+		}
+	}
+
+	log.Printf("Successfully fetched %d AWS Organization resources.\n", len(resources))
+	return resources, nil
+}
+
+// organizationRoleARN returns the ARN of the role named roleName in the
+// organization account described by res.
+func organizationRoleARN(res StandardizedResource, roleName string) string {
+	partition := "aws"
+	if parsed, err := arn.Parse(res.Attributes["arn"]); err == nil {
+		partition = parsed.Partition
+	}
+	return fmt.Sprintf("arn:%s:iam::%s:role/%s", partition, res.ID, roleName)
+}
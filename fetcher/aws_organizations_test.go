@@ -0,0 +1,141 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const organizationsHost = "organizations.us-east-1.amazonaws.com"
+
+// serveOrganization records the example organization: the management
+// account under the root, and a Production OU with an active and a
+// suspended member account.
+func serveOrganization(f *fakeCloud) {
+	const target = "AWSOrganizationsV20161128."
+	f.handle(organizationsHost, target+"DescribeOrganization", "aws/organizations/describe_organization.json")
+	f.handle(organizationsHost, target+"ListRoots", "aws/organizations/list_roots.json")
+	// The tree is walked breadth first: the root, then ou-ab12-prod0001.
+	f.handle(organizationsHost, target+"ListOrganizationalUnitsForParent", "aws/organizations/list_organizational_units_root.json")
+	f.handle(organizationsHost, target+"ListOrganizationalUnitsForParent", "aws/organizations/list_organizational_units_empty.json")
+	f.handle(organizationsHost, target+"ListAccountsForParent", "aws/organizations/list_accounts_root.json")
+	f.handle(organizationsHost, target+"ListAccountsForParent", "aws/organizations/list_accounts_production.json")
+}
+
+func TestFetchAWSOrganization(t *testing.T) {
+	f := newFakeCloud(t)
+	serveOrganization(f)
+
+	resources, err := FetchAWSOrganization(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchAWSOrganization() error = %v", err)
+	}
+
+	want := []string{
+		"aws-account/111122223333", "aws-account/444455556666", "aws-account/777788889999",
+		"aws-ou/ou-ab12-prod0001", "aws-ou/r-ab12",
+	}
+	if got := resourceKeys(resources); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Resources = %v, want %v", got, want)
+	}
+
+	index := resourceIndex(resources)
+	parents := map[string]string{
+		"aws-ou/r-ab12":            "",
+		"aws-ou/ou-ab12-prod0001":  "r-ab12",
+		"aws-account/111122223333": "r-ab12",
+		"aws-account/444455556666": "ou-ab12-prod0001",
+		"aws-account/777788889999": "ou-ab12-prod0001",
+	}
+	for key, parent := range parents {
+		if got := index[key].Attributes["parent_id"]; got != parent {
+			t.Errorf("%s: parent_id = %q, want %q", key, got, parent)
+		}
+		if got := index[key].Attributes["organization_id"]; got != "o-exampleorg1" {
+			t.Errorf("%s: organization_id = %q", key, got)
+		}
+	}
+	prod := index["aws-account/444455556666"]
+	if prod.Name != "Production App" || prod.Attributes["state"] != "active" || prod.Attributes["email"] != "aws-prod@example.com" || prod.Attributes["joined"] != "2021-01-01T00:00:00Z" {
+		t.Errorf("Unexpected account %+v", prod)
+	}
+	if state := index["aws-account/777788889999"].Attributes["state"]; state != "suspended" {
+		t.Errorf("Suspended account state = %q", state)
+	}
+}
+
+func TestFetchAWSAccountsFromOrganization(t *testing.T) {
+	const roleARN = "arn:aws:iam::444455556666:role/InfrakitReadOnly"
+
+	f := newFakeCloud(t)
+	serveOrganization(f)
+	// The default credentials are resolved before the member accounts.
+	f.handle(stsHost, "GetCallerIdentity", "aws/sts/get_caller_identity.xml")
+	f.handle(stsHost, "GetCallerIdentity", "aws/sts/get_caller_identity_assumed_role.xml")
+	f.handle(awsIAMHost, "ListAccountAliases", "aws/iam/list_account_aliases.xml")
+	f.handle(awsIAMHost, "ListAccountAliases", "aws/iam/list_account_aliases_empty.xml")
+	f.handle(stsHost, "AssumeRole", "aws/sts/assume_role.xml")
+
+	cfg := f.awsConfig("us-east-1")
+	scope := Scope{
+		AWSConfig:       &cfg,
+		AWSOrganization: AWSOrganization{Discover: true, RoleName: "InfrakitReadOnly", ExternalID: "ext-1"},
+	}
+	resources, err := FetchAWSAccounts(t.Context(), scope)
+	if err != nil {
+		t.Fatalf("FetchAWSAccounts() error = %v", err)
+	}
+	if n := f.calls(stsHost, "AssumeRole"); n != 1 {
+		t.Errorf("Expected the role to be assumed into the active member only, got %d AssumeRole calls", n)
+	}
+
+	index := resourceIndex(resources)
+	management := index["aws-account/111122223333"]
+	if management.Name != "acme-prod" || management.Attributes["parent_id"] != "r-ab12" || management.Attributes["role_arn"] != "" {
+		t.Errorf("Unexpected management account %+v", management)
+	}
+	member := index["aws-account/444455556666"]
+	if member.Name != "Production App" || member.Attributes["role_arn"] != roleARN || member.Attributes["parent_id"] != "ou-ab12-prod0001" {
+		t.Errorf("Unexpected member account %+v", member)
+	}
+	if _, ok := index["aws-ou/ou-ab12-prod0001"]; !ok {
+		t.Error("Expected the organizational units to be returned")
+	}
+
+	tests := []struct {
+		key            string
+		wantCollected  bool
+		wantExternalID string
+	}{
+		{key: "aws-account/111122223333", wantCollected: true},
+		{key: "aws-account/444455556666", wantCollected: true, wantExternalID: "ext-1"},
+		{key: "aws-account/777788889999", wantCollected: false},
+		{key: "aws-ou/ou-ab12-prod0001", wantCollected: false},
+	}
+	for _, tt := range tests {
+		child, ok := ChildScope(scope, index[tt.key])
+		if ok != tt.wantCollected {
+			t.Errorf("%s: ChildScope() collected = %v, want %v", tt.key, ok, tt.wantCollected)
+		}
+		if child.Account.ExternalID != tt.wantExternalID {
+			t.Errorf("%s: external ID = %q, want %q", tt.key, child.Account.ExternalID, tt.wantExternalID)
+		}
+	}
+}
+
+func TestFetchAWSAccountsOrganizationNotInUse(t *testing.T) {
+	f := newFakeCloud(t)
+	f.respond(organizationsHost, "AWSOrganizationsV20161128.DescribeOrganization", http.StatusBadRequest, "aws/errors/organizations_not_in_use.json")
+	f.handle(stsHost, "GetCallerIdentity", "aws/sts/get_caller_identity.xml")
+	f.handle(awsIAMHost, "ListAccountAliases", "aws/iam/list_account_aliases.xml")
+
+	cfg := f.awsConfig("us-east-1")
+	resources, err := FetchAWSAccounts(t.Context(), Scope{AWSConfig: &cfg, AWSOrganization: AWSOrganization{Discover: true}})
+	failed := FailedServices(err)
+	if len(failed) != 1 || failed["aws-ou"] == nil {
+		t.Fatalf("Expected only aws-ou to fail, got %v", err)
+	}
+	if got := resourceKeys(resources); !reflect.DeepEqual(got, []string{"aws-account/111122223333"}) {
+		t.Errorf("Resources = %v", got)
+	}
+}
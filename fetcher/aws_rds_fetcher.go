@@ -0,0 +1,145 @@
+// fetcher/aws_rds_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+func init() {
+	Register(NewFetcher("aws-rds", "aws", ScopeProject, []string{"rds"}, regionalAWSFetcher(FetchRDSDatabases)))
+}
+
+// FetchRDSDatabases collects the RDS DB instances and Aurora (or Multi-AZ)
+// DB clusters in the region of cfg. Both have the service "rds", are
+// identified by their ARN since identifiers are only unique within a region
+// and kind, and are told apart by the "type" attribute. Cluster members name
+// their cluster in "cluster".
+func FetchRDSDatabases(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	var errs []error
+	client := rds.NewFromConfig(cfg)
+	region := cfg.Region
+	log.Printf("   -> Fetching RDS databases for AWS region: %s", region)
+
+	clusters := rds.NewDescribeDBClustersPaginator(client, &rds.DescribeDBClustersInput{})
+	if err := eachPage(ctx, apiAWSRDS, clusters.HasMorePages, clusters.NextPage, func(page *rds.DescribeDBClustersOutput) {
+		for _, cluster := range page.DBClusters {
+			resources = append(resources, rdsClusterResource(cluster, region))
+		}
+	}); err != nil {
+		errs = append(errs, fmt.Errorf("could not describe DB clusters: %w", err))
+	}
+
+	instances := rds.NewDescribeDBInstancesPaginator(client, &rds.DescribeDBInstancesInput{})
+	if err := eachPage(ctx, apiAWSRDS, instances.HasMorePages, instances.NextPage, func(page *rds.DescribeDBInstancesOutput) {
+		for _, instance := range page.DBInstances {
+			resources = append(resources, rdsInstanceResource(instance, region))
+		}
+	}); err != nil {
+		errs = append(errs, fmt.Errorf("could not describe DB instances: %w", err))
+	}
+
+	log.Printf("Successfully fetched %d RDS databases in %s.\n", len(resources), region)
+	return resources, errors.Join(errs...)
+}
+
+func rdsClusterResource(cluster types.DBCluster, region string) StandardizedResource {
+	var members []string
+	for _, member := range cluster.DBClusterMembers {
+		id := aws.ToString(member.DBInstanceIdentifier)
+		if aws.ToBool(member.IsClusterWriter) {
+			id += " (writer)"
+		}
+		members = append(members, id)
+	}
+	var groups []string
+	for _, group := range cluster.VpcSecurityGroups {
+		groups = append(groups, aws.ToString(group.VpcSecurityGroupId))
+	}
+	attributes := map[string]string{
+		"type":                "cluster",
+		"arn":                 aws.ToString(cluster.DBClusterArn),
+		"engine":              aws.ToString(cluster.Engine),
+		"engine_version":      aws.ToString(cluster.EngineVersion),
+		"engine_mode":         aws.ToString(cluster.EngineMode),
+		"instance_class":      aws.ToString(cluster.DBClusterInstanceClass),
+		"status":              aws.ToString(cluster.Status),
+		"endpoint":            aws.ToString(cluster.Endpoint),
+		"reader_endpoint":     aws.ToString(cluster.ReaderEndpoint),
+		"port":                rdsPort(cluster.Port),
+		"multi_az":            fmt.Sprintf("%t", aws.ToBool(cluster.MultiAZ)),
+		"storage_encrypted":   fmt.Sprintf("%t", aws.ToBool(cluster.StorageEncrypted)),
+		"kms_key":             aws.ToString(cluster.KmsKeyId),
+		"publicly_accessible": fmt.Sprintf("%t", aws.ToBool(cluster.PubliclyAccessible)),
+		"subnet_group":        aws.ToString(cluster.DBSubnetGroup),
+		"parameter_group":     aws.ToString(cluster.DBClusterParameterGroup),
+		"security_groups":     strings.Join(groups, ", "),
+		"members":             strings.Join(members, ", "),
+	}
+	attributes = dropEmpty(attributes)
+	setTagMap(attributes, rdsTagMap(cluster.TagList))
+
+	return StandardizedResource{Provider: "aws", Service: "rds", Region: region, ID: aws.ToString(cluster.DBClusterArn), Name: aws.ToString(cluster.DBClusterIdentifier), Attributes: attributes}
+}
+
+func rdsInstanceResource(instance types.DBInstance, region string) StandardizedResource {
+	var groups, parameterGroups []string
+	for _, group := range instance.VpcSecurityGroups {
+		groups = append(groups, aws.ToString(group.VpcSecurityGroupId))
+	}
+	for _, group := range instance.DBParameterGroups {
+		parameterGroups = append(parameterGroups, aws.ToString(group.DBParameterGroupName))
+	}
+	attributes := map[string]string{
+		"type":                "instance",
+		"arn":                 aws.ToString(instance.DBInstanceArn),
+		"engine":              aws.ToString(instance.Engine),
+		"engine_version":      aws.ToString(instance.EngineVersion),
+		"instance_class":      aws.ToString(instance.DBInstanceClass),
+		"status":              aws.ToString(instance.DBInstanceStatus),
+		"cluster":             aws.ToString(instance.DBClusterIdentifier),
+		"availability_zone":   aws.ToString(instance.AvailabilityZone),
+		"multi_az":            fmt.Sprintf("%t", aws.ToBool(instance.MultiAZ)),
+		"storage_encrypted":   fmt.Sprintf("%t", aws.ToBool(instance.StorageEncrypted)),
+		"kms_key":             aws.ToString(instance.KmsKeyId),
+		"publicly_accessible": fmt.Sprintf("%t", aws.ToBool(instance.PubliclyAccessible)),
+		"parameter_group":     strings.Join(parameterGroups, ", "),
+		"security_groups":     strings.Join(groups, ", "),
+	}
+	if instance.Endpoint != nil {
+		attributes["endpoint"] = aws.ToString(instance.Endpoint.Address)
+		attributes["port"] = rdsPort(instance.Endpoint.Port)
+	}
+	if group := instance.DBSubnetGroup; group != nil {
+		attributes["subnet_group"] = aws.ToString(group.DBSubnetGroupName)
+		attributes["vpc_id"] = aws.ToString(group.VpcId)
+	}
+	attributes = dropEmpty(attributes)
+	setTagMap(attributes, rdsTagMap(instance.TagList))
+
+	return StandardizedResource{Provider: "aws", Service: "rds", Region: region, ID: aws.ToString(instance.DBInstanceArn), Name: aws.ToString(instance.DBInstanceIdentifier), Attributes: attributes}
+}
+
+func rdsPort(port *int32) string {
+	if port == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d", *port)
+}
+
+// rdsTagMap converts an RDS tag list for setTagMap.
+func rdsTagMap(tags []types.Tag) map[string]string {
+	m := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		m[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return m
+}
@@ -0,0 +1,120 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const rdsHost = "rds.us-east-1.amazonaws.com"
+
+func TestFetchRDSDatabases(t *testing.T) {
+	f := newFakeCloud(t)
+	f.handle(rdsHost, "DescribeDBClusters", "aws/rds/describe_db_clusters.xml")
+	f.handle(rdsHost, "DescribeDBInstances", "aws/rds/describe_db_instances_page1.xml")
+	f.handle(rdsHost, "DescribeDBInstances?token=page-2", "aws/rds/describe_db_instances_page2.xml")
+
+	resources, err := FetchRDSDatabases(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchRDSDatabases() error = %v", err)
+	}
+	index := resourceIndex(resources)
+
+	tests := []struct {
+		id    string
+		attrs map[string]string
+	}{
+		{"orders-aurora", map[string]string{
+			"type":                "cluster",
+			"arn":                 "arn:aws:rds:us-east-1:123456789012:cluster:orders-aurora",
+			"engine":              "aurora-postgresql",
+			"engine_version":      "15.4",
+			"engine_mode":         "provisioned",
+			"status":              "available",
+			"endpoint":            "orders-aurora.cluster-c9akciq32.us-east-1.rds.amazonaws.com",
+			"reader_endpoint":     "orders-aurora.cluster-ro-c9akciq32.us-east-1.rds.amazonaws.com",
+			"port":                "5432",
+			"multi_az":            "true",
+			"storage_encrypted":   "true",
+			"kms_key":             "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			"publicly_accessible": "false",
+			"subnet_group":        "orders-db-subnets",
+			"parameter_group":     "default.aurora-postgresql15",
+			"security_groups":     "sg-0db0000000000001",
+			"members":             "orders-aurora-1 (writer), orders-aurora-2",
+			"tag:team":            "orders",
+		}},
+		{"orders-aurora-1", map[string]string{
+			"type":                "instance",
+			"arn":                 "arn:aws:rds:us-east-1:123456789012:db:orders-aurora-1",
+			"engine":              "aurora-postgresql",
+			"engine_version":      "15.4",
+			"instance_class":      "db.r6g.large",
+			"status":              "available",
+			"cluster":             "orders-aurora",
+			"endpoint":            "orders-aurora-1.c9akciq32.us-east-1.rds.amazonaws.com",
+			"port":                "5432",
+			"availability_zone":   "us-east-1a",
+			"multi_az":            "false",
+			"storage_encrypted":   "true",
+			"kms_key":             "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			"publicly_accessible": "false",
+			"subnet_group":        "orders-db-subnets",
+			"vpc_id":              "vpc-0a1b2c3d4e5f60001",
+			"parameter_group":     "default.aurora-postgresql15",
+			"security_groups":     "sg-0db0000000000001",
+		}},
+		{"legacy-mysql", map[string]string{
+			"type":                "instance",
+			"arn":                 "arn:aws:rds:us-east-1:123456789012:db:legacy-mysql",
+			"engine":              "mysql",
+			"engine_version":      "8.0.35",
+			"instance_class":      "db.t3.medium",
+			"status":              "stopped",
+			"endpoint":            "legacy-mysql.c9akciq32.us-east-1.rds.amazonaws.com",
+			"port":                "3306",
+			"availability_zone":   "us-east-1b",
+			"multi_az":            "true",
+			"storage_encrypted":   "false",
+			"publicly_accessible": "true",
+			"subnet_group":        "default",
+			"vpc_id":              "vpc-0default000000001",
+			"parameter_group":     "legacy-mysql8",
+			"tag:owner":           "billing",
+		}},
+	}
+	if len(resources) != len(tests) {
+		t.Errorf("Expected %d databases, got %v", len(tests), resourceKeys(resources))
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			res, ok := index["rds/"+tt.attrs["arn"]]
+			if !ok {
+				t.Fatalf("Missing database %s", tt.id)
+			}
+			if res.Provider != "aws" || res.Name != tt.id || res.Region != "us-east-1" {
+				t.Errorf("Unexpected resource %+v", res)
+			}
+			if !reflect.DeepEqual(res.Attributes, tt.attrs) {
+				t.Errorf("Attributes mismatch\n got: %v\nwant: %v", res.Attributes, tt.attrs)
+			}
+		})
+	}
+}
+
+func TestFetchRDSDatabasesClustersDenied(t *testing.T) {
+	f := newFakeCloud(t)
+	f.respond(rdsHost, "DescribeDBClusters", http.StatusForbidden, "aws/errors/rds_access_denied.xml")
+	f.handle(rdsHost, "DescribeDBInstances", "aws/rds/describe_db_instances_page1.xml")
+	f.handle(rdsHost, "DescribeDBInstances?token=page-2", "aws/rds/describe_db_instances_page2.xml")
+
+	resources, err := FetchRDSDatabases(t.Context(), f.awsConfig("us-east-1"))
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+	// The instances are still returned.
+	want := []string{"rds/arn:aws:rds:us-east-1:123456789012:db:legacy-mysql", "rds/arn:aws:rds:us-east-1:123456789012:db:orders-aurora-1"}
+	if got := resourceKeys(resources); !reflect.DeepEqual(got, want) {
+		t.Errorf("Resources mismatch\n got: %v\nwant: %v", got, want)
+	}
+}
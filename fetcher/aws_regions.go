@@ -0,0 +1,139 @@
+// fetcher/aws_regions.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// defaultAWSRegion is used to call DescribeRegions when no region is configured.
+const defaultAWSRegion = "us-east-1"
+
+// maxRegionConcurrency caps how many regions one fetcher queries at once.
+const maxRegionConcurrency = 8
+
+// AWSFetchFunc collects resources from the region (or global service) that cfg points at.
+type AWSFetchFunc func(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error)
+
+// globalAWSFetcher adapts a fetch function for a global AWS service, such as
+// IAM, in the account of the scope.
+func globalAWSFetcher(fetch AWSFetchFunc) FetchFunc {
+	return func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+		cfg, err := awsConfigFor(ctx, scope)
+		if err != nil {
+			return nil, err
+		}
+		resources, err := fetch(ctx, cfg)
+		stampAWSAccount(resources, scope.Account)
+		return resources, err
+	}
+}
+
+// regionalAWSFetcher adapts a fetch function for a regional AWS service in the
+// account of the scope. It runs once per region returned by AWSRegions, in parallel.
+func regionalAWSFetcher(fetch AWSFetchFunc) FetchFunc {
+	return func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+		cfg, err := awsConfigFor(ctx, scope)
+		if err != nil {
+			return nil, err
+		}
+		regions, err := AWSRegions(ctx, cfg, scope.AWSRegions)
+		if err != nil {
+			return nil, err
+		}
+		resources, err := FetchAWSRegions(ctx, cfg, regions, fetch)
+		stampAWSAccount(resources, scope.Account)
+		return resources, err
+	}
+}
+
+// AWSRegions returns the regions to collect from: allowList when it is not
+// empty, otherwise every region enabled for the account.
+func AWSRegions(ctx context.Context, cfg aws.Config, allowList []string) ([]string, error) {
+	if len(allowList) > 0 {
+		return allowList, nil
+	}
+	if cfg.Region == "" {
+		cfg.Region = defaultAWSRegion
+	}
+	client := ec2.NewFromConfig(cfg)
+	out, err := callAPI(ctx, apiAWSEC2, func() (*ec2.DescribeRegionsOutput, error) {
+		return client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list enabled AWS regions: %w", err)
+	}
+
+	var regions []string
+	for _, region := range out.Regions {
+		if region.RegionName != nil {
+			regions = append(regions, *region.RegionName)
+		}
+	}
+	sort.Strings(regions)
+	return regions, nil
+}
+
+// FetchAWSRegions runs fetch against every region in parallel, each with a
+// copy of cfg pointed at that region. A region that fails does not stop the
+// others; its error is returned alongside their resources, and one that
+// failed outright rather than per service fails every service of the
+// fetcher (see FailedServices). Regions that deny access and return nothing
+// (commonly an SCP restricting regions) are skipped with a warning, unless
+// every region does.
+func FetchAWSRegions(ctx context.Context, cfg aws.Config, regions []string, fetch AWSFetchFunc) ([]StandardizedResource, error) {
+	type output struct {
+		resources []StandardizedResource
+		err       error
+	}
+	outputs := make([]output, len(regions))
+	sem := make(chan struct{}, maxRegionConcurrency)
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				outputs[i].err = ctx.Err()
+				return
+			}
+			regionCfg := cfg.Copy()
+			regionCfg.Region = region
+			res, err := fetch(ctx, regionCfg)
+			outputs[i] = output{resources: res, err: err}
+		}()
+	}
+	wg.Wait()
+
+	var resources []StandardizedResource
+	var errs, denied []error
+	for i, out := range outputs {
+		resources = append(resources, out.resources...)
+		if out.err == nil {
+			continue
+		}
+		err := fmt.Errorf("region %s: %w", regions[i], out.err)
+		if IsPermissionDenied(out.err) && len(out.resources) == 0 {
+			denied = append(denied, err)
+			continue
+		}
+		errs = append(errs, err)
+	}
+	if len(denied) > 0 && len(denied) == len(regions) {
+		return resources, errors.Join(denied...)
+	}
+	for _, err := range denied {
+		log.Printf("Warning: skipping AWS region: %v", err)
+	}
+	return resources, errors.Join(errs...)
+}
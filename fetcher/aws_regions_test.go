@@ -0,0 +1,118 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestAWSRegions(t *testing.T) {
+	t.Run("allow-list", func(t *testing.T) {
+		f := newFakeCloud(t)
+		regions, err := AWSRegions(t.Context(), f.awsConfig("us-east-1"), []string{"eu-west-1"})
+		if err != nil || !reflect.DeepEqual(regions, []string{"eu-west-1"}) {
+			t.Errorf("AWSRegions() = %v, %v", regions, err)
+		}
+		if n := f.calls(ec2Host, "DescribeRegions"); n != 0 {
+			t.Errorf("DescribeRegions should not be called with an allow-list, got %d calls", n)
+		}
+	})
+
+	t.Run("enabled regions", func(t *testing.T) {
+		f := newFakeCloud(t)
+		f.handle(ec2Host, "DescribeRegions", "aws/ec2/describe_regions.xml")
+		regions, err := AWSRegions(t.Context(), f.awsConfig(""), nil)
+		want := []string{"ap-southeast-2", "eu-west-1", "us-east-1"}
+		if err != nil || !reflect.DeepEqual(regions, want) {
+			t.Errorf("AWSRegions() = %v, %v; want %v", regions, err, want)
+		}
+	})
+
+	t.Run("DescribeRegions denied", func(t *testing.T) {
+		f := newFakeCloud(t)
+		f.respond(ec2Host, "DescribeRegions", http.StatusForbidden, "aws/errors/ec2_unauthorized.xml")
+		if _, err := AWSRegions(t.Context(), f.awsConfig("us-east-1"), nil); err == nil {
+			t.Error("Expected an error when regions cannot be listed")
+		}
+	})
+}
+
+func TestRegionalEC2Fetcher(t *testing.T) {
+	const (
+		euHost = "ec2.eu-west-1.amazonaws.com"
+		apHost = "ec2.ap-southeast-2.amazonaws.com"
+	)
+
+	tests := []struct {
+		name        string
+		apResponse  fakeResponse
+		regions     []string
+		wantErr     bool
+		wantRegions map[string]string
+	}{
+		{
+			name:       "all enabled regions",
+			apResponse: fakeResponse{http.StatusOK, "aws/ec2/describe_instances_empty.xml"},
+			wantRegions: map[string]string{
+				"i-0aaa1111bbbb22220": "us-east-1",
+				"i-0aaa1111bbbb22221": "us-east-1",
+				"i-0ccc3333dddd44440": "us-east-1",
+				"i-0eee5555ffff66660": "eu-west-1",
+			},
+		},
+		{
+			name:        "allow-list",
+			regions:     []string{"eu-west-1"},
+			wantRegions: map[string]string{"i-0eee5555ffff66660": "eu-west-1"},
+		},
+		{
+			name:       "region denied by policy is skipped",
+			apResponse: fakeResponse{http.StatusForbidden, "aws/errors/ec2_unauthorized.xml"},
+			wantRegions: map[string]string{
+				"i-0aaa1111bbbb22220": "us-east-1",
+				"i-0aaa1111bbbb22221": "us-east-1",
+				"i-0ccc3333dddd44440": "us-east-1",
+				"i-0eee5555ffff66660": "eu-west-1",
+			},
+		},
+		{
+			name:       "failing region keeps the others",
+			apResponse: fakeResponse{http.StatusInternalServerError, "aws/errors/ec2_request_limit_exceeded.xml"},
+			wantErr:    true,
+			wantRegions: map[string]string{
+				"i-0aaa1111bbbb22220": "us-east-1",
+				"i-0aaa1111bbbb22221": "us-east-1",
+				"i-0ccc3333dddd44440": "us-east-1",
+				"i-0eee5555ffff66660": "eu-west-1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeCloud(t)
+			if tt.regions == nil {
+				f.handle(ec2Host, "DescribeRegions", "aws/ec2/describe_regions.xml")
+				f.handle(ec2Host, "DescribeInstances", "aws/ec2/describe_instances_page1.xml")
+				f.handle(ec2Host, "DescribeInstances?token=page-2", "aws/ec2/describe_instances_page2.xml")
+				f.respond(apHost, "DescribeInstances", tt.apResponse.status, tt.apResponse.file)
+			}
+			f.handle(euHost, "DescribeInstances", "aws/ec2/describe_instances_eu_west_1.xml")
+
+			cfg := f.awsConfig("us-east-1")
+			ec2Fetcher, _ := LookupFetcher("aws-ec2")
+			resources, err := ec2Fetcher.Fetch(t.Context(), Scope{AWSConfig: &cfg, AWSRegions: tt.regions})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			got := make(map[string]string)
+			for _, res := range resources {
+				got[res.ID] = res.Region
+			}
+			if !reflect.DeepEqual(got, tt.wantRegions) {
+				t.Errorf("Instance regions mismatch\n got: %v\nwant: %v", got, tt.wantRegions)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+// fetcher/aws_route53_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+func init() {
+	Register(NewFetcher("aws-route53", "aws", ScopeProject, []string{"dnszone", "dnsrecord"}, globalAWSFetcher(FetchRoute53Records)))
+}
+
+// FetchRoute53Records collects the public and private hosted zones of the
+// account with their record sets. A zone is identified by its hosted zone ID
+// and named after its domain. A record's ID is "<zone id>/<name>/<type>",
+// followed by "/<set identifier>" for records with a routing policy, and its
+// "parent_id" is the zone. Alias records hold their target in
+// "alias_target" rather than "values". Names are recorded without the
+// trailing dot. A zone whose records cannot be listed is reported as a
+// ServiceError for dnsrecord.
+func FetchRoute53Records(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	client := route53.NewFromConfig(cfg)
+	log.Println("Fetching Route 53 hosted zones...")
+
+	var zones []types.HostedZone
+	zonePages := route53.NewListHostedZonesPaginator(client, &route53.ListHostedZonesInput{})
+	if err := eachPage(ctx, apiAWSRoute53, zonePages.HasMorePages, zonePages.NextPage, func(page *route53.ListHostedZonesOutput) {
+		zones = append(zones, page.HostedZones...)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list Route 53 hosted zones: %w", err)
+	}
+
+	var recordErrs []error
+	for _, zone := range zones {
+		zoneID := strings.TrimPrefix(aws.ToString(zone.Id), "/hostedzone/")
+		zoneName := dnsName(aws.ToString(zone.Name))
+		resources = append(resources, route53ZoneResource(zone, zoneID))
+
+		recordPages := route53.NewListResourceRecordSetsPaginator(client, &route53.ListResourceRecordSetsInput{HostedZoneId: aws.String(zoneID)})
+		if err := eachPage(ctx, apiAWSRoute53, recordPages.HasMorePages, recordPages.NextPage, func(page *route53.ListResourceRecordSetsOutput) {
+			for _, record := range page.ResourceRecordSets {
+				resources = append(resources, route53RecordResource(record, zoneID, zoneName))
+			}
+		}); err != nil {
+			recordErrs = append(recordErrs, fmt.Errorf("could not list records of %s: %w", zoneName, err))
+		}
+	}
+	log.Printf("Successfully fetched %d Route 53 zones and records.\n", len(resources))
+	if err := errors.Join(recordErrs...); err != nil {
+		return resources, &ServiceError{Service: "dnsrecord", Err: err}
+	}
+	return resources, nil
+}
+
+func route53ZoneResource(zone types.HostedZone, zoneID string) StandardizedResource {
+	attributes := map[string]string{
+		"private":      "false",
+		"record_count": strconv.FormatInt(aws.ToInt64(zone.ResourceRecordSetCount), 10),
+	}
+	if zone.Config != nil {
+		attributes["private"] = fmt.Sprintf("%t", zone.Config.PrivateZone)
+		attributes["comment"] = aws.ToString(zone.Config.Comment)
+	}
+	if zone.LinkedService != nil {
+		attributes["linked_service"] = aws.ToString(zone.LinkedService.ServicePrincipal)
+	}
+	return StandardizedResource{
+		Provider:   "aws",
+		Service:    "dnszone",
+		Region:     "global",
+		ID:         zoneID,
+		Name:       dnsName(aws.ToString(zone.Name)),
+		Attributes: dropEmpty(attributes),
+	}
+}
+
+func route53RecordResource(record types.ResourceRecordSet, zoneID, zoneName string) StandardizedResource {
+	name := route53Unescape(dnsName(aws.ToString(record.Name)))
+	id := zoneID + "/" + name + "/" + string(record.Type)
+	if record.SetIdentifier != nil {
+		id += "/" + aws.ToString(record.SetIdentifier)
+	}
+	var values []string
+	for _, value := range record.ResourceRecords {
+		values = append(values, aws.ToString(value.Value))
+	}
+	attributes := map[string]string{
+		"parent_id":      zoneID,
+		"zone":           zoneName,
+		"type":           string(record.Type),
+		"values":         strings.Join(values, ", "),
+		"set_identifier": aws.ToString(record.SetIdentifier),
+		"region":         string(record.Region),
+		"failover":       strings.ToLower(string(record.Failover)),
+		"health_check":   aws.ToString(record.HealthCheckId),
+	}
+	if record.TTL != nil {
+		attributes["ttl"] = strconv.FormatInt(*record.TTL, 10)
+	}
+	if record.Weight != nil {
+		attributes["weight"] = strconv.FormatInt(*record.Weight, 10)
+	}
+	if alias := record.AliasTarget; alias != nil {
+		attributes["alias_target"] = dnsName(aws.ToString(alias.DNSName))
+		attributes["alias_zone"] = aws.ToString(alias.HostedZoneId)
+		attributes["evaluate_target_health"] = fmt.Sprintf("%t", alias.EvaluateTargetHealth)
+	}
+	if geo := record.GeoLocation; geo != nil {
+		var location []string
+		for _, part := range []*string{geo.ContinentCode, geo.CountryCode, geo.SubdivisionCode} {
+			if part != nil {
+				location = append(location, *part)
+			}
+		}
+		attributes["geolocation"] = strings.Join(location, "/")
+	}
+	return StandardizedResource{
+		Provider:   "aws",
+		Service:    "dnsrecord",
+		Region:     "global",
+		ID:         id,
+		Name:       name,
+		Attributes: dropEmpty(attributes),
+	}
+}
+
+// route53Unescape decodes the octal escapes Route 53 uses for characters
+// outside letters, digits, "-" and ".", such as "\052" for a wildcard "*".
+func route53Unescape(name string) string {
+	if !strings.Contains(name, `\`) {
+		return name
+	}
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] == '\\' && i+3 < len(name) {
+			if code, err := strconv.ParseUint(name[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(name[i])
+	}
+	return b.String()
+}
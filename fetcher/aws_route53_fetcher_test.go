@@ -0,0 +1,142 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const (
+	route53Host   = "route53.amazonaws.com"
+	publicZone    = "Z0123456789PUBLIC"
+	privateZone   = "Z0987654321PRIVATE"
+	publicRRSets  = "GET /2013-04-01/hostedzone/" + publicZone + "/rrset"
+	privateRRSets = "GET /2013-04-01/hostedzone/" + privateZone + "/rrset"
+)
+
+func TestFetchRoute53Records(t *testing.T) {
+	f := newFakeCloud(t)
+	f.handle(route53Host, "GET /2013-04-01/hostedzone", "aws/route53/list_hosted_zones.xml")
+	f.handle(route53Host, publicRRSets, "aws/route53/list_records_public.xml")
+	f.handle(route53Host, privateRRSets, "aws/route53/list_records_private.xml")
+
+	resources, err := FetchRoute53Records(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchRoute53Records() error = %v", err)
+	}
+	index := resourceIndex(resources)
+
+	tests := []struct {
+		key   string
+		name  string
+		attrs map[string]string
+	}{
+		{"dnszone/" + publicZone, "example.com", map[string]string{
+			"private":      "false",
+			"record_count": "7",
+			"comment":      "Public site",
+		}},
+		{"dnszone/" + privateZone, "internal.example.com", map[string]string{
+			"private":      "true",
+			"record_count": "3",
+		}},
+		{"dnsrecord/" + publicZone + "/example.com/NS", "example.com", map[string]string{
+			"parent_id": publicZone,
+			"zone":      "example.com",
+			"type":      "NS",
+			"ttl":       "172800",
+			"values":    "ns-1536.awsdns-00.co.uk., ns-0.awsdns-00.com.",
+		}},
+		{"dnsrecord/" + publicZone + "/example.com/TXT", "example.com", map[string]string{
+			"parent_id": publicZone,
+			"zone":      "example.com",
+			"type":      "TXT",
+			"ttl":       "300",
+			"values":    `"v=spf1 include:_spf.example.net ~all"`,
+		}},
+		{"dnsrecord/" + publicZone + "/www.example.com/A", "www.example.com", map[string]string{
+			"parent_id":              publicZone,
+			"zone":                   "example.com",
+			"type":                   "A",
+			"alias_target":           "dualstack.web-alb-1234567890.us-east-1.elb.amazonaws.com",
+			"alias_zone":             "Z35SXDOTRQ7X7K",
+			"evaluate_target_health": "true",
+		}},
+		{"dnsrecord/" + publicZone + "/*.example.com/CNAME", "*.example.com", map[string]string{
+			"parent_id": publicZone,
+			"zone":      "example.com",
+			"type":      "CNAME",
+			"ttl":       "300",
+			"values":    "www.example.com",
+		}},
+		{"dnsrecord/" + publicZone + "/api.example.com/A/blue", "api.example.com", map[string]string{
+			"parent_id":      publicZone,
+			"zone":           "example.com",
+			"type":           "A",
+			"ttl":            "60",
+			"values":         "34.120.1.10",
+			"set_identifier": "blue",
+			"weight":         "90",
+		}},
+		{"dnsrecord/" + publicZone + "/api.example.com/A/green", "api.example.com", map[string]string{
+			"parent_id":      publicZone,
+			"zone":           "example.com",
+			"type":           "A",
+			"ttl":            "60",
+			"values":         "203.0.113.25",
+			"set_identifier": "green",
+			"weight":         "10",
+			"health_check":   "abcdef11-2222-3333-4444-555555fedcba",
+		}},
+		{"dnsrecord/" + publicZone + "/app.example.com/CNAME", "app.example.com", map[string]string{
+			"parent_id": publicZone,
+			"zone":      "example.com",
+			"type":      "CNAME",
+			"ttl":       "300",
+			"values":    "app.demo.example.com.",
+		}},
+		{"dnsrecord/" + privateZone + "/db.internal.example.com/CNAME", "db.internal.example.com", map[string]string{
+			"parent_id": privateZone,
+			"zone":      "internal.example.com",
+			"type":      "CNAME",
+			"ttl":       "60",
+			"values":    "orders-db.cluster-c9akciq32.us-east-1.rds.amazonaws.com",
+		}},
+	}
+	if len(resources) != len(tests) {
+		t.Errorf("Expected %d resources, got %v", len(tests), resourceKeys(resources))
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			res, ok := index[tt.key]
+			if !ok {
+				t.Fatalf("Missing resource %s", tt.key)
+			}
+			if res.Provider != "aws" || res.Name != tt.name || res.Region != "global" {
+				t.Errorf("Unexpected resource %+v", res)
+			}
+			if !reflect.DeepEqual(res.Attributes, tt.attrs) {
+				t.Errorf("Attributes mismatch\n got: %v\nwant: %v", res.Attributes, tt.attrs)
+			}
+		})
+	}
+}
+
+func TestFetchRoute53RecordsDenied(t *testing.T) {
+	f := newFakeCloud(t)
+	f.handle(route53Host, "GET /2013-04-01/hostedzone", "aws/route53/list_hosted_zones.xml")
+	f.handle(route53Host, publicRRSets, "aws/route53/list_records_public.xml")
+	f.respond(route53Host, privateRRSets, http.StatusForbidden, "aws/errors/route53_access_denied.xml")
+
+	resources, err := FetchRoute53Records(t.Context(), f.awsConfig("us-east-1"))
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+	if failed := FailedServices(err); len(failed) != 1 || failed["dnsrecord"] == nil {
+		t.Errorf("Expected only dnsrecord to fail, got %v", failed)
+	}
+	// Both zones and the records of the public one are still returned.
+	if len(resources) != 9 {
+		t.Errorf("Expected 9 resources, got %v", resourceKeys(resources))
+	}
+}
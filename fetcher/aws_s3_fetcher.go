@@ -0,0 +1,243 @@
+// fetcher/aws_s3_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// maxBucketConcurrency caps how many buckets are described at once.
+const maxBucketConcurrency = 8
+
+func init() {
+	Register(NewFetcher("aws-s3", "aws", ScopeProject, []string{"s3"}, globalAWSFetcher(FetchS3Buckets)))
+}
+
+// FetchS3Buckets lists the S3 buckets of the account and describes the
+// security posture of each one: versioning, default encryption, public access
+// block, whether a bucket policy is attached, and tags. A configuration the
+// credentials may not read is recorded as "access_denied" instead of failing
+// the bucket; other errors are returned alongside the buckets that worked.
+func FetchS3Buckets(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error) {
+	client := s3.NewFromConfig(cfg)
+	log.Println("Fetching S3 buckets...")
+
+	var buckets []types.Bucket
+	paginator := s3.NewListBucketsPaginator(client, &s3.ListBucketsInput{})
+	for paginator.HasMorePages() {
+		page, err := awsPage(ctx, apiAWSS3, paginator.NextPage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list S3 buckets: %w", err)
+		}
+		buckets = append(buckets, page.Buckets...)
+	}
+
+	resources := make([]StandardizedResource, len(buckets))
+	errs := make([]error, len(buckets))
+	sem := make(chan struct{}, maxBucketConcurrency)
+	var wg sync.WaitGroup
+	for i, bucket := range buckets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			resources[i], errs[i] = s3BucketResource(ctx, client, bucket)
+		}()
+	}
+	wg.Wait()
+
+	var described []StandardizedResource
+	for i, res := range resources {
+		if res.ID == "" {
+			continue
+		}
+		if errs[i] != nil {
+			errs[i] = fmt.Errorf("bucket %s: %w", res.ID, errs[i])
+		}
+		described = append(described, res)
+	}
+	log.Printf("Successfully fetched %d S3 buckets.\n", len(described))
+	return described, errors.Join(errs...)
+}
+
+// s3BucketResource describes one bucket. The configuration calls are sent to
+// the bucket's own region, since S3 redirects them from any other.
+func s3BucketResource(ctx context.Context, client *s3.Client, bucket types.Bucket) (StandardizedResource, error) {
+	name := aws.ToString(bucket.Name)
+	res := StandardizedResource{
+		Provider:   "aws",
+		Service:    "s3",
+		Region:     aws.ToString(bucket.BucketRegion),
+		ID:         name,
+		Name:       name,
+		Attributes: map[string]string{"arn": "arn:aws:s3:::" + name},
+	}
+	if bucket.CreationDate != nil {
+		res.Attributes["created"] = bucket.CreationDate.UTC().Format(time.RFC3339)
+	}
+
+	location, err := callAPI(ctx, apiAWSS3, func() (*s3.GetBucketLocationOutput, error) {
+		return client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: aws.String(name)})
+	})
+	switch {
+	case err == nil:
+		res.Region = s3LocationRegion(location.LocationConstraint)
+	case IsPermissionDenied(err) && res.Region != "":
+		// ListBuckets already told us the region; the location is only a confirmation.
+	default:
+		return res, fmt.Errorf("could not get location: %w", err)
+	}
+
+	bucketInput := aws.String(name)
+	inRegion := func(o *s3.Options) { o.Region = res.Region }
+	lookups := []struct {
+		attribute string
+		lookup    func() error
+	}{
+		{"versioning", func() error {
+			out, err := callAPI(ctx, apiAWSS3, func() (*s3.GetBucketVersioningOutput, error) {
+				return client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{Bucket: bucketInput}, inRegion)
+			})
+			if err != nil {
+				return err
+			}
+			res.Attributes["versioning"] = "disabled"
+			if out.Status != "" {
+				res.Attributes["versioning"] = strings.ToLower(string(out.Status))
+			}
+			if out.MFADelete != "" {
+				res.Attributes["mfa_delete"] = strings.ToLower(string(out.MFADelete))
+			}
+			return nil
+		}},
+		{"encryption", func() error {
+			out, err := callAPI(ctx, apiAWSS3, func() (*s3.GetBucketEncryptionOutput, error) {
+				return client.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{Bucket: bucketInput}, inRegion)
+			})
+			if hasAWSErrorCode(err, "ServerSideEncryptionConfigurationNotFoundError") {
+				res.Attributes["encryption"] = "none"
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			res.Attributes["encryption"] = "none"
+			if out.ServerSideEncryptionConfiguration != nil {
+				for _, rule := range out.ServerSideEncryptionConfiguration.Rules {
+					if def := rule.ApplyServerSideEncryptionByDefault; def != nil {
+						res.Attributes["encryption"] = string(def.SSEAlgorithm)
+						if key := aws.ToString(def.KMSMasterKeyID); key != "" {
+							res.Attributes["kms_key"] = key
+						}
+					}
+					res.Attributes["bucket_key"] = fmt.Sprintf("%t", aws.ToBool(rule.BucketKeyEnabled))
+				}
+			}
+			return nil
+		}},
+		{"public_access_block", func() error {
+			out, err := callAPI(ctx, apiAWSS3, func() (*s3.GetPublicAccessBlockOutput, error) {
+				return client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{Bucket: bucketInput}, inRegion)
+			})
+			// A bucket without a public access block blocks nothing itself.
+			block := &types.PublicAccessBlockConfiguration{}
+			switch {
+			case err == nil && out.PublicAccessBlockConfiguration != nil:
+				block = out.PublicAccessBlockConfiguration
+			case err != nil && !hasAWSErrorCode(err, "NoSuchPublicAccessBlockConfiguration"):
+				return err
+			}
+			settings := map[string]bool{
+				"block_public_acls":       aws.ToBool(block.BlockPublicAcls),
+				"ignore_public_acls":      aws.ToBool(block.IgnorePublicAcls),
+				"block_public_policy":     aws.ToBool(block.BlockPublicPolicy),
+				"restrict_public_buckets": aws.ToBool(block.RestrictPublicBuckets),
+			}
+			enabled := 0
+			for key, on := range settings {
+				res.Attributes[key] = fmt.Sprintf("%t", on)
+				if on {
+					enabled++
+				}
+			}
+			switch enabled {
+			case len(settings):
+				res.Attributes["public_access_block"] = "all"
+			case 0:
+				res.Attributes["public_access_block"] = "none"
+			default:
+				res.Attributes["public_access_block"] = "partial"
+			}
+			return nil
+		}},
+		{"has_policy", func() error {
+			_, err := callAPI(ctx, apiAWSS3, func() (*s3.GetBucketPolicyOutput, error) {
+				return client.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{Bucket: bucketInput}, inRegion)
+			})
+			if hasAWSErrorCode(err, "NoSuchBucketPolicy") {
+				res.Attributes["has_policy"] = "false"
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			res.Attributes["has_policy"] = "true"
+			return nil
+		}},
+		{"tags", func() error {
+			out, err := callAPI(ctx, apiAWSS3, func() (*s3.GetBucketTaggingOutput, error) {
+				return client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: bucketInput}, inRegion)
+			})
+			if hasAWSErrorCode(err, "NoSuchTagSet") {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			for _, tag := range out.TagSet {
+				res.Attributes["tag:"+aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			return nil
+		}},
+	}
+
+	var errs []error
+	for _, l := range lookups {
+		err := l.lookup()
+		switch {
+		case err == nil:
+		case IsPermissionDenied(err):
+			res.Attributes[l.attribute] = "access_denied"
+		default:
+			errs = append(errs, fmt.Errorf("could not get %s: %w", l.attribute, err))
+		}
+	}
+	return res, errors.Join(errs...)
+}
+
+// s3LocationRegion converts a GetBucketLocation constraint to a region name.
+// Buckets in us-east-1 have no constraint, and old eu-west-1 buckets report "EU".
+func s3LocationRegion(constraint types.BucketLocationConstraint) string {
+	switch constraint {
+	case "":
+		return "us-east-1"
+	case types.BucketLocationConstraintEu:
+		return "eu-west-1"
+	}
+	return string(constraint)
+}
@@ -0,0 +1,157 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const s3Host = "s3.us-east-1.amazonaws.com"
+
+// s3Routes are the responses of the example buckets, keyed by the bucket's
+// virtual host and operation. acme-logs is locked down and fully tagged,
+// acme-eu-assets is an old "EU" bucket without a public access block, and
+// acme-locked refuses to show its policy and tags.
+var s3Routes = map[string]fakeResponse{
+	"acme-logs.s3.us-east-1.amazonaws.com GET /?location":          {http.StatusOK, "aws/s3/get_bucket_location_us_east_1.xml"},
+	"acme-logs.s3.us-east-1.amazonaws.com GET /?versioning":        {http.StatusOK, "aws/s3/get_bucket_versioning_enabled.xml"},
+	"acme-logs.s3.us-east-1.amazonaws.com GET /?encryption":        {http.StatusOK, "aws/s3/get_bucket_encryption_kms.xml"},
+	"acme-logs.s3.us-east-1.amazonaws.com GET /?publicAccessBlock": {http.StatusOK, "aws/s3/get_public_access_block.xml"},
+	"acme-logs.s3.us-east-1.amazonaws.com GET /?policy":            {http.StatusOK, "aws/s3/get_bucket_policy.json"},
+	"acme-logs.s3.us-east-1.amazonaws.com GET /?tagging":           {http.StatusOK, "aws/s3/get_bucket_tagging.xml"},
+
+	"acme-eu-assets.s3.us-east-1.amazonaws.com GET /?location":          {http.StatusOK, "aws/s3/get_bucket_location_eu.xml"},
+	"acme-eu-assets.s3.eu-west-1.amazonaws.com GET /?versioning":        {http.StatusOK, "aws/s3/get_bucket_versioning_never.xml"},
+	"acme-eu-assets.s3.eu-west-1.amazonaws.com GET /?encryption":        {http.StatusOK, "aws/s3/get_bucket_encryption_s3.xml"},
+	"acme-eu-assets.s3.eu-west-1.amazonaws.com GET /?publicAccessBlock": {http.StatusNotFound, "aws/errors/s3_no_such_public_access_block.xml"},
+	"acme-eu-assets.s3.eu-west-1.amazonaws.com GET /?policy":            {http.StatusNotFound, "aws/errors/s3_no_such_bucket_policy.xml"},
+	"acme-eu-assets.s3.eu-west-1.amazonaws.com GET /?tagging":           {http.StatusNotFound, "aws/errors/s3_no_such_tag_set.xml"},
+
+	"acme-locked.s3.us-east-1.amazonaws.com GET /?location":          {http.StatusForbidden, "aws/errors/s3_access_denied.xml"},
+	"acme-locked.s3.us-west-2.amazonaws.com GET /?versioning":        {http.StatusOK, "aws/s3/get_bucket_versioning_enabled.xml"},
+	"acme-locked.s3.us-west-2.amazonaws.com GET /?encryption":        {http.StatusOK, "aws/s3/get_bucket_encryption_s3.xml"},
+	"acme-locked.s3.us-west-2.amazonaws.com GET /?publicAccessBlock": {http.StatusOK, "aws/s3/get_public_access_block_partial.xml"},
+	"acme-locked.s3.us-west-2.amazonaws.com GET /?policy":            {http.StatusForbidden, "aws/errors/s3_access_denied.xml"},
+	"acme-locked.s3.us-west-2.amazonaws.com GET /?tagging":           {http.StatusForbidden, "aws/errors/s3_access_denied.xml"},
+}
+
+// serveS3 records the example buckets, replacing the responses of the
+// routes in overrides.
+func serveS3(f *fakeCloud, overrides map[string]fakeResponse) {
+	f.handle(s3Host, "GET /", "aws/s3/list_buckets.xml")
+	for route, resp := range s3Routes {
+		if o, ok := overrides[route]; ok {
+			resp = o
+		}
+		host, operation, _ := strings.Cut(route, " ")
+		f.respond(host, operation, resp.status, resp.file)
+	}
+}
+
+func TestFetchS3Buckets(t *testing.T) {
+	f := newFakeCloud(t)
+	serveS3(f, nil)
+
+	resources, err := FetchS3Buckets(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchS3Buckets() error = %v", err)
+	}
+	index := resourceIndex(resources)
+
+	tests := []struct {
+		id     string
+		region string
+		attrs  map[string]string
+	}{
+		{"acme-logs", "us-east-1", map[string]string{
+			"arn":                     "arn:aws:s3:::acme-logs",
+			"created":                 "2021-11-15T12:30:00Z",
+			"versioning":              "enabled",
+			"mfa_delete":              "disabled",
+			"encryption":              "aws:kms",
+			"kms_key":                 "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			"bucket_key":              "true",
+			"public_access_block":     "all",
+			"block_public_acls":       "true",
+			"ignore_public_acls":      "true",
+			"block_public_policy":     "true",
+			"restrict_public_buckets": "true",
+			"has_policy":              "true",
+			"tag:team":                "platform",
+			"tag:env":                 "prod",
+		}},
+		{"acme-eu-assets", "eu-west-1", map[string]string{
+			"arn":                     "arn:aws:s3:::acme-eu-assets",
+			"created":                 "2019-05-20T08:00:00Z",
+			"versioning":              "disabled",
+			"encryption":              "AES256",
+			"bucket_key":              "false",
+			"public_access_block":     "none",
+			"block_public_acls":       "false",
+			"ignore_public_acls":      "false",
+			"block_public_policy":     "false",
+			"restrict_public_buckets": "false",
+			"has_policy":              "false",
+		}},
+		{"acme-locked", "us-west-2", map[string]string{
+			"arn":                     "arn:aws:s3:::acme-locked",
+			"created":                 "2023-01-02T03:04:05Z",
+			"versioning":              "enabled",
+			"mfa_delete":              "disabled",
+			"encryption":              "AES256",
+			"bucket_key":              "false",
+			"public_access_block":     "partial",
+			"block_public_acls":       "true",
+			"ignore_public_acls":      "true",
+			"block_public_policy":     "false",
+			"restrict_public_buckets": "false",
+			"has_policy":              "access_denied",
+			"tags":                    "access_denied",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			res, ok := index["s3/"+tt.id]
+			if !ok {
+				t.Fatalf("Missing bucket %s", tt.id)
+			}
+			if res.Provider != "aws" || res.Name != tt.id || res.Region != tt.region {
+				t.Errorf("Unexpected resource %+v", res)
+			}
+			if !reflect.DeepEqual(res.Attributes, tt.attrs) {
+				t.Errorf("Attributes mismatch\n got: %v\nwant: %v", res.Attributes, tt.attrs)
+			}
+		})
+	}
+}
+
+func TestFetchS3BucketsPartialFailure(t *testing.T) {
+	f := newFakeCloud(t)
+	serveS3(f, map[string]fakeResponse{
+		"acme-logs.s3.us-east-1.amazonaws.com GET /?encryption": {http.StatusInternalServerError, "aws/errors/s3_internal_error.xml"},
+	})
+
+	resources, err := FetchS3Buckets(t.Context(), f.awsConfig("us-east-1"))
+	if err == nil {
+		t.Fatal("FetchS3Buckets() succeeded, want the encryption error")
+	}
+	want := []string{"s3/acme-eu-assets", "s3/acme-locked", "s3/acme-logs"}
+	if got := resourceKeys(resources); !reflect.DeepEqual(got, want) {
+		t.Errorf("Resources mismatch\n got: %v\nwant: %v", got, want)
+	}
+	// The bucket is still returned with the configuration that could be read.
+	if logs := resourceIndex(resources)["s3/acme-logs"]; logs.Attributes["versioning"] != "enabled" || logs.Attributes["encryption"] != "" {
+		t.Errorf("Unexpected attributes %v", logs.Attributes)
+	}
+}
+
+func TestFetchS3BucketsListDenied(t *testing.T) {
+	f := newFakeCloud(t)
+	f.respond(s3Host, "GET /", http.StatusForbidden, "aws/errors/s3_access_denied.xml")
+
+	_, err := FetchS3Buckets(t.Context(), f.awsConfig("us-east-1"))
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+// fetcher/aws_secrets_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smtypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func init() {
+	Register(NewFetcher("aws-secretsmanager", "aws", ScopeProject, []string{"secret"}, regionalAWSFetcher(FetchSecrets)))
+	Register(NewFetcher("aws-ssm", "aws", ScopeProject, []string{"ssmparameter"}, regionalAWSFetcher(FetchSSMParameters)))
+}
+
+// FetchSecrets collects the Secrets Manager secrets in the region of cfg.
+// Only the metadata ListSecrets returns is recorded: the KMS key, rotation
+// settings and dates. Secret values are never read. A secret encrypted with
+// the account's default key has "kms_key" set to "aws/secretsmanager".
+func FetchSecrets(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	client := secretsmanager.NewFromConfig(cfg)
+	region := cfg.Region
+	log.Printf("   -> Fetching Secrets Manager secrets for AWS region: %s", region)
+
+	paginator := secretsmanager.NewListSecretsPaginator(client, &secretsmanager.ListSecretsInput{})
+	if err := eachPage(ctx, apiAWSSecretsManager, paginator.HasMorePages, paginator.NextPage, func(page *secretsmanager.ListSecretsOutput) {
+		for _, secret := range page.SecretList {
+			resources = append(resources, secretResource(secret, region))
+		}
+	}); err != nil {
+		return resources, fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	log.Printf("Successfully fetched %d secrets in %s.\n", len(resources), region)
+	return resources, nil
+}
+
+func secretResource(secret smtypes.SecretListEntry, region string) StandardizedResource {
+	kmsKey := aws.ToString(secret.KmsKeyId)
+	if kmsKey == "" {
+		kmsKey = "aws/secretsmanager"
+	}
+	attributes := map[string]string{
+		"arn":              aws.ToString(secret.ARN),
+		"description":      aws.ToString(secret.Description),
+		"kms_key":          kmsKey,
+		"rotation_enabled": fmt.Sprintf("%t", aws.ToBool(secret.RotationEnabled)),
+		"rotation_lambda":  aws.ToString(secret.RotationLambdaARN),
+		"primary_region":   aws.ToString(secret.PrimaryRegion),
+		"owning_service":   aws.ToString(secret.OwningService),
+	}
+	if rules := secret.RotationRules; rules != nil {
+		if days := aws.ToInt64(rules.AutomaticallyAfterDays); days > 0 {
+			attributes["rotation_days"] = strconv.FormatInt(days, 10)
+		}
+		attributes["rotation_schedule"] = aws.ToString(rules.ScheduleExpression)
+	}
+	for key, value := range map[string]*time.Time{
+		"created":       secret.CreatedDate,
+		"last_changed":  secret.LastChangedDate,
+		"last_accessed": secret.LastAccessedDate,
+		"last_rotated":  secret.LastRotatedDate,
+		"next_rotation": secret.NextRotationDate,
+		"deleted":       secret.DeletedDate,
+	} {
+		if value != nil {
+			attributes[key] = value.UTC().Format(time.RFC3339)
+		}
+	}
+	for _, tag := range secret.Tags {
+		attributes["tag:"+aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	return StandardizedResource{
+		Provider:   "aws",
+		Service:    "secret",
+		Region:     region,
+		ID:         aws.ToString(secret.ARN),
+		Name:       aws.ToString(secret.Name),
+		Attributes: dropEmpty(attributes),
+	}
+}
+
+// FetchSSMParameters collects the Systems Manager Parameter Store entries in
+// the region of cfg from DescribeParameters, which returns metadata only;
+// parameter values are never read. Parameters are identified by their ARN,
+// since the same name is often used in several regions. A parameter with an
+// expiration policy records when it expires in "expires".
+func FetchSSMParameters(ctx context.Context, cfg aws.Config) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	client := ssm.NewFromConfig(cfg)
+	region := cfg.Region
+	log.Printf("   -> Fetching SSM parameters for AWS region: %s", region)
+
+	paginator := ssm.NewDescribeParametersPaginator(client, &ssm.DescribeParametersInput{})
+	if err := eachPage(ctx, apiAWSSSM, paginator.HasMorePages, paginator.NextPage, func(page *ssm.DescribeParametersOutput) {
+		for _, parameter := range page.Parameters {
+			resources = append(resources, ssmParameterResource(parameter, region))
+		}
+	}); err != nil {
+		return resources, fmt.Errorf("failed to describe SSM parameters: %w", err)
+	}
+
+	log.Printf("Successfully fetched %d SSM parameters in %s.\n", len(resources), region)
+	return resources, nil
+}
+
+func ssmParameterResource(parameter ssmtypes.ParameterMetadata, region string) StandardizedResource {
+	attributes := map[string]string{
+		"arn":                aws.ToString(parameter.ARN),
+		"type":               string(parameter.Type),
+		"tier":               string(parameter.Tier),
+		"data_type":          aws.ToString(parameter.DataType),
+		"description":        aws.ToString(parameter.Description),
+		"kms_key":            aws.ToString(parameter.KeyId),
+		"version":            strconv.FormatInt(parameter.Version, 10),
+		"last_modified_user": aws.ToString(parameter.LastModifiedUser),
+	}
+	if parameter.LastModifiedDate != nil {
+		attributes["last_modified"] = parameter.LastModifiedDate.UTC().Format(time.RFC3339)
+	}
+	var policies []string
+	for _, policy := range parameter.Policies {
+		policies = append(policies, aws.ToString(policy.PolicyType))
+		if aws.ToString(policy.PolicyType) != "Expiration" {
+			continue
+		}
+		var text struct {
+			Attributes struct {
+				Timestamp string `json:"Timestamp"`
+			} `json:"Attributes"`
+		}
+		if json.Unmarshal([]byte(aws.ToString(policy.PolicyText)), &text) != nil {
+			continue
+		}
+		if expires, err := time.Parse(time.RFC3339, text.Attributes.Timestamp); err == nil {
+			attributes["expires"] = expires.UTC().Format(time.RFC3339)
+		}
+	}
+	attributes["policies"] = strings.Join(policies, ", ")
+
+	return StandardizedResource{Provider: "aws", Service: "ssmparameter", Region: region, ID: aws.ToString(parameter.ARN), Name: aws.ToString(parameter.Name), Attributes: dropEmpty(attributes)}
+}
@@ -0,0 +1,145 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const (
+	secretsManagerHost = "secretsmanager.us-east-1.amazonaws.com"
+	ssmHost            = "ssm.us-east-1.amazonaws.com"
+)
+
+func TestFetchSecrets(t *testing.T) {
+	f := newFakeCloud(t)
+	f.handle(secretsManagerHost, "secretsmanager.ListSecrets", "aws/secretsmanager/list_secrets.json")
+
+	resources, err := FetchSecrets(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchSecrets() error = %v", err)
+	}
+	if calls := f.calls(secretsManagerHost, "secretsmanager.GetSecretValue"); calls != 0 {
+		t.Errorf("Secret values were read %d times", calls)
+	}
+	index := resourceIndex(resources)
+
+	tests := []struct {
+		id    string
+		name  string
+		attrs map[string]string
+	}{
+		{"arn:aws:secretsmanager:us-east-1:123456789012:secret:prod/orders/db-AbCdEf", "prod/orders/db", map[string]string{
+			"arn":              "arn:aws:secretsmanager:us-east-1:123456789012:secret:prod/orders/db-AbCdEf",
+			"description":      "Orders database credentials",
+			"kms_key":          "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			"rotation_enabled": "true",
+			"rotation_lambda":  "arn:aws:lambda:us-east-1:123456789012:function:rotate-orders-db",
+			"rotation_days":    "30",
+			"created":          "2023-11-02T09:15:00Z",
+			"last_changed":     "2025-06-15T10:00:00Z",
+			"last_accessed":    "2025-06-29T00:00:00Z",
+			"last_rotated":     "2025-06-15T10:00:00Z",
+			"next_rotation":    "2025-07-15T00:00:00Z",
+			"tag:team":         "orders",
+		}},
+		{"arn:aws:secretsmanager:us-east-1:123456789012:secret:legacy/api-key-GhIjKl", "legacy/api-key", map[string]string{
+			"arn":              "arn:aws:secretsmanager:us-east-1:123456789012:secret:legacy/api-key-GhIjKl",
+			"kms_key":          "aws/secretsmanager",
+			"rotation_enabled": "false",
+			"created":          "2023-11-02T09:15:00Z",
+			"last_changed":     "2023-11-02T09:15:00Z",
+		}},
+	}
+	if len(resources) != len(tests) {
+		t.Errorf("Expected %d secrets, got %v", len(tests), resourceKeys(resources))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, ok := index["secret/"+tt.id]
+			if !ok {
+				t.Fatalf("Missing secret %s", tt.id)
+			}
+			if res.Provider != "aws" || res.Name != tt.name || res.Region != "us-east-1" {
+				t.Errorf("Unexpected resource %+v", res)
+			}
+			if !reflect.DeepEqual(res.Attributes, tt.attrs) {
+				t.Errorf("Attributes mismatch\n got: %v\nwant: %v", res.Attributes, tt.attrs)
+			}
+		})
+	}
+}
+
+func TestFetchSSMParameters(t *testing.T) {
+	f := newFakeCloud(t)
+	f.handle(ssmHost, "AmazonSSM.DescribeParameters", "aws/ssm/describe_parameters.json")
+
+	resources, err := FetchSSMParameters(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchSSMParameters() error = %v", err)
+	}
+	for _, op := range []string{"AmazonSSM.GetParameter", "AmazonSSM.GetParameters", "AmazonSSM.GetParametersByPath"} {
+		if calls := f.calls(ssmHost, op); calls != 0 {
+			t.Errorf("Parameter values were read %d times with %s", calls, op)
+		}
+	}
+	index := resourceIndex(resources)
+
+	tests := []struct {
+		name  string
+		attrs map[string]string
+	}{
+		{"/orders/db-host", map[string]string{
+			"arn":                "arn:aws:ssm:us-east-1:123456789012:parameter/orders/db-host",
+			"type":               "String",
+			"tier":               "Standard",
+			"data_type":          "text",
+			"version":            "3",
+			"last_modified":      "2025-05-12T18:45:00Z",
+			"last_modified_user": "arn:aws:iam::123456789012:user/alice",
+		}},
+		{"/orders/api-token", map[string]string{
+			"arn":                "arn:aws:ssm:us-east-1:123456789012:parameter/orders/api-token",
+			"type":               "SecureString",
+			"tier":               "Advanced",
+			"data_type":          "text",
+			"description":        "Token for the payments API",
+			"kms_key":            "alias/aws/ssm",
+			"version":            "7",
+			"last_modified":      "2025-06-29T14:30:00Z",
+			"last_modified_user": "arn:aws:sts::123456789012:assumed-role/ci-deployer/build",
+			"policies":           "Expiration, ExpirationNotification",
+			"expires":            "2025-08-01T00:00:00Z",
+		}},
+	}
+	if len(resources) != len(tests) {
+		t.Errorf("Expected %d parameters, got %v", len(tests), resourceKeys(resources))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, ok := index["ssmparameter/"+tt.attrs["arn"]]
+			if !ok {
+				t.Fatalf("Missing parameter %s", tt.name)
+			}
+			if res.Provider != "aws" || res.Name != tt.name || res.Region != "us-east-1" {
+				t.Errorf("Unexpected resource %+v", res)
+			}
+			if !reflect.DeepEqual(res.Attributes, tt.attrs) {
+				t.Errorf("Attributes mismatch\n got: %v\nwant: %v", res.Attributes, tt.attrs)
+			}
+		})
+	}
+}
+
+func TestFetchSSMParametersDenied(t *testing.T) {
+	f := newFakeCloud(t)
+	f.respond(ssmHost, "AmazonSSM.DescribeParameters", http.StatusBadRequest, "aws/errors/ssm_access_denied.json")
+
+	resources, err := FetchSSMParameters(t.Context(), f.awsConfig("us-east-1"))
+	if !IsPermissionDenied(err) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
+	if len(resources) != 0 {
+		t.Errorf("Expected no parameters, got %v", resourceKeys(resources))
+	}
+}
@@ -0,0 +1,145 @@
+// fetcher/dns_resolver.go
+package fetcher
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DNSLink is one hop in resolving a hostname: a record, one of its values
+// and, when the value belongs to a collected resource, that resource. When
+// several resources hold the value, as reused private IPs often are, all of
+// them are listed in Candidates.
+type DNSLink struct {
+	Record     StandardizedResource   `json:"record"`
+	Value      string                 `json:"value"`
+	Target     *StandardizedResource  `json:"target,omitempty"`
+	Candidates []StandardizedResource `json:"candidates,omitempty"`
+	Depth      int                    `json:"depth"`
+}
+
+// ResolveDNSName answers "what does this hostname point at?" from collected
+// resources. The A, AAAA, CNAME and alias records of hostname, in Route 53
+// or Cloud DNS zones, are followed through any CNAMEs to other collected
+// records, and every value is matched against the addresses and hostnames
+// of load balancers, GCP forwarding rules, EC2 and Compute Engine instances,
+// RDS databases and Cloud Run services. A value held by several resources
+// resolves to the one in the record's own account or project, or to none
+// when that is still ambiguous; a resource elsewhere is only taken when it
+// is the sole match. Each record value yields one DNSLink, in the order they
+// were followed; Depth counts the CNAME hops from hostname.
+func ResolveDNSName(resources []StandardizedResource, hostname string) []DNSLink {
+	records := make(map[string][]StandardizedResource)
+	targets := make(map[string][]StandardizedResource)
+	addTarget := func(key string, res StandardizedResource) {
+		if key = dnsName(key); key == "" {
+			return
+		}
+		for _, existing := range targets[key] {
+			if existing.Service == res.Service && existing.ID == res.ID {
+				return
+			}
+		}
+		targets[key] = append(targets[key], res)
+	}
+	for _, res := range resources {
+		switch res.Service {
+		case "dnsrecord":
+			switch res.Attributes["type"] {
+			case "A", "AAAA", "CNAME":
+				records[dnsName(res.Name)] = append(records[dnsName(res.Name)], res)
+			}
+		case "elb":
+			addTarget(res.Attributes["dns_name"], res)
+			for _, ip := range splitList(res.Attributes["ip_addresses"]) {
+				addTarget(ip, res)
+			}
+		case "forwardingrule":
+			addTarget(res.Attributes["ip_address"], res)
+		case "ec2", "gce":
+			for _, key := range []string{"public_ip", "private_ip", "public_dns", "private_dns"} {
+				addTarget(res.Attributes[key], res)
+			}
+		case "rds":
+			addTarget(res.Attributes["endpoint"], res)
+			addTarget(res.Attributes["reader_endpoint"], res)
+		case "cloudrun":
+			if u, err := url.Parse(res.Attributes["url"]); err == nil {
+				addTarget(u.Hostname(), res)
+			}
+		}
+	}
+	for _, list := range records {
+		sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	}
+
+	var links []DNSLink
+	visited := make(map[string]bool)
+	var follow func(name string, depth int)
+	follow = func(name string, depth int) {
+		// Each name is followed once, so a CNAME loop ends.
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, record := range records[name] {
+			values := splitList(record.Attributes["values"])
+			if alias := record.Attributes["alias_target"]; alias != "" {
+				values = []string{alias}
+			}
+			for _, value := range values {
+				link := DNSLink{Record: record, Value: value, Depth: depth}
+				key := dnsName(value)
+				candidates, ok := targets[key]
+				if !ok {
+					// Route 53 aliases to load balancers may carry a "dualstack." prefix.
+					candidates, ok = targets[strings.TrimPrefix(key, "dualstack.")]
+				}
+				if len(candidates) > 1 {
+					link.Candidates = candidates
+				}
+				link.Target = pickDNSTarget(record, candidates)
+				links = append(links, link)
+				if !ok {
+					follow(key, depth+1)
+				}
+			}
+		}
+	}
+	follow(dnsName(hostname), 0)
+	return links
+}
+
+// pickDNSTarget chooses which of the resources holding a record's value the
+// record points at: the only one in the record's account or project, or the
+// only candidate at all. It returns nil when the choice is ambiguous.
+func pickDNSTarget(record StandardizedResource, candidates []StandardizedResource) *StandardizedResource {
+	var local []StandardizedResource
+	for _, res := range candidates {
+		if res.Provider == record.Provider && resourceOwner(res) != "" && resourceOwner(res) == resourceOwner(record) {
+			local = append(local, res)
+		}
+	}
+	switch {
+	case len(local) == 1:
+		return &local[0]
+	case len(local) == 0 && len(candidates) == 1:
+		return &candidates[0]
+	}
+	return nil
+}
+
+// resourceOwner is the AWS account or GCP project a resource belongs to.
+func resourceOwner(res StandardizedResource) string {
+	if id := res.Attributes["account_id"]; id != "" {
+		return id
+	}
+	return res.Attributes["project_id"]
+}
+
+// dnsName normalizes a DNS name for comparison: lower case, without the
+// trailing dot of a fully qualified name.
+func dnsName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+}
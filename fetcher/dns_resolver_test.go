@@ -0,0 +1,113 @@
+package fetcher
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestResolveDNSName(t *testing.T) {
+	f := newFakeCloud(t)
+	f.handle(route53Host, "GET /2013-04-01/hostedzone", "aws/route53/list_hosted_zones.xml")
+	f.handle(route53Host, publicRRSets, "aws/route53/list_records_public.xml")
+	f.handle(route53Host, privateRRSets, "aws/route53/list_records_private.xml")
+	serveELB(f)
+	serveWAF(f)
+	serveDemoProject(f, nil)
+
+	resources, err := FetchRoute53Records(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchRoute53Records() error = %v", err)
+	}
+	loadBalancers, err := FetchLoadBalancers(t.Context(), f.awsConfig("us-east-1"))
+	if err != nil {
+		t.Fatalf("FetchLoadBalancers() error = %v", err)
+	}
+	project, err := FetchGCPSingleProject(t.Context(), "demo-project", f.gcpOptions()...)
+	if err != nil {
+		t.Fatalf("FetchGCPSingleProject() error = %v", err)
+	}
+	resources = append(resources, loadBalancers...)
+	resources = append(resources, project...)
+	resources = append(resources,
+		StandardizedResource{Provider: "aws", Service: "rds", ID: "arn:aws:rds:us-east-1:123456789012:cluster:orders-db", Name: "orders-db",
+			Attributes: map[string]string{"endpoint": "orders-db.cluster-c9akciq32.us-east-1.rds.amazonaws.com"}},
+		// Two records pointing at each other.
+		StandardizedResource{Provider: "aws", Service: "dnsrecord", ID: "Z1/loop-a.example.org/CNAME", Name: "loop-a.example.org",
+			Attributes: map[string]string{"type": "CNAME", "values": "loop-b.example.org."}},
+		StandardizedResource{Provider: "aws", Service: "dnsrecord", ID: "Z1/loop-b.example.org/CNAME", Name: "loop-b.example.org",
+			Attributes: map[string]string{"type": "CNAME", "values": "loop-a.example.org."}},
+		// The same private IP in two accounts.
+		StandardizedResource{Provider: "aws", Service: "ec2", ID: "i-0aaa", Name: "db-a",
+			Attributes: map[string]string{"account_id": "111111111111", "private_ip": "10.0.0.5"}},
+		StandardizedResource{Provider: "aws", Service: "ec2", ID: "i-0bbb", Name: "db-b",
+			Attributes: map[string]string{"account_id": "222222222222", "private_ip": "10.0.0.5"}},
+		StandardizedResource{Provider: "aws", Service: "dnsrecord", ID: "Z2/db.a.example.org/A", Name: "db.a.example.org",
+			Attributes: map[string]string{"account_id": "111111111111", "type": "A", "values": "10.0.0.5"}},
+		StandardizedResource{Provider: "aws", Service: "dnsrecord", ID: "Z3/db.c.example.org/A", Name: "db.c.example.org",
+			Attributes: map[string]string{"account_id": "333333333333", "type": "A", "values": "10.0.0.5"}},
+	)
+
+	tests := []struct {
+		hostname string
+		want     []string
+	}{
+		{"www.example.com", []string{
+			"0 www.example.com dualstack.web-alb-1234567890.us-east-1.elb.amazonaws.com -> elb/" + webALB,
+		}},
+		{"WWW.Example.com.", []string{
+			"0 www.example.com dualstack.web-alb-1234567890.us-east-1.elb.amazonaws.com -> elb/" + webALB,
+		}},
+		{"*.example.com", []string{
+			"0 *.example.com www.example.com",
+			"1 www.example.com dualstack.web-alb-1234567890.us-east-1.elb.amazonaws.com -> elb/" + webALB,
+		}},
+		{"api.example.com", []string{
+			"0 api.example.com 34.120.1.10 -> forwardingrule/web-https",
+			"0 api.example.com 203.0.113.25",
+		}},
+		{"app.example.com", []string{
+			"0 app.example.com app.demo.example.com.",
+			"1 app.demo.example.com web-abc123-uc.a.run.app. -> cloudrun/web",
+		}},
+		{"failover.demo.example.com", []string{
+			"0 failover.demo.example.com 10.128.0.50",
+			"0 failover.demo.example.com 34.120.1.10 -> forwardingrule/web-https",
+		}},
+		{"db.internal.example.com", []string{
+			"0 db.internal.example.com orders-db.cluster-c9akciq32.us-east-1.rds.amazonaws.com -> rds/arn:aws:rds:us-east-1:123456789012:cluster:orders-db",
+		}},
+		{"loop-a.example.org", []string{
+			"0 loop-a.example.org loop-b.example.org.",
+			"1 loop-b.example.org loop-a.example.org.",
+		}},
+		{"db.a.example.org", []string{
+			"0 db.a.example.org 10.0.0.5 -> ec2/i-0aaa among ec2/i-0aaa ec2/i-0bbb",
+		}},
+		{"db.c.example.org", []string{
+			"0 db.c.example.org 10.0.0.5 among ec2/i-0aaa ec2/i-0bbb",
+		}},
+		{"unknown.example.com", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hostname, func(t *testing.T) {
+			var got []string
+			for _, link := range ResolveDNSName(resources, tt.hostname) {
+				line := fmt.Sprintf("%d %s %s", link.Depth, link.Record.Name, link.Value)
+				if link.Target != nil {
+					line += " -> " + link.Target.Service + "/" + link.Target.ID
+				}
+				if len(link.Candidates) > 0 {
+					line += " among"
+					for _, candidate := range link.Candidates {
+						line += " " + candidate.Service + "/" + candidate.ID
+					}
+				}
+				got = append(got, line)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Links mismatch\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// fetcher/errors.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/aws/smithy-go"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceError reports that one service of a multi-service Fetcher could not
+// be collected. Fetchers join these with errors.Join and still return the
+// resources of the services that worked.
+type ServiceError struct {
+	Service string
+	Err     error
+}
+
+func (e *ServiceError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Service, e.Err)
+}
+
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// FailedServices returns the per-service errors contained in err, keyed by
+// service. It returns an empty map when err does not carry any ServiceError,
+// or also carries an error outside of one, meaning the whole Fetcher failed:
+// a region that failed outright leaves every service of it unknown.
+func FailedServices(err error) map[string]error {
+	failed := make(map[string]error)
+	// walk reports whether every failure in err is scoped to a service.
+	var walk func(error) bool
+	walk = func(err error) bool {
+		switch e := err.(type) {
+		case nil:
+			return true
+		case *ServiceError:
+			failed[e.Service] = e.Err
+			return true
+		case interface{ Unwrap() []error }:
+			scoped := true
+			for _, inner := range e.Unwrap() {
+				scoped = walk(inner) && scoped
+			}
+			return scoped
+		case interface{ Unwrap() error }:
+			return walk(e.Unwrap())
+		}
+		return false
+	}
+	if !walk(err) {
+		return map[string]error{}
+	}
+	return failed
+}
+
+// awsPermissionCodes are the AWS error codes returned when the caller's
+// credentials are not allowed to make a request.
+var awsPermissionCodes = map[string]bool{
+	"AccessDenied":          true,
+	"AccessDeniedException": true,
+	"UnauthorizedOperation": true,
+	"UnauthorizedAccess":    true,
+	"AuthorizationError":    true,
+}
+
+// hasAWSErrorCode reports whether err is an AWS API error with one of codes.
+func hasAWSErrorCode(err error, codes ...string) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.ErrorCode() == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPermissionDenied reports whether err means the credentials lack access,
+// as opposed to the API failing. GCP "API not enabled" errors also count,
+// since both mean the project could not be read rather than being empty.
+func IsPermissionDenied(err error) bool {
+	if err == nil {
+		return false
+	}
+	var gErr *googleapi.Error
+	if errors.As(err, &gErr) {
+		return gErr.Code == http.StatusForbidden && !IsThrottled(err)
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return awsPermissionCodes[apiErr.ErrorCode()]
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Code() == codes.PermissionDenied
+	}
+	return false
+}
+
+// gcpRateLimitReasons are the googleapi error reasons GCP uses for quota
+// errors. Some APIs return them with a 403 rather than a 429.
+var gcpRateLimitReasons = map[string]bool{
+	"rateLimitExceeded":     true,
+	"userRateLimitExceeded": true,
+	"RATE_LIMIT_EXCEEDED":   true,
+}
+
+// awsThrottleCodes are the AWS error codes returned when a request was throttled.
+var awsThrottleCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottled":                       true,
+	"RequestThrottledException":              true,
+	"RequestLimitExceeded":                   true,
+	"TooManyRequestsException":               true,
+	"SlowDown":                               true,
+	"ProvisionedThroughputExceededException": true,
+	"BandwidthLimitExceeded":                 true,
+	"PriorRequestNotComplete":                true,
+	"EC2ThrottledException":                  true,
+}
+
+// IsThrottled reports whether err is a quota or rate limit error.
+func IsThrottled(err error) bool {
+	if err == nil {
+		return false
+	}
+	var gErr *googleapi.Error
+	if errors.As(err, &gErr) {
+		if gErr.Code == http.StatusTooManyRequests {
+			return true
+		}
+		for _, item := range gErr.Errors {
+			if gcpRateLimitReasons[item.Reason] {
+				return true
+			}
+		}
+		return false
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return awsThrottleCodes[apiErr.ErrorCode()]
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Code() == codes.ResourceExhausted
+	}
+	return false
+}
+
+// IsRetryable reports whether a failed API call is worth repeating: it was
+// throttled, the service returned a 5xx, or the connection failed.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if IsThrottled(err) {
+		return true
+	}
+	var gErr *googleapi.Error
+	if errors.As(err, &gErr) {
+		return gErr.Code >= http.StatusInternalServerError
+	}
+	var httpErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &httpErr) {
+		return httpErr.HTTPStatusCode() >= http.StatusInternalServerError
+	}
+	if s, ok := status.FromError(err); ok && s.Code() == codes.Unavailable {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
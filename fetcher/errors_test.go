@@ -0,0 +1,148 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFailedServices(t *testing.T) {
+	vpcErr := errors.New("vpc boom")
+	firewallErr := errors.New("firewall boom")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected map[string]error
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			expected: map[string]error{},
+		},
+		{
+			name:     "plain error fails the whole fetcher",
+			err:      errors.New("boom"),
+			expected: map[string]error{},
+		},
+		{
+			name: "joined service errors",
+			err: errors.Join(
+				&ServiceError{Service: "vpc", Err: vpcErr},
+				&ServiceError{Service: "firewall", Err: firewallErr},
+			),
+			expected: map[string]error{"vpc": vpcErr, "firewall": firewallErr},
+		},
+		{
+			name: "service error joined with a plain error fails the whole fetcher",
+			err: errors.Join(
+				fmt.Errorf("region us-east-1: %w", &ServiceError{Service: "vpc", Err: vpcErr}),
+				fmt.Errorf("region eu-west-1: %w", errors.New("no credentials")),
+			),
+			expected: map[string]error{},
+		},
+		{
+			name:     "wrapped service error",
+			err:      fmt.Errorf("project p1: %w", &ServiceError{Service: "vpc", Err: vpcErr}),
+			expected: map[string]error{"vpc": vpcErr},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failed := FailedServices(tt.err)
+			if len(failed) != len(tt.expected) {
+				t.Fatalf("Expected %d failed services, got %d (%v)", len(tt.expected), len(failed), failed)
+			}
+			for service, err := range tt.expected {
+				if failed[service] != err {
+					t.Errorf("Service %s: expected %v, got %v", service, err, failed[service])
+				}
+			}
+		})
+	}
+}
+
+func TestIsPermissionDenied(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("connection reset"), false},
+		{"GCP 403", &googleapi.Error{Code: 403, Message: "The caller does not have permission"}, true},
+		{"GCP 403 wrapped", fmt.Errorf("could not list networks: %w", &googleapi.Error{Code: 403}), true},
+		{"GCP 429", &googleapi.Error{Code: 429, Message: "rateLimitExceeded"}, false},
+		{"GCP 403 rate limit", &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}, false},
+		{"gRPC permission denied", status.Error(codes.PermissionDenied, "denied"), true},
+		{"gRPC unavailable", status.Error(codes.Unavailable, "try again"), false},
+		{"AWS access denied", &smithy.GenericAPIError{Code: "AccessDenied", Message: "not authorized"}, true},
+		{"AWS unauthorized operation", fmt.Errorf("page: %w", &smithy.GenericAPIError{Code: "UnauthorizedOperation"}), true},
+		{"AWS throttling", &smithy.GenericAPIError{Code: "Throttling"}, false},
+		{"service error wrapping a 403", &ServiceError{Service: "vpc", Err: &googleapi.Error{Code: 403}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPermissionDenied(tt.err); got != tt.expected {
+				t.Errorf("IsPermissionDenied(%v) = %v, expected %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStatusFor(t *testing.T) {
+	if StatusFor(nil) != StatusSucceeded {
+		t.Error("nil error should be succeeded")
+	}
+	if StatusFor(&googleapi.Error{Code: 403}) != StatusSkipped {
+		t.Error("permission error should be skipped")
+	}
+	if StatusFor(errors.New("boom")) != StatusFailed {
+		t.Error("other errors should be failed")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+		throttled bool
+	}{
+		{"nil", nil, false, false},
+		{"plain error", errors.New("boom"), false, false},
+		{"context cancelled", context.Canceled, false, false},
+		{"GCP 429", &googleapi.Error{Code: 429}, true, true},
+		{"GCP 403 user rate limit", &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "userRateLimitExceeded"}}}, true, true},
+		{"GCP 403 forbidden", &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}}, false, false},
+		{"GCP 500", &googleapi.Error{Code: 500}, true, false},
+		{"GCP 404", &googleapi.Error{Code: 404}, false, false},
+		{"gRPC resource exhausted", status.Error(codes.ResourceExhausted, "quota"), true, true},
+		{"gRPC unavailable", status.Error(codes.Unavailable, "try again"), true, false},
+		{"gRPC not found", status.Error(codes.NotFound, "nope"), false, false},
+		{"AWS throttling", &smithy.GenericAPIError{Code: "Throttling"}, true, true},
+		{"AWS request limit", fmt.Errorf("page: %w", &smithy.GenericAPIError{Code: "RequestLimitExceeded"}), true, true},
+		{"AWS access denied", &smithy.GenericAPIError{Code: "AccessDenied"}, false, false},
+		{"network error", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.retryable {
+				t.Errorf("IsRetryable(%v) = %v, expected %v", tt.err, got, tt.retryable)
+			}
+			if got := IsThrottled(tt.err); got != tt.throttled {
+				t.Errorf("IsThrottled(%v) = %v, expected %v", tt.err, got, tt.throttled)
+			}
+		})
+	}
+}
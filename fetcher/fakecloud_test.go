@@ -0,0 +1,248 @@
+package fetcher
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"google.golang.org/api/option"
+)
+
+// fakeCloud is an httptest server that stands in for the GCP and AWS APIs.
+// Clients built from gcpOptions and awsConfig keep their real endpoints; a
+// rewriting transport sends every request to the server with the original
+// Host header, so one server can serve every API and region.
+//
+// Responses are recorded API payloads stored under testdata/. A route is
+// keyed by host and operation, where the operation is:
+//
+//   - "METHOD /path" for Google REST APIs, e.g. "GET /compute/v1/projects/p1/global/networks",
+//     and for S3 followed by the subresource, e.g. "GET /?versioning"
+//   - the Action of AWS query APIs (EC2, IAM, ...), e.g. "DescribeInstances"
+//   - the X-Amz-Target header of AWS JSON APIs
+//
+// A page token (pageToken, NextToken, nextToken, Marker or continuation-token) in the request is appended
+// as "?token=<value>" so that paginated responses can be served in order.
+type fakeCloud struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu       sync.Mutex
+	routes   map[string][]fakeResponse
+	requests []string
+}
+
+type fakeResponse struct {
+	status int
+	file   string
+}
+
+func newFakeCloud(t *testing.T) *fakeCloud {
+	t.Helper()
+	f := &fakeCloud{t: t, routes: make(map[string][]fakeResponse)}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.server.Close)
+	useTestThrottle(t)
+	return f
+}
+
+// handle serves the testdata file for host and operation with a 200.
+func (f *fakeCloud) handle(host, operation, file string) {
+	f.respond(host, operation, http.StatusOK, file)
+}
+
+// respond queues a response for host and operation. When several responses
+// are queued for the same route they are served in order and the last one
+// is repeated, which lets tests script a throttled call followed by success.
+func (f *fakeCloud) respond(host, operation string, status int, file string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := host + " " + operation
+	f.routes[key] = append(f.routes[key], fakeResponse{status: status, file: file})
+}
+
+// calls returns how many requests were made for host and operation.
+func (f *fakeCloud) calls(host, operation string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, r := range f.requests {
+		if r == host+" "+operation {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *fakeCloud) serve(w http.ResponseWriter, r *http.Request) {
+	key := r.Host + " " + fakeOperation(r)
+
+	f.mu.Lock()
+	f.requests = append(f.requests, key)
+	queue := f.routes[key]
+	var resp fakeResponse
+	if len(queue) > 0 {
+		resp = queue[0]
+		if len(queue) > 1 {
+			f.routes[key] = queue[1:]
+		}
+	}
+	f.mu.Unlock()
+
+	if resp.file == "" {
+		f.t.Errorf("fakeCloud: no response recorded for %q", key)
+		http.Error(w, `{"error": {"code": 404, "message": "no fixture"}}`, http.StatusNotFound)
+		return
+	}
+	body, err := os.ReadFile(filepath.Join("testdata", resp.file))
+	if err != nil {
+		f.t.Errorf("fakeCloud: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	switch filepath.Ext(resp.file) {
+	case ".xml":
+		w.Header().Set("Content-Type", "text/xml")
+	default:
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(resp.status)
+	w.Write(body)
+}
+
+// fakeOperation derives the route operation of a request, as described on fakeCloud.
+func fakeOperation(r *http.Request) string {
+	if target := r.Header.Get("X-Amz-Target"); target != "" {
+		return target + fakeToken(jsonToken(r))
+	}
+	if r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		r.ParseForm()
+		token := r.PostForm.Get("NextToken")
+		if token == "" {
+			token = r.PostForm.Get("Marker")
+		}
+		return r.PostForm.Get("Action") + fakeToken(token)
+	}
+	query := r.URL.Query()
+	token := query.Get("pageToken")
+	if token == "" {
+		token = query.Get("marker")
+	}
+	if token == "" {
+		token = query.Get("Marker")
+	}
+	if token == "" {
+		token = query.Get("nextToken")
+	}
+	if token == "" {
+		token = query.Get("continuation-token")
+	}
+	// S3 selects a bucket's configuration with a valueless parameter, as in "/?location".
+	subresource := ""
+	for key, values := range query {
+		if len(values) == 1 && values[0] == "" {
+			subresource = "?" + key
+		}
+	}
+	return r.Method + " " + r.URL.Path + subresource + fakeToken(token)
+}
+
+func fakeToken(token string) string {
+	if token == "" {
+		return ""
+	}
+	return "?token=" + token
+}
+
+// jsonToken extracts a "NextToken" field from an AWS JSON request body
+// without consuming it for later readers.
+func jsonToken(r *http.Request) string {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return ""
+	}
+	r.Body = io.NopCloser(strings.NewReader(string(body)))
+	const field = `"NextToken":"`
+	i := strings.Index(string(body), field)
+	if i < 0 {
+		return ""
+	}
+	rest := string(body[i+len(field):])
+	if j := strings.IndexByte(rest, '"'); j >= 0 {
+		return rest[:j]
+	}
+	return ""
+}
+
+// client returns an HTTP client whose requests all reach the fake server.
+func (f *fakeCloud) client() *http.Client {
+	target, _ := url.Parse(f.server.URL)
+	return &http.Client{Transport: &rewriteTransport{target: target, base: http.DefaultTransport}}
+}
+
+// gcpOptions returns client options that send Google API calls to the fake server.
+func (f *fakeCloud) gcpOptions() []option.ClientOption {
+	return []option.ClientOption{option.WithHTTPClient(f.client())}
+}
+
+// awsConfig returns an AWS configuration for region that sends calls to the fake server.
+func (f *fakeCloud) awsConfig(region string) aws.Config {
+	return aws.Config{
+		Region:      region,
+		Credentials: credentials.NewStaticCredentialsProvider("AKIDTEST", "secret", ""),
+		HTTPClient:  f.client(),
+		Retryer:     func() aws.Retryer { return aws.NopRetryer{} },
+	}
+}
+
+type rewriteTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (rt *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.Host = req.URL.Host
+	r.URL.Scheme = rt.target.Scheme
+	r.URL.Host = rt.target.Host
+	return rt.base.RoundTrip(r)
+}
+
+// useTestThrottle installs a Throttle with millisecond backoff and no rate
+// limits for the duration of the test.
+func useTestThrottle(t *testing.T) *Throttle {
+	th := NewThrottle(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}, nil)
+	previous := CurrentThrottle()
+	SetThrottle(th)
+	t.Cleanup(func() { SetThrottle(previous) })
+	return th
+}
+
+// resourceIndex indexes resources by "service/id" for assertions.
+func resourceIndex(resources []StandardizedResource) map[string]StandardizedResource {
+	index := make(map[string]StandardizedResource)
+	for _, res := range resources {
+		index[res.Service+"/"+res.ID] = res
+	}
+	return index
+}
+
+// resourceKeys returns the sorted "service/id" keys of resources.
+func resourceKeys(resources []StandardizedResource) []string {
+	var keys []string
+	for _, res := range resources {
+		keys = append(keys, res.Service+"/"+res.ID)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -3,45 +3,61 @@ package fetcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 
 	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
 )
 
+func init() {
+	Register(NewFetcher("gcp-network", "gcp", ScopeProject, []string{"vpc", "subnet", "firewall"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchGCPNetworkResourcesForProject(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+	Register(NewFetcher("gcp-appinfra", "gcp", ScopeProject, []string{"backendservice", "urlmap", "targethttpsproxy", "forwardingrule"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchGCPAppInfraForProject(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+}
+
 // FetchGCPNetworkResourcesForProject scans a single project for its networking components.
-func FetchGCPNetworkResourcesForProject(projectID string) ([]StandardizedResource, error) {
-	ctx := context.Background()
+// A failed listing is reported as a ServiceError; the other services are still returned.
+func FetchGCPNetworkResourcesForProject(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
 	var networkResources []StandardizedResource
-	computeService, err := compute.NewService(ctx)
+	var errs []error
+	computeService, err := compute.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create compute service for project %s: %w", projectID, err)
 	}
 	log.Printf("   -> Fetching network resources for project: %s", projectID)
 
-	networks, _ := computeService.Networks.List(projectID).Do()
-	if networks != nil {
+	networks, err := gcpDo(ctx, apiGCPCompute, computeService.Networks.List(projectID).Context(ctx).Do)
+	if err != nil {
+		errs = append(errs, &ServiceError{Service: "vpc", Err: fmt.Errorf("could not list networks: %w", err)})
+	} else {
 		for _, network := range networks.Items {
 			networkResources = append(networkResources, StandardizedResource{Provider: "gcp", Service: "vpc", Region: "global", ID: network.Name, Name: network.Name, Attributes: map[string]string{"project_id": projectID, "mode": fmt.Sprintf("%t", network.AutoCreateSubnetworks)}})
 		}
 	}
-	subnets, _ := computeService.Subnetworks.AggregatedList(projectID).Do()
-	if subnets != nil {
+	subnets, err := gcpDo(ctx, apiGCPCompute, computeService.Subnetworks.AggregatedList(projectID).Context(ctx).Do)
+	if err != nil {
+		errs = append(errs, &ServiceError{Service: "subnet", Err: fmt.Errorf("could not list subnetworks: %w", err)})
+	} else {
 		for _, scope := range subnets.Items {
 			for _, subnet := range scope.Subnetworks {
 				networkResources = append(networkResources, StandardizedResource{Provider: "gcp", Service: "subnet", Region: subnet.Region, ID: subnet.Name, Name: subnet.Name, Attributes: map[string]string{"project_id": projectID, "vpc": subnet.Network, "cidr_range": subnet.IpCidrRange}})
 			}
 		}
 	}
-	firewallList, _ := computeService.Firewalls.List(projectID).Do()
-	if firewallList != nil {
-		for _, listRule := range firewallList.Items {
-			rule, err := computeService.Firewalls.Get(projectID, listRule.Name).Do()
-			if err != nil {
-				log.Printf("Warning: could not get full details for firewall rule %s: %v", listRule.Name, err)
-				continue
-			}
+	firewallList, err := gcpDo(ctx, apiGCPCompute, computeService.Firewalls.List(projectID).Context(ctx).Do)
+	if err != nil {
+		errs = append(errs, &ServiceError{Service: "firewall", Err: fmt.Errorf("could not list firewalls: %w", err)})
+	} else {
+		// List returns every rule in full, so no Get per rule is needed.
+		for _, rule := range firewallList.Items {
 			formatAllowedRules := func(details []*compute.FirewallAllowed) string {
 				var p []string
 				for _, d := range details {
@@ -68,41 +84,48 @@ func FetchGCPNetworkResourcesForProject(projectID string) ([]StandardizedResourc
 			if len(rule.Allowed) > 0 {
 				action = "ALLOW"
 			}
-			attributes := map[string]string{"project_id": projectID, "action": action, "direction": rule.Direction, "priority": fmt.Sprintf("%d", rule.Priority), "disabled": fmt.Sprintf("%t", rule.Disabled), "source_ranges": strings.Join(rule.SourceRanges, ", "), "destination_ranges": strings.Join(rule.DestinationRanges, ", "), "target_tags": strings.Join(rule.TargetTags, ", "), "allowed": formatAllowedRules(rule.Allowed), "denied": formatDeniedRules(rule.Denied)}
+			attributes := map[string]string{"project_id": projectID, "action": action, "direction": rule.Direction, "priority": fmt.Sprintf("%d", rule.Priority), "disabled": fmt.Sprintf("%t", rule.Disabled), "source_ranges": strings.Join(rule.SourceRanges, ", "), "destination_ranges": strings.Join(rule.DestinationRanges, ", "), "target_tags": strings.Join(rule.TargetTags, ", "), "target_service_accounts": strings.Join(rule.TargetServiceAccounts, ", "), "network": extractResourceName(rule.Network), "allowed": formatAllowedRules(rule.Allowed), "denied": formatDeniedRules(rule.Denied)}
 			networkResources = append(networkResources, StandardizedResource{Provider: "gcp", Service: "firewall", Region: "global", ID: rule.Name, Name: rule.Name, Attributes: attributes})
 		}
 	}
-	return networkResources, nil
+	return networkResources, errors.Join(errs...)
 }
 
 // FetchGCPAppInfraForProject scans a single project for application infrastructure like LBs.
-func FetchGCPAppInfraForProject(projectID string) ([]StandardizedResource, error) {
-	ctx := context.Background()
+// A failed listing is reported as a ServiceError; the other services are still returned.
+func FetchGCPAppInfraForProject(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
 	var appResources []StandardizedResource
-	computeService, err := compute.NewService(ctx)
+	var errs []error
+	computeService, err := compute.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create compute service for project %s: %w", projectID, err)
 	}
 	log.Printf("   -> Fetching App infrastructure for project: %s", projectID)
 
-	backendServices, _ := computeService.BackendServices.AggregatedList(projectID).Do()
-	if backendServices != nil {
+	backendServices, err := gcpDo(ctx, apiGCPCompute, computeService.BackendServices.AggregatedList(projectID).Context(ctx).Do)
+	if err != nil {
+		errs = append(errs, &ServiceError{Service: "backendservice", Err: fmt.Errorf("could not list backend services: %w", err)})
+	} else {
 		for _, scope := range backendServices.Items {
 			for _, bs := range scope.BackendServices {
 				appResources = append(appResources, StandardizedResource{Provider: "gcp", Service: "backendservice", ID: bs.Name, Name: bs.Name, Attributes: map[string]string{"project_id": projectID, "load_balancing_scheme": bs.LoadBalancingScheme, "cloud_armor_policy": bs.SecurityPolicy}})
 			}
 		}
 	}
-	urlMaps, _ := computeService.UrlMaps.AggregatedList(projectID).Do()
-	if urlMaps != nil {
+	urlMaps, err := gcpDo(ctx, apiGCPCompute, computeService.UrlMaps.AggregatedList(projectID).Context(ctx).Do)
+	if err != nil {
+		errs = append(errs, &ServiceError{Service: "urlmap", Err: fmt.Errorf("could not list URL maps: %w", err)})
+	} else {
 		for _, scope := range urlMaps.Items {
 			for _, um := range scope.UrlMaps {
 				appResources = append(appResources, StandardizedResource{Provider: "gcp", Service: "urlmap", ID: um.Name, Name: um.Name, Attributes: map[string]string{"project_id": projectID, "default_service": um.DefaultService}})
 			}
 		}
 	}
-	targetProxies, _ := computeService.TargetHttpsProxies.AggregatedList(projectID).Do()
-	if targetProxies != nil {
+	targetProxies, err := gcpDo(ctx, apiGCPCompute, computeService.TargetHttpsProxies.AggregatedList(projectID).Context(ctx).Do)
+	if err != nil {
+		errs = append(errs, &ServiceError{Service: "targethttpsproxy", Err: fmt.Errorf("could not list target HTTPS proxies: %w", err)})
+	} else {
 		for _, scope := range targetProxies.Items {
 			for _, proxy := range scope.TargetHttpsProxies {
 				attributes := map[string]string{
@@ -127,8 +150,10 @@ func FetchGCPAppInfraForProject(projectID string) ([]StandardizedResource, error
 			}
 		}
 	}
-	forwardingRules, _ := computeService.GlobalForwardingRules.List(projectID).Do()
-	if forwardingRules != nil {
+	forwardingRules, err := gcpDo(ctx, apiGCPCompute, computeService.GlobalForwardingRules.List(projectID).Context(ctx).Do)
+	if err != nil {
+		errs = append(errs, &ServiceError{Service: "forwardingrule", Err: fmt.Errorf("could not list forwarding rules: %w", err)})
+	} else {
 		for _, fr := range forwardingRules.Items {
 			appResources = append(appResources, StandardizedResource{
 				Provider: "gcp",
@@ -146,26 +171,26 @@ func FetchGCPAppInfraForProject(projectID string) ([]StandardizedResource, error
 			})
 		}
 	}
-	return appResources, nil
+	return appResources, errors.Join(errs...)
 }
 
 // FetchGCPLoadBalancerFlows traces connections from Forwarding Rules to Backends.
-func FetchGCPLoadBalancerFlows(projectID string) ([]LoadBalancerFlow, error) {
-	ctx := context.Background()
+func FetchGCPLoadBalancerFlows(ctx context.Context, projectID string, opts ...option.ClientOption) ([]LoadBalancerFlow, error) {
 	var flows []LoadBalancerFlow
-	computeService, err := compute.NewService(ctx)
+	computeService, err := compute.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("could not create compute service: %w", err)
 	}
 	log.Printf("   -> Tracing Load Balancer flows for project: %s", projectID)
 
-	forwardingRules, err := computeService.GlobalForwardingRules.List(projectID).Do()
+	forwardingRules, err := gcpDo(ctx, apiGCPCompute, computeService.GlobalForwardingRules.List(projectID).Context(ctx).Do)
 	if err != nil {
 		return nil, fmt.Errorf("could not list forwarding rules: %w", err)
 	}
 	for _, fr := range forwardingRules.Items {
 		flow := LoadBalancerFlow{
 			Name:      fr.Name,
+			Provider:  "gcp",
 			ProjectID: projectID,
 			Frontend: FrontendConfig{
 				IPAddress:           fr.IPAddress,
@@ -175,25 +200,25 @@ func FetchGCPLoadBalancerFlows(projectID string) ([]LoadBalancerFlow, error) {
 			},
 		}
 		proxyName := strings.Split(fr.Target, "/")[len(strings.Split(fr.Target, "/"))-1]
-		httpsProxy, err := computeService.TargetHttpsProxies.Get(projectID, proxyName).Do()
+		httpsProxy, err := gcpDo(ctx, apiGCPCompute, computeService.TargetHttpsProxies.Get(projectID, proxyName).Context(ctx).Do)
 		if err == nil {
 			flow.Frontend.Certificates = httpsProxy.SslCertificates
 			flow.Frontend.SSLPolicy = httpsProxy.SslPolicy
 			urlMapName := strings.Split(httpsProxy.UrlMap, "/")[len(strings.Split(httpsProxy.UrlMap, "/"))-1]
-			urlMap, err := computeService.UrlMaps.Get(projectID, urlMapName).Do()
+			urlMap, err := gcpDo(ctx, apiGCPCompute, computeService.UrlMaps.Get(projectID, urlMapName).Context(ctx).Do)
 			if err == nil {
 				for _, hostRule := range urlMap.HostRules {
 					flow.RoutingRules = append(flow.RoutingRules, RoutingRule{Hosts: hostRule.Hosts, PathMatcher: hostRule.PathMatcher})
 				}
 				backendServiceName := strings.Split(urlMap.DefaultService, "/")[len(strings.Split(urlMap.DefaultService, "/"))-1]
-				backendService, err := computeService.BackendServices.Get(projectID, backendServiceName).Do()
+				backendService, err := gcpDo(ctx, apiGCPCompute, computeService.BackendServices.Get(projectID, backendServiceName).Context(ctx).Do)
 				if err == nil {
 					flow.Backend.Name = backendService.Name
 					for _, backend := range backendService.Backends {
 						if strings.Contains(backend.Group, "run.googleapis.com") {
 							flow.Backend.Type = "Cloud Run"
 							negName := strings.Split(backend.Group, "/")[len(strings.Split(backend.Group, "/"))-1]
-							neg, err := computeService.RegionNetworkEndpointGroups.Get(projectID, backendService.Region, negName).Do()
+							neg, err := gcpDo(ctx, apiGCPCompute, computeService.RegionNetworkEndpointGroups.Get(projectID, backendService.Region, negName).Context(ctx).Do)
 							if err == nil && neg.CloudRun != nil {
 								flow.Backend.ServiceName = neg.CloudRun.Service
 								flow.Backend.Region = backendService.Region
@@ -202,7 +227,7 @@ func FetchGCPLoadBalancerFlows(projectID string) ([]LoadBalancerFlow, error) {
 					}
 					if backendService.SecurityPolicy != "" {
 						policyName := strings.Split(backendService.SecurityPolicy, "/")[len(strings.Split(backendService.SecurityPolicy, "/"))-1]
-						policy, err := computeService.SecurityPolicies.Get(projectID, policyName).Do()
+						policy, err := gcpDo(ctx, apiGCPCompute, computeService.SecurityPolicies.Get(projectID, policyName).Context(ctx).Do)
 						if err == nil {
 							flow.CloudArmor.Name = policy.Name
 							for _, rule := range policy.Rules {
@@ -0,0 +1,366 @@
+// fetcher/gcp_databases_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	firestore "google.golang.org/api/firestore/v1"
+	"google.golang.org/api/option"
+	redis "google.golang.org/api/redis/v1"
+	spanner "google.golang.org/api/spanner/v1"
+	sqladmin "google.golang.org/api/sqladmin/v1"
+)
+
+func init() {
+	Register(NewFetcher("gcp-cloudsql", "gcp", ScopeProject, []string{"cloudsql"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchCloudSQLInstances(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+	Register(NewFetcher("gcp-redis", "gcp", ScopeProject, []string{"redis"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchRedisInstances(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+	Register(NewFetcher("gcp-spanner", "gcp", ScopeProject, []string{"spanner"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchSpannerDatabases(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+	Register(NewFetcher("gcp-firestore", "gcp", ScopeProject, []string{"firestore"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchFirestoreDatabases(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+}
+
+// FetchCloudSQLInstances collects the Cloud SQL instances of a project,
+// replicas included. Like RDS databases, they record their engine and
+// version, and like EC2 instances their addresses in "private_ip" and
+// "public_ip". "authorized_networks" lists the ranges allowed to reach the
+// public address.
+func FetchCloudSQLInstances(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	sqlService, err := sqladmin.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sqladmin service for project %s: %w", projectID, err)
+	}
+	log.Printf("   -> Fetching Cloud SQL instances for project: %s", projectID)
+
+	call := sqlService.Instances.List(projectID).Context(ctx)
+	for {
+		page, err := gcpDo(ctx, apiGCPSQLAdmin, call.Do)
+		if err != nil {
+			return nil, fmt.Errorf("could not list Cloud SQL instances for project %s: %w", projectID, err)
+		}
+		for _, instance := range page.Items {
+			resources = append(resources, cloudSQLResource(instance, projectID))
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		call.PageToken(page.NextPageToken)
+	}
+
+	log.Printf("   -> Fetched %d Cloud SQL instances for project %s", len(resources), projectID)
+	return resources, nil
+}
+
+func cloudSQLResource(instance *sqladmin.DatabaseInstance, projectID string) StandardizedResource {
+	// Versions are named like "POSTGRES_15" or "MYSQL_8_0".
+	engine, _, _ := strings.Cut(instance.DatabaseVersion, "_")
+	attributes := map[string]string{
+		"project_id":       projectID,
+		"engine":           strings.ToLower(engine),
+		"database_version": instance.DatabaseVersion,
+		"status":           strings.ToLower(instance.State),
+		"type":             strings.ToLower(instance.InstanceType),
+		"primary":          instance.MasterInstanceName,
+		"zone":             instance.GceZone,
+		"connection_name":  instance.ConnectionName,
+		"created":          instance.CreateTime,
+	}
+	for _, address := range instance.IpAddresses {
+		switch address.Type {
+		case "PRIMARY":
+			attributes["public_ip"] = address.IpAddress
+		case "PRIVATE":
+			attributes["private_ip"] = address.IpAddress
+		}
+	}
+	if settings := instance.Settings; settings != nil {
+		attributes["tier"] = settings.Tier
+		attributes["availability_type"] = strings.ToLower(settings.AvailabilityType)
+		attributes["disk_size_gb"] = fmt.Sprintf("%d", settings.DataDiskSizeGb)
+		attributes["deletion_protection"] = fmt.Sprintf("%t", settings.DeletionProtectionEnabled)
+		attributes["backups_enabled"] = "false"
+		if ip := settings.IpConfiguration; ip != nil {
+			var networks []string
+			for _, network := range ip.AuthorizedNetworks {
+				networks = append(networks, network.Value)
+			}
+			attributes["public_ip_enabled"] = fmt.Sprintf("%t", ip.Ipv4Enabled)
+			if ip.PrivateNetwork != "" {
+				attributes["vpc"] = extractResourceName(ip.PrivateNetwork)
+			}
+			attributes["authorized_networks"] = strings.Join(networks, ", ")
+			attributes["ssl_mode"] = ip.SslMode
+		}
+		if backup := settings.BackupConfiguration; backup != nil {
+			attributes["backups_enabled"] = fmt.Sprintf("%t", backup.Enabled)
+			attributes["backup_start_time"] = backup.StartTime
+			attributes["point_in_time_recovery"] = fmt.Sprintf("%t", backup.PointInTimeRecoveryEnabled || backup.BinaryLogEnabled)
+		}
+	}
+	attributes = dropEmpty(attributes)
+	if instance.Settings != nil {
+		for key, value := range instance.Settings.UserLabels {
+			attributes["label:"+key] = value
+		}
+	}
+
+	return StandardizedResource{
+		Provider:   "gcp",
+		Service:    "cloudsql",
+		Region:     instance.Region,
+		ID:         instance.Name,
+		Name:       instance.Name,
+		Attributes: attributes,
+	}
+}
+
+// FetchRedisInstances collects the Memorystore for Redis instances of every
+// region of a project. An instance's ID is "<region>/<name>" and its
+// address is in "endpoint" and "port". Regions the API could not reach are
+// reported as a ServiceError, with the instances of the others returned.
+func FetchRedisInstances(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	redisService, err := redis.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create redis service for project %s: %w", projectID, err)
+	}
+	log.Printf("   -> Fetching Memorystore Redis instances for project: %s", projectID)
+
+	var unreachable []string
+	call := redisService.Projects.Locations.Instances.List(fmt.Sprintf("projects/%s/locations/-", projectID)).Context(ctx)
+	for {
+		page, err := gcpDo(ctx, apiGCPRedis, call.Do)
+		if err != nil {
+			return nil, fmt.Errorf("could not list Redis instances for project %s: %w", projectID, err)
+		}
+		for _, instance := range page.Instances {
+			resources = append(resources, redisResource(instance, projectID))
+		}
+		unreachable = append(unreachable, page.Unreachable...)
+		if page.NextPageToken == "" {
+			break
+		}
+		call.PageToken(page.NextPageToken)
+	}
+
+	log.Printf("   -> Fetched %d Redis instances for project %s", len(resources), projectID)
+	if len(unreachable) > 0 {
+		return resources, &ServiceError{Service: "redis", Err: fmt.Errorf("could not list Redis instances in %s", strings.Join(unreachable, ", "))}
+	}
+	return resources, nil
+}
+
+func redisResource(instance *redis.Instance, projectID string) StandardizedResource {
+	region := gcpLocation(instance.Name)
+	name := extractResourceName(instance.Name)
+	attributes := map[string]string{
+		"project_id":         projectID,
+		"display_name":       instance.DisplayName,
+		"zone":               instance.CurrentLocationId,
+		"tier":               strings.ToLower(instance.Tier),
+		"memory_size_gb":     fmt.Sprintf("%d", instance.MemorySizeGb),
+		"redis_version":      instance.RedisVersion,
+		"status":             strings.ToLower(instance.State),
+		"endpoint":           instance.Host,
+		"port":               fmt.Sprintf("%d", instance.Port),
+		"read_endpoint":      instance.ReadEndpoint,
+		"vpc":                extractResourceName(instance.AuthorizedNetwork),
+		"connect_mode":       strings.ToLower(instance.ConnectMode),
+		"reserved_ip_range":  instance.ReservedIpRange,
+		"auth_enabled":       fmt.Sprintf("%t", instance.AuthEnabled),
+		"transit_encryption": strings.ToLower(instance.TransitEncryptionMode),
+		"kms_key":            instance.CustomerManagedKey,
+		"created":            instance.CreateTime,
+	}
+	if instance.ReplicaCount > 0 {
+		attributes["replica_count"] = fmt.Sprintf("%d", instance.ReplicaCount)
+	}
+	attributes = dropEmpty(attributes)
+	for key, value := range instance.Labels {
+		attributes["label:"+key] = value
+	}
+
+	return StandardizedResource{
+		Provider:   "gcp",
+		Service:    "redis",
+		Region:     region,
+		ID:         region + "/" + name,
+		Name:       name,
+		Attributes: attributes,
+	}
+}
+
+// FetchSpannerDatabases collects the databases of every Spanner instance of
+// a project. A database's ID is "<instance>/<database>", and it records the
+// configuration and compute capacity of its instance. The databases of
+// instances that cannot be listed are reported as a ServiceError.
+func FetchSpannerDatabases(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	spannerService, err := spanner.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create spanner service for project %s: %w", projectID, err)
+	}
+	log.Printf("   -> Fetching Spanner databases for project: %s", projectID)
+
+	var instances []*spanner.Instance
+	var errs []error
+	instanceCall := spannerService.Projects.Instances.List("projects/" + projectID).Context(ctx)
+	for {
+		page, err := gcpDo(ctx, apiGCPSpanner, instanceCall.Do)
+		if err != nil {
+			return nil, fmt.Errorf("could not list Spanner instances for project %s: %w", projectID, err)
+		}
+		instances = append(instances, page.Instances...)
+		if len(page.Unreachable) > 0 {
+			errs = append(errs, fmt.Errorf("could not list Spanner instances in %s", strings.Join(page.Unreachable, ", ")))
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		instanceCall.PageToken(page.NextPageToken)
+	}
+
+	for _, instance := range instances {
+		databaseCall := spannerService.Projects.Instances.Databases.List(instance.Name).Context(ctx)
+		for {
+			page, err := gcpDo(ctx, apiGCPSpanner, databaseCall.Do)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("could not list databases of Spanner instance %s: %w", extractResourceName(instance.Name), err))
+				break
+			}
+			for _, database := range page.Databases {
+				resources = append(resources, spannerResource(database, instance, projectID))
+			}
+			if page.NextPageToken == "" {
+				break
+			}
+			databaseCall.PageToken(page.NextPageToken)
+		}
+	}
+
+	log.Printf("   -> Fetched %d Spanner databases for project %s", len(resources), projectID)
+	if err := errors.Join(errs...); err != nil {
+		return resources, &ServiceError{Service: "spanner", Err: err}
+	}
+	return resources, nil
+}
+
+func spannerResource(database *spanner.Database, instance *spanner.Instance, projectID string) StandardizedResource {
+	instanceName := extractResourceName(instance.Name)
+	config := extractResourceName(instance.Config)
+	name := extractResourceName(database.Name)
+	attributes := map[string]string{
+		"project_id":        projectID,
+		"instance":          instanceName,
+		"instance_config":   config,
+		"edition":           strings.ToLower(instance.Edition),
+		"processing_units":  fmt.Sprintf("%d", instance.ProcessingUnits),
+		"dialect":           strings.ToLower(database.DatabaseDialect),
+		"status":            strings.ToLower(database.State),
+		"default_leader":    database.DefaultLeader,
+		"version_retention": database.VersionRetentionPeriod,
+		"drop_protection":   fmt.Sprintf("%t", database.EnableDropProtection),
+		"created":           database.CreateTime,
+	}
+	if instance.NodeCount > 0 {
+		attributes["nodes"] = fmt.Sprintf("%d", instance.NodeCount)
+	}
+	if database.EncryptionConfig != nil {
+		attributes["kms_key"] = database.EncryptionConfig.KmsKeyName
+	}
+	attributes = dropEmpty(attributes)
+	for key, value := range instance.Labels {
+		attributes["label:"+key] = value
+	}
+
+	return StandardizedResource{
+		Provider: "gcp",
+		Service:  "spanner",
+		// Regional configurations are named like "regional-us-central1";
+		// multi-region ones, e.g. "nam3", are kept as they are.
+		Region:     strings.TrimPrefix(config, "regional-"),
+		ID:         instanceName + "/" + name,
+		Name:       name,
+		Attributes: attributes,
+	}
+}
+
+// FetchFirestoreDatabases collects the Firestore databases of a project, in
+// Native and Datastore mode; "type" tells them apart. The default database
+// has the ID "(default)".
+func FetchFirestoreDatabases(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	firestoreService, err := firestore.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create firestore service for project %s: %w", projectID, err)
+	}
+	log.Printf("   -> Fetching Firestore databases for project: %s", projectID)
+
+	list, err := gcpDo(ctx, apiGCPFirestore, firestoreService.Projects.Databases.List("projects/"+projectID).Context(ctx).Do)
+	if err != nil {
+		return nil, fmt.Errorf("could not list Firestore databases for project %s: %w", projectID, err)
+	}
+	for _, database := range list.Databases {
+		resources = append(resources, firestoreResource(database, projectID))
+	}
+
+	log.Printf("   -> Fetched %d Firestore databases for project %s", len(resources), projectID)
+	if len(list.Unreachable) > 0 {
+		return resources, &ServiceError{Service: "firestore", Err: fmt.Errorf("could not list Firestore databases in %s", strings.Join(list.Unreachable, ", "))}
+	}
+	return resources, nil
+}
+
+func firestoreResource(database *firestore.GoogleFirestoreAdminV1Database, projectID string) StandardizedResource {
+	name := extractResourceName(database.Name)
+	attributes := map[string]string{
+		"project_id":             projectID,
+		"location":               database.LocationId,
+		"type":                   strings.ToLower(database.Type),
+		"edition":                strings.ToLower(database.DatabaseEdition),
+		"concurrency_mode":       strings.ToLower(database.ConcurrencyMode),
+		"point_in_time_recovery": fmt.Sprintf("%t", database.PointInTimeRecoveryEnablement == "POINT_IN_TIME_RECOVERY_ENABLED"),
+		"delete_protection":      fmt.Sprintf("%t", database.DeleteProtectionState == "DELETE_PROTECTION_ENABLED"),
+		"version_retention":      database.VersionRetentionPeriod,
+		"created":                database.CreateTime,
+	}
+	if database.CmekConfig != nil {
+		attributes["kms_key"] = database.CmekConfig.KmsKeyName
+	}
+
+	return StandardizedResource{
+		Provider:   "gcp",
+		Service:    "firestore",
+		Region:     database.LocationId,
+		ID:         name,
+		Name:       name,
+		Attributes: dropEmpty(attributes),
+	}
+}
+
+// gcpLocation returns the location of a resource from its full name, e.g.
+// "us-central1" for "projects/p/locations/us-central1/instances/cache".
+func gcpLocation(name string) string {
+	parts := strings.Split(name, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "locations" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
@@ -10,14 +10,39 @@ import (
 	resourcemanager "cloud.google.com/go/resourcemanager/apiv3"
 	"google.golang.org/api/cloudresourcemanager/v1"
 	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
 	assetpb "google.golang.org/genproto/googleapis/cloud/asset/v1"
 	resourcemanagerpb "google.golang.org/genproto/googleapis/cloud/resourcemanager/v3"
 )
 
+func init() {
+	Register(NewFetcher("gcp-projects", "gcp", ScopeProvider, []string{"project", "folder"}, fetchGCPHierarchy))
+}
+
+// fetchGCPHierarchy discovers the projects (and folders, when an organization
+// is visible) that the project-scoped GCP fetchers are run against.
+func fetchGCPHierarchy(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+	if scope.ProjectID != "" {
+		project, err := FetchGCPProject(ctx, scope.ProjectID, scope.GCPOptions...)
+		if err != nil {
+			return nil, err
+		}
+		return []StandardizedResource{project}, nil
+	}
+
+	gcpOrganizationID, err := DiscoverGCPOrganization(ctx, scope.GCPOptions...)
+	if err != nil {
+		log.Printf("Warning: Could not discover GCP organization: %v", err)
+	}
+	if gcpOrganizationID != "" {
+		return FetchGCPResourcesFromOrg(ctx, gcpOrganizationID, scope.GCPOptions...)
+	}
+	return FetchGCPProjectsNoOrg(ctx, scope.GCPOptions...)
+}
+
 // DiscoverGCPOrganization searches for an organization the user can access.
-func DiscoverGCPOrganization() (string, error) {
-	ctx := context.Background()
-	orgClient, err := resourcemanager.NewOrganizationsClient(ctx)
+func DiscoverGCPOrganization(ctx context.Context, opts ...option.ClientOption) (string, error) {
+	orgClient, err := resourcemanager.NewOrganizationsClient(ctx, opts...)
 	if err != nil {
 		return "", fmt.Errorf("failed to create organizations client: %w", err)
 	}
@@ -25,24 +50,28 @@ func DiscoverGCPOrganization() (string, error) {
 
 	log.Println("Checking for a GCP Organization...")
 	req := &resourcemanagerpb.SearchOrganizationsRequest{Query: ""}
-	it := orgClient.SearchOrganizations(ctx, req)
-	firstOrg, err := it.Next()
-	if err == iterator.Done {
-		log.Println("No GCP Organization found.")
-		return "", nil
-	}
+	orgs, err := callAPI(ctx, apiGCPResourceManager, func() ([]*resourcemanagerpb.Organization, error) {
+		var orgs []*resourcemanagerpb.Organization
+		_, err := iterator.NewPager(orgClient.SearchOrganizations(ctx, req), 1, "").NextPage(&orgs)
+		return orgs, err
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed during organization search: %w", err)
 	}
+	if len(orgs) == 0 {
+		log.Println("No GCP Organization found.")
+		return "", nil
+	}
+	firstOrg := orgs[0]
 	log.Println("Found GCP Organization:", firstOrg.DisplayName)
 	return firstOrg.Name, nil
 }
 
-// FetchGCPResourcesFromOrg uses the Cloud Asset API to fetch all folders, projects, and their sub-resources.
-func FetchGCPResourcesFromOrg(organizationID string) ([]StandardizedResource, error) {
-	ctx := context.Background()
+// FetchGCPResourcesFromOrg uses the Cloud Asset API to fetch all folders and projects.
+// Project sub-resources are collected separately by the project-scoped fetchers.
+func FetchGCPResourcesFromOrg(ctx context.Context, organizationID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
 	var allResources []StandardizedResource
-	client, err := asset.NewClient(ctx)
+	client, err := asset.NewClient(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create asset client: %w", err)
 	}
@@ -53,15 +82,27 @@ func FetchGCPResourcesFromOrg(organizationID string) ([]StandardizedResource, er
 		Scope:      organizationID,
 		AssetTypes: []string{"cloudresourcemanager.googleapis.com/Project", "cloudresourcemanager.googleapis.com/Folder"},
 	}
-	it := client.SearchAllResources(ctx, req)
+	var results []*assetpb.ResourceSearchResult
+	pageToken := ""
 	for {
-		resource, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
+		// Each page is requested through a fresh iterator so that a throttled
+		// page can be retried from its token.
+		var page []*assetpb.ResourceSearchResult
+		nextPageToken, err := callAPI(ctx, apiGCPCloudAsset, func() (string, error) {
+			page = nil
+			return iterator.NewPager(client.SearchAllResources(ctx, req), 500, pageToken).NextPage(&page)
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed during asset iteration: %w", err)
 		}
+		results = append(results, page...)
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+
+	for _, resource := range results {
 		var standardizedRes StandardizedResource // Declare standardizedRes here
 		var projectID string
 		switch resource.AssetType {
@@ -80,23 +121,6 @@ func FetchGCPResourcesFromOrg(organizationID string) ([]StandardizedResource, er
 				Attributes: map[string]string{"state": resource.GetState(), "project_number": projectNumber},
 			}
 			allResources = append(allResources, standardizedRes)
-
-			if projectID != "" && projectID != "N/A" {
-				networkRes, _ := FetchGCPNetworkResourcesForProject(projectID)
-				allResources = append(allResources, networkRes...)
-				cloudRunRes, _ := FetchGCPCloudRunServices(projectID, networkRes)
-				allResources = append(allResources, cloudRunRes...)
-				appInfraRes, _ := FetchGCPAppInfraForProject(projectID)
-				allResources = append(allResources, appInfraRes...)
-
-				// CORRECTED: Added the missing call
-				iamRes, err := FetchGCPServiceAccounts(projectID)
-				if err != nil {
-					log.Printf("Warning: could not fetch service accounts for project %s: %v", projectID, err)
-					// Continue even if fetching SAs fails for one project
-				}
-				allResources = append(allResources, iamRes...)
-			}
 		case "cloudresourcemanager.googleapis.com/Folder":
 			standardizedRes = StandardizedResource{
 				Provider: "gcp", Service: "folder", Region: "global", ID: resource.GetName(), Name: resource.GetDisplayName(),
@@ -108,17 +132,20 @@ func FetchGCPResourcesFromOrg(organizationID string) ([]StandardizedResource, er
 	return allResources, nil
 }
 
-// FetchGCPProjectsNoOrg uses the Resource Manager API to list all projects and their sub-resources.
-func FetchGCPProjectsNoOrg() ([]StandardizedResource, error) {
-	ctx := context.Background()
+// FetchGCPProjectsNoOrg uses the Resource Manager API to list all accessible projects.
+func FetchGCPProjectsNoOrg(ctx context.Context, opts ...option.ClientOption) ([]StandardizedResource, error) {
 	var allResources []StandardizedResource
-	crmService, err := cloudresourcemanager.NewService(ctx)
+	crmService, err := cloudresourcemanager.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cloudresourcemanager service: %w", err)
 	}
 	log.Println("No GCP Organization ID provided. Fetching all accessible projects using v1 API...")
-	call := crmService.Projects.List()
-	err = call.Pages(ctx, func(page *cloudresourcemanager.ListProjectsResponse) error {
+	call := crmService.Projects.List().Context(ctx)
+	for {
+		page, err := gcpDo(ctx, apiGCPResourceManager, call.Do)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list projects: %w", err)
+		}
 		for _, project := range page.Projects {
 			standardizedRes := StandardizedResource{
 				Provider: "gcp", Service: "project", Region: "global", ID: project.ProjectId, Name: project.Name,
@@ -128,53 +155,30 @@ func FetchGCPProjectsNoOrg() ([]StandardizedResource, error) {
 				},
 			}
 			allResources = append(allResources, standardizedRes)
-
-			networkRes, _ := FetchGCPNetworkResourcesForProject(project.ProjectId)
-			allResources = append(allResources, networkRes...)
-			cloudRunRes, _ := FetchGCPCloudRunServices(project.ProjectId, networkRes)
-			allResources = append(allResources, cloudRunRes...)
-			appInfraRes, _ := FetchGCPAppInfraForProject(project.ProjectId)
-			allResources = append(allResources, appInfraRes...)
-
-			// CORRECTED: Added the missing call
-			iamRes, err := FetchGCPServiceAccounts(project.ProjectId)
-			if err != nil {
-				log.Printf("Warning: could not fetch service accounts for project %s: %v", project.ProjectId, err)
-				// Continue to next project even if SA fetching fails for one
-				continue
-			}
-			allResources = append(allResources, iamRes...)
 		}
-		return nil
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list projects: %w", err)
+		if page.NextPageToken == "" {
+			break
+		}
+		call.PageToken(page.NextPageToken)
 	}
 	return allResources, nil
 }
 
-// FetchGCPSingleProject fetches all resources for a specific GCP project.
-// This is used for targeted syncing without affecting the entire cache.
-func FetchGCPSingleProject(projectID string) ([]StandardizedResource, error) {
-	ctx := context.Background()
-	var allResources []StandardizedResource
-
-	log.Printf("Fetching resources for GCP project: %s", projectID)
-
+// FetchGCPProject returns the project resource itself, verifying that it exists.
+func FetchGCPProject(ctx context.Context, projectID string, opts ...option.ClientOption) (StandardizedResource, error) {
 	// Create a Resource Manager service to verify the project exists
-	crmService, err := cloudresourcemanager.NewService(ctx)
+	crmService, err := cloudresourcemanager.NewService(ctx, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create cloudresourcemanager service: %w", err)
+		return StandardizedResource{}, fmt.Errorf("failed to create cloudresourcemanager service: %w", err)
 	}
 
 	// Verify the project exists and get its metadata
-	project, err := crmService.Projects.Get(projectID).Context(ctx).Do()
+	project, err := gcpDo(ctx, apiGCPResourceManager, crmService.Projects.Get(projectID).Context(ctx).Do)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get project %s: %w", projectID, err)
+		return StandardizedResource{}, fmt.Errorf("failed to get project %s: %w", projectID, err)
 	}
 
-	// Add the project itself as a resource
-	standardizedRes := StandardizedResource{
+	return StandardizedResource{
 		Provider: "gcp",
 		Service:  "project",
 		Region:   "global",
@@ -184,45 +188,21 @@ func FetchGCPSingleProject(projectID string) ([]StandardizedResource, error) {
 			"state":          project.LifecycleState,
 			"project_number": fmt.Sprintf("%d", project.ProjectNumber),
 		},
-	}
-	allResources = append(allResources, standardizedRes)
-
-	// Fetch network resources
-	log.Printf("Fetching network resources for project %s...", projectID)
-	networkRes, err := FetchGCPNetworkResourcesForProject(projectID)
-	if err != nil {
-		log.Printf("Warning: could not fetch network resources for project %s: %v", projectID, err)
-	} else {
-		allResources = append(allResources, networkRes...)
-	}
-
-	// Fetch Cloud Run services
-	log.Printf("Fetching Cloud Run services for project %s...", projectID)
-	cloudRunRes, err := FetchGCPCloudRunServices(projectID, networkRes)
-	if err != nil {
-		log.Printf("Warning: could not fetch Cloud Run services for project %s: %v", projectID, err)
-	} else {
-		allResources = append(allResources, cloudRunRes...)
-	}
+	}, nil
+}
 
-	// Fetch app infrastructure resources
-	log.Printf("Fetching app infrastructure for project %s...", projectID)
-	appInfraRes, err := FetchGCPAppInfraForProject(projectID)
+// FetchGCPSingleProject fetches all resources for a specific GCP project.
+// This is used for targeted syncing without affecting the entire cache.
+func FetchGCPSingleProject(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
+	log.Printf("Fetching resources for GCP project: %s", projectID)
+	project, err := FetchGCPProject(ctx, projectID, opts...)
 	if err != nil {
-		log.Printf("Warning: could not fetch app infrastructure for project %s: %v", projectID, err)
-	} else {
-		allResources = append(allResources, appInfraRes...)
+		return nil, err
 	}
 
-	// Fetch IAM service accounts
-	log.Printf("Fetching service accounts for project %s...", projectID)
-	iamRes, err := FetchGCPServiceAccounts(projectID)
-	if err != nil {
-		log.Printf("Warning: could not fetch service accounts for project %s: %v", projectID, err)
-	} else {
-		allResources = append(allResources, iamRes...)
-	}
+	allResources := []StandardizedResource{project}
+	allResources = append(allResources, FetchProjectResources(ctx, "gcp", Scope{ProjectID: projectID, GCPOptions: opts})...)
 
 	log.Printf("Successfully fetched %d resources for project %s", len(allResources), projectID)
 	return allResources, nil
-}
\ No newline at end of file
+}
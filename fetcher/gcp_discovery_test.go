@@ -0,0 +1,437 @@
+package fetcher
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+const (
+	computeHost = "compute.googleapis.com"
+	crmHost     = "cloudresourcemanager.googleapis.com"
+	runHost     = "run.googleapis.com"
+	iamHost     = "iam.googleapis.com"
+	dnsHost     = "dns.googleapis.com"
+	gkeHost     = "container.googleapis.com"
+	sqlHost     = "sqladmin.googleapis.com"
+	redisHost   = "redis.googleapis.com"
+	spannerHost = "spanner.googleapis.com"
+	fsHost      = "firestore.googleapis.com"
+	storageHost = "storage.googleapis.com"
+	pubsubHost  = "pubsub.googleapis.com"
+	gcfHost     = "cloudfunctions.googleapis.com"
+	cronHost    = "cloudscheduler.googleapis.com"
+)
+
+// demoProjectRoutes maps every call made while syncing demo-project to its fixture.
+var demoProjectRoutes = []struct{ host, operation, file string }{
+	{crmHost, "GET /v1/projects/demo-project", "gcp/demo-project/project.json"},
+	{crmHost, "POST /v1/projects/demo-project:getIamPolicy", "gcp/demo-project/iam_policy.json"},
+	{computeHost, "GET /compute/v1/projects/demo-project/global/networks", "gcp/demo-project/networks.json"},
+	{computeHost, "GET /compute/v1/projects/demo-project/aggregated/subnetworks", "gcp/demo-project/subnetworks.json"},
+	{computeHost, "GET /compute/v1/projects/demo-project/global/firewalls", "gcp/demo-project/firewalls.json"},
+	{computeHost, "GET /compute/v1/projects/demo-project/aggregated/backendServices", "gcp/demo-project/backend_services.json"},
+	{computeHost, "GET /compute/v1/projects/demo-project/aggregated/urlMaps", "gcp/demo-project/url_maps.json"},
+	{computeHost, "GET /compute/v1/projects/demo-project/aggregated/targetHttpsProxies", "gcp/demo-project/target_https_proxies.json"},
+	{computeHost, "GET /compute/v1/projects/demo-project/global/forwardingRules", "gcp/demo-project/forwarding_rules.json"},
+	{computeHost, "GET /compute/v1/projects/demo-project/aggregated/instances", "gcp/demo-project/instances.json"},
+	{computeHost, "GET /compute/v1/projects/demo-project/aggregated/disks", "gcp/demo-project/disks.json"},
+	{runHost, "GET /v1/projects/demo-project/locations/-/services", "gcp/demo-project/run_services.json"},
+	{gkeHost, "GET /v1/projects/demo-project/locations/-/clusters", "gcp/demo-project/gke_clusters.json"},
+	{sqlHost, "GET /v1/projects/demo-project/instances", "gcp/demo-project/sql_instances.json"},
+	{redisHost, "GET /v1/projects/demo-project/locations/-/instances", "gcp/demo-project/redis_instances.json"},
+	{spannerHost, "GET /v1/projects/demo-project/instances", "gcp/demo-project/spanner_instances.json"},
+	{spannerHost, "GET /v1/projects/demo-project/instances/ledger/databases", "gcp/demo-project/spanner_databases.json"},
+	{fsHost, "GET /v1/projects/demo-project/databases", "gcp/demo-project/firestore_databases.json"},
+	{storageHost, "GET /storage/v1/b", "gcp/demo-project/buckets.json"},
+	{storageHost, "GET /storage/v1/b/demo-project-assets/iam", "gcp/demo-project/bucket_iam_assets.json"},
+	{storageHost, "GET /storage/v1/b/demo-project-backups/iam", "gcp/demo-project/bucket_iam_backups.json"},
+	{storageHost, "GET /storage/v1/b/demo-project-legacy/iam", "gcp/demo-project/bucket_iam_legacy.json"},
+	{pubsubHost, "GET /v1/projects/demo-project/topics", "gcp/demo-project/pubsub_topics.json"},
+	{pubsubHost, "GET /v1/projects/demo-project/subscriptions", "gcp/demo-project/pubsub_subscriptions.json"},
+	{gcfHost, "GET /v2/projects/demo-project/locations/-/functions", "gcp/demo-project/functions.json"},
+	{cronHost, "GET /v1/projects/demo-project/locations", "gcp/demo-project/scheduler_locations.json"},
+	{cronHost, "GET /v1/projects/demo-project/locations/europe-west1/jobs", "gcp/demo-project/scheduler_jobs_europe-west1.json"},
+	{cronHost, "GET /v1/projects/demo-project/locations/us-central1/jobs", "gcp/demo-project/scheduler_jobs_us-central1.json"},
+	{iamHost, "GET /v1/projects/demo-project/serviceAccounts", "gcp/demo-project/service_accounts.json"},
+	{dnsHost, "GET /dns/v1/projects/demo-project/managedZones", "gcp/demo-project/dns_managed_zones.json"},
+	{dnsHost, "GET /dns/v1/projects/demo-project/managedZones/demo-zone/rrsets", "gcp/demo-project/dns_record_sets.json"},
+}
+
+// serveDemoProject registers the demo-project fixtures, except for the
+// operations in overrides, which are answered with the given status and file.
+func serveDemoProject(f *fakeCloud, overrides map[string]fakeResponse) {
+	for _, route := range demoProjectRoutes {
+		if o, ok := overrides[route.operation]; ok {
+			f.respond(route.host, route.operation, o.status, o.file)
+			continue
+		}
+		f.handle(route.host, route.operation, route.file)
+	}
+}
+
+func TestFetchGCPSingleProject(t *testing.T) {
+	allResources := []string{
+		"backendservice/web-backend",
+		"cloudfunction/us-central1/nightly-report",
+		"cloudfunction/us-central1/process-order",
+		"cloudrun/web",
+		"cloudsql/orders-db",
+		"cloudsql/reporting-mysql",
+		"dnsrecord/demo-zone/app.demo.example.com/CNAME",
+		"dnsrecord/demo-zone/canary.demo.example.com/A",
+		"dnsrecord/demo-zone/demo.example.com/NS",
+		"dnsrecord/demo-zone/failover.demo.example.com/A",
+		"dnsrecord/demo-zone/www.demo.example.com/A",
+		"dnszone/demo-zone",
+		"firestore/(default)",
+		"firewall/allow-ssh",
+		"forwardingrule/web-https",
+		"gce/us-central1-a/web-1",
+		"gce/us-central1-b/batch-worker",
+		"gcs/demo-project-assets",
+		"gcs/demo-project-backups",
+		"gcs/demo-project-legacy",
+		"gkecluster/us-central1-a/dev-gke",
+		"gkecluster/us-central1/prod-gke",
+		"gkenodepool/us-central1-a/dev-gke/default-pool",
+		"gkenodepool/us-central1/prod-gke/default-pool",
+		"gkenodepool/us-central1/prod-gke/spot-pool",
+		"project/demo-project",
+		"pubsubsubscription/eventarc-us-central1-process-order-123",
+		"pubsubsubscription/orders-worker",
+		"pubsubsubscription/partner-feed",
+		"pubsubtopic/nightly-trigger",
+		"pubsubtopic/orders",
+		"pubsubtopic/orders-dlq",
+		"redis/us-central1/sessions",
+		"schedulerjob/us-central1/cleanup",
+		"schedulerjob/us-central1/nightly-report",
+		"schedulerjob/us-central1/tick",
+		"serviceaccount/ci-deployer@demo-project.iam.gserviceaccount.com",
+		"serviceaccount/web-runtime@demo-project.iam.gserviceaccount.com",
+		"spanner/ledger/accounts",
+		"subnet/prod-subnet",
+		"targethttpsproxy/web-proxy",
+		"urlmap/web-map",
+		"vpc/prod-vpc",
+	}
+	forbidden := fakeResponse{status: http.StatusForbidden, file: "gcp/errors/forbidden.json"}
+	rateLimited := fakeResponse{status: http.StatusForbidden, file: "gcp/errors/rate_limited.json"}
+
+	tests := []struct {
+		name      string
+		overrides map[string]fakeResponse
+		wantErr   bool
+		wantKeys  []string
+	}{
+		{
+			name:     "full project",
+			wantKeys: allResources,
+		},
+		{
+			name: "networks forbidden",
+			overrides: map[string]fakeResponse{
+				"GET /compute/v1/projects/demo-project/global/networks": forbidden,
+			},
+			wantKeys: without(allResources, "vpc/prod-vpc"),
+		},
+		{
+			name: "Cloud Run API fails",
+			overrides: map[string]fakeResponse{
+				"GET /v1/projects/demo-project/locations/-/services": {status: http.StatusInternalServerError, file: "gcp/errors/forbidden.json"},
+			},
+			wantKeys: without(allResources, "cloudrun/web"),
+		},
+		{
+			name: "compute rate limit exhausts retries",
+			overrides: map[string]fakeResponse{
+				"GET /compute/v1/projects/demo-project/aggregated/subnetworks": rateLimited,
+			},
+			wantKeys: without(allResources, "subnet/prod-subnet"),
+		},
+		{
+			name: "instances forbidden",
+			overrides: map[string]fakeResponse{
+				"GET /compute/v1/projects/demo-project/aggregated/instances": forbidden,
+			},
+			wantKeys: without(allResources, "gce/us-central1-a/web-1", "gce/us-central1-b/batch-worker"),
+		},
+		{
+			name: "disks forbidden",
+			overrides: map[string]fakeResponse{
+				"GET /compute/v1/projects/demo-project/aggregated/disks": forbidden,
+			},
+			wantKeys: allResources,
+		},
+		{
+			name: "GKE API disabled",
+			overrides: map[string]fakeResponse{
+				"GET /v1/projects/demo-project/locations/-/clusters": forbidden,
+			},
+			wantKeys: without(allResources, "gkecluster/us-central1-a/dev-gke", "gkecluster/us-central1/prod-gke",
+				"gkenodepool/us-central1-a/dev-gke/default-pool", "gkenodepool/us-central1/prod-gke/default-pool", "gkenodepool/us-central1/prod-gke/spot-pool"),
+		},
+		{
+			name: "Cloud SQL Admin API disabled",
+			overrides: map[string]fakeResponse{
+				"GET /v1/projects/demo-project/instances": forbidden,
+			},
+			// Spanner instances are listed on the same path of another host.
+			wantKeys: without(allResources, "cloudsql/orders-db", "cloudsql/reporting-mysql", "spanner/ledger/accounts"),
+		},
+		{
+			name: "Spanner databases forbidden",
+			overrides: map[string]fakeResponse{
+				"GET /v1/projects/demo-project/instances/ledger/databases": forbidden,
+			},
+			wantKeys: without(allResources, "spanner/ledger/accounts"),
+		},
+		{
+			name: "buckets forbidden",
+			overrides: map[string]fakeResponse{
+				"GET /storage/v1/b": forbidden,
+			},
+			wantKeys: without(allResources, "gcs/demo-project-assets", "gcs/demo-project-backups", "gcs/demo-project-legacy"),
+		},
+		{
+			name: "Pub/Sub subscriptions forbidden",
+			overrides: map[string]fakeResponse{
+				"GET /v1/projects/demo-project/subscriptions": forbidden,
+			},
+			wantKeys: without(allResources, "pubsubsubscription/eventarc-us-central1-process-order-123", "pubsubsubscription/orders-worker", "pubsubsubscription/partner-feed"),
+		},
+		{
+			name: "Cloud Scheduler location fails",
+			overrides: map[string]fakeResponse{
+				"GET /v1/projects/demo-project/locations/us-central1/jobs": forbidden,
+			},
+			wantKeys: without(allResources, "schedulerjob/us-central1/cleanup", "schedulerjob/us-central1/nightly-report", "schedulerjob/us-central1/tick"),
+		},
+		{
+			name: "project not accessible",
+			overrides: map[string]fakeResponse{
+				"GET /v1/projects/demo-project": {status: http.StatusForbidden, file: "gcp/errors/permission_denied.json"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeCloud(t)
+			serveDemoProject(f, tt.overrides)
+
+			resources, err := FetchGCPSingleProject(t.Context(), "demo-project", f.gcpOptions()...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchGCPSingleProject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := resourceKeys(resources); !reflect.DeepEqual(got, tt.wantKeys) {
+				t.Errorf("Resources mismatch\n got: %v\nwant: %v", got, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestFetchGCPSingleProjectAttributes(t *testing.T) {
+	f := newFakeCloud(t)
+	serveDemoProject(f, nil)
+
+	resources, err := FetchGCPSingleProject(t.Context(), "demo-project", f.gcpOptions()...)
+	if err != nil {
+		t.Fatalf("FetchGCPSingleProject() failed: %v", err)
+	}
+	index := resourceIndex(resources)
+
+	tests := []struct {
+		key, attribute, want string
+	}{
+		{"project/demo-project", "project_number", "123456789012"},
+		{"vpc/prod-vpc", "mode", "false"},
+		{"subnet/prod-subnet", "cidr_range", "10.10.0.0/20"},
+		{"firewall/allow-ssh", "allowed", "tcp:22"},
+		{"firewall/allow-ssh", "action", "ALLOW"},
+		{"firewall/allow-ssh", "source_ranges", "35.235.240.0/20"},
+		{"firewall/allow-ssh", "target_tags", "ssh"},
+		{"firewall/allow-ssh", "network", "prod-vpc"},
+		{"backendservice/web-backend", "cloud_armor_policy", "https://www.googleapis.com/compute/v1/projects/demo-project/global/securityPolicies/edge-policy"},
+		{"targethttpsproxy/web-proxy", "url_map", "https://www.googleapis.com/compute/v1/projects/demo-project/global/urlMaps/web-map"},
+		{"forwardingrule/web-https", "ip_address", "34.120.1.10"},
+		{"cloudrun/web", "vpc", "prod-vpc"},
+		{"cloudrun/web", "subnet", "prod-subnet"},
+		{"cloudrun/web", "subnet_cidr", "10.10.0.0/20"},
+		{"cloudrun/web", "url", "https://web-abc123-uc.a.run.app"},
+		{"serviceaccount/web-runtime@demo-project.iam.gserviceaccount.com", "roles", "roles/run.invoker, roles/cloudsql.client"},
+		{"serviceaccount/ci-deployer@demo-project.iam.gserviceaccount.com", "disabled", "true"},
+		{"gce/us-central1-a/web-1", "zone", "us-central1-a"},
+		{"gce/us-central1-a/web-1", "machine_type", "e2-medium"},
+		{"gce/us-central1-a/web-1", "status", "RUNNING"},
+		{"gce/us-central1-a/web-1", "private_ip", "10.10.0.5"},
+		{"gce/us-central1-a/web-1", "public_ip", "34.123.45.67"},
+		{"gce/us-central1-a/web-1", "vpc", "prod-vpc"},
+		{"gce/us-central1-a/web-1", "subnet", "prod-subnet"},
+		{"gce/us-central1-a/web-1", "network_tags", "http-server, ssh"},
+		{"gce/us-central1-a/web-1", "service_account", "web-runtime@demo-project.iam.gserviceaccount.com"},
+		{"gce/us-central1-a/web-1", "label:team", "web"},
+		{"gce/us-central1-a/web-1", "boot_disk", "web-1"},
+		{"gce/us-central1-a/web-1", "boot_image", "projects/debian-cloud/global/images/debian-12-bookworm-v20250610"},
+		{"gce/us-central1-a/web-1", "preemptible", "false"},
+		{"gce/us-central1-b/batch-worker", "status", "TERMINATED"},
+		{"gce/us-central1-b/batch-worker", "public_ip", ""},
+		{"gce/us-central1-b/batch-worker", "boot_image", "projects/demo-project/global/images/worker-2025-06"},
+		{"gce/us-central1-b/batch-worker", "preemptible", "true"},
+		{"gkecluster/us-central1/prod-gke", "version", "1.30.5-gke.1014001"},
+		{"gkecluster/us-central1/prod-gke", "endpoint", "10.0.0.2"},
+		{"gkecluster/us-central1/prod-gke", "private_cluster", "true"},
+		{"gkecluster/us-central1/prod-gke", "vpc", "prod-vpc"},
+		{"gkecluster/us-central1/prod-gke", "subnet", "prod-subnet"},
+		{"gkecluster/us-central1/prod-gke", "pod_cidr", "10.20.0.0/14"},
+		{"gkecluster/us-central1/prod-gke", "service_cidr", "10.24.0.0/20"},
+		{"gkecluster/us-central1/prod-gke", "workload_pool", "demo-project.svc.id.goog"},
+		{"gkecluster/us-central1/prod-gke", "release_channel", "regular"},
+		{"gkecluster/us-central1-a/dev-gke", "private_cluster", "false"},
+		{"gkecluster/us-central1-a/dev-gke", "workload_pool", ""},
+		{"gkenodepool/us-central1/prod-gke/default-pool", "parent_id", "us-central1/prod-gke"},
+		{"gkenodepool/us-central1/prod-gke/default-pool", "machine_type", "e2-standard-4"},
+		{"gkenodepool/us-central1/prod-gke/default-pool", "service_account", "gke-nodes@demo-project.iam.gserviceaccount.com"},
+		{"gkenodepool/us-central1/prod-gke/default-pool", "autoscaling", "true"},
+		{"gkenodepool/us-central1/prod-gke/default-pool", "max_nodes_per_zone", "3"},
+		{"gkenodepool/us-central1/prod-gke/spot-pool", "spot", "true"},
+		{"gkenodepool/us-central1/prod-gke/spot-pool", "max_nodes", "10"},
+		{"gkenodepool/us-central1-a/dev-gke/default-pool", "autoscaling", "false"},
+		{"cloudsql/orders-db", "engine", "postgres"},
+		{"cloudsql/orders-db", "database_version", "POSTGRES_15"},
+		{"cloudsql/orders-db", "tier", "db-custom-2-7680"},
+		{"cloudsql/orders-db", "private_ip", "10.50.0.3"},
+		{"cloudsql/orders-db", "public_ip", ""},
+		{"cloudsql/orders-db", "vpc", "prod-vpc"},
+		{"cloudsql/orders-db", "backups_enabled", "true"},
+		{"cloudsql/orders-db", "point_in_time_recovery", "true"},
+		{"cloudsql/orders-db", "availability_type", "regional"},
+		{"cloudsql/orders-db", "label:team", "orders"},
+		{"cloudsql/reporting-mysql", "engine", "mysql"},
+		{"cloudsql/reporting-mysql", "public_ip", "35.231.10.20"},
+		{"cloudsql/reporting-mysql", "vpc", ""},
+		{"cloudsql/reporting-mysql", "authorized_networks", "203.0.113.0/24, 0.0.0.0/0"},
+		{"cloudsql/reporting-mysql", "backups_enabled", "false"},
+		{"redis/us-central1/sessions", "tier", "standard_ha"},
+		{"redis/us-central1/sessions", "memory_size_gb", "5"},
+		{"redis/us-central1/sessions", "endpoint", "10.60.0.4"},
+		{"redis/us-central1/sessions", "port", "6379"},
+		{"redis/us-central1/sessions", "vpc", "prod-vpc"},
+		{"redis/us-central1/sessions", "auth_enabled", "true"},
+		{"spanner/ledger/accounts", "instance", "ledger"},
+		{"spanner/ledger/accounts", "instance_config", "regional-us-central1"},
+		{"spanner/ledger/accounts", "processing_units", "1000"},
+		{"spanner/ledger/accounts", "dialect", "google_standard_sql"},
+		{"spanner/ledger/accounts", "drop_protection", "true"},
+		{"firestore/(default)", "type", "firestore_native"},
+		{"firestore/(default)", "location", "nam5"},
+		{"firestore/(default)", "point_in_time_recovery", "true"},
+		{"firestore/(default)", "delete_protection", "false"},
+		{"gcs/demo-project-assets", "location", "us"},
+		{"gcs/demo-project-assets", "uniform_access", "true"},
+		{"gcs/demo-project-assets", "public", "true"},
+		{"gcs/demo-project-assets", "public_members", "allUsers (roles/storage.objectViewer)"},
+		{"gcs/demo-project-assets", "versioning", "disabled"},
+		{"gcs/demo-project-backups", "storage_class", "NEARLINE"},
+		{"gcs/demo-project-backups", "public_access_prevention", "enforced"},
+		{"gcs/demo-project-backups", "retention_seconds", "2592000"},
+		{"gcs/demo-project-backups", "retention_locked", "true"},
+		{"gcs/demo-project-backups", "versioning", "enabled"},
+		{"gcs/demo-project-backups", "kms_key", "projects/demo-project/locations/us-central1/keyRings/data/cryptoKeys/backups"},
+		{"gcs/demo-project-backups", "public", "false"},
+		{"gcs/demo-project-legacy", "uniform_access", "false"},
+		{"gcs/demo-project-legacy", "public", "false"},
+		{"gcs/demo-project-legacy", "public_members", "allAuthenticatedUsers (roles/storage.legacyObjectReader)"},
+		{"pubsubtopic/orders", "full_name", "projects/demo-project/topics/orders"},
+		{"pubsubtopic/orders", "label:team", "orders"},
+		{"pubsubsubscription/orders-worker", "parent_id", "orders"},
+		{"pubsubsubscription/orders-worker", "delivery", "pull"},
+		{"pubsubsubscription/orders-worker", "filter", `attributes.type = "created"`},
+		{"pubsubsubscription/orders-worker", "dead_letter_topic", "projects/demo-project/topics/orders-dlq"},
+		{"pubsubsubscription/orders-worker", "max_delivery_attempts", "5"},
+		{"pubsubsubscription/eventarc-us-central1-process-order-123", "delivery", "push"},
+		{"pubsubsubscription/eventarc-us-central1-process-order-123", "push_service_account", "web-runtime@demo-project.iam.gserviceaccount.com"},
+		{"pubsubsubscription/eventarc-us-central1-process-order-123", "target_service", "cloudfunction"},
+		{"pubsubsubscription/eventarc-us-central1-process-order-123", "target_id", "us-central1/process-order"},
+		{"pubsubsubscription/partner-feed", "topic", "projects/partner-project/topics/feed"},
+		{"pubsubsubscription/partner-feed", "parent_id", ""},
+		{"cloudfunction/us-central1/nightly-report", "generation", "gen1"},
+		{"cloudfunction/us-central1/nightly-report", "runtime", "nodejs20"},
+		{"cloudfunction/us-central1/nightly-report", "trigger", "http"},
+		{"cloudfunction/us-central1/process-order", "generation", "gen2"},
+		{"cloudfunction/us-central1/process-order", "trigger", "google.cloud.pubsub.topic.v1.messagePublished"},
+		{"cloudfunction/us-central1/process-order", "trigger_topic", "projects/demo-project/topics/orders"},
+		{"cloudfunction/us-central1/process-order", "service_account", "web-runtime@demo-project.iam.gserviceaccount.com"},
+		{"cloudfunction/us-central1/process-order", "vpc_connector", "prod-connector"},
+		{"cloudfunction/us-central1/process-order", "service_url", "https://process-order-xyz789-uc.a.run.app"},
+		{"schedulerjob/us-central1/cleanup", "schedule", "*/30 * * * *"},
+		{"schedulerjob/us-central1/cleanup", "target_type", "http"},
+		{"schedulerjob/us-central1/cleanup", "target_service", "cloudrun"},
+		{"schedulerjob/us-central1/cleanup", "target_id", "web"},
+		{"schedulerjob/us-central1/nightly-report", "target_service", "cloudfunction"},
+		{"schedulerjob/us-central1/nightly-report", "target_id", "us-central1/nightly-report"},
+		{"schedulerjob/us-central1/nightly-report", "last_error", "Permission denied on the target."},
+		{"schedulerjob/us-central1/tick", "status", "paused"},
+		{"schedulerjob/us-central1/tick", "target_type", "pubsub"},
+		{"schedulerjob/us-central1/tick", "target_service", "pubsubtopic"},
+		{"schedulerjob/us-central1/tick", "target_id", "nightly-trigger"},
+		{"schedulerjob/us-central1/tick", "target_project", "demo-project"},
+		{"dnszone/demo-zone", "visibility", "public"},
+		{"dnszone/demo-zone", "dnssec", "on"},
+		{"dnsrecord/demo-zone/www.demo.example.com/A", "values", "34.120.1.10"},
+		{"dnsrecord/demo-zone/www.demo.example.com/A", "parent_id", "demo-zone"},
+		{"dnsrecord/demo-zone/app.demo.example.com/CNAME", "values", "web-abc123-uc.a.run.app."},
+		{"dnsrecord/demo-zone/canary.demo.example.com/A", "routing_policy", "weighted"},
+		{"dnsrecord/demo-zone/canary.demo.example.com/A", "values", "34.120.1.10, 34.120.1.20"},
+		{"dnsrecord/demo-zone/failover.demo.example.com/A", "routing_policy", "failover"},
+		{"dnsrecord/demo-zone/failover.demo.example.com/A", "values", "10.128.0.50, 34.120.1.10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"/"+tt.attribute, func(t *testing.T) {
+			res, ok := index[tt.key]
+			if !ok {
+				t.Fatalf("Resource %s not found", tt.key)
+			}
+			if got := res.Attributes[tt.attribute]; got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.attribute, got, tt.want)
+			}
+			if res.Service != "project" && res.Attributes["project_id"] != "demo-project" {
+				t.Errorf("project_id = %q, want demo-project", res.Attributes["project_id"])
+			}
+		})
+	}
+}
+
+func TestFetchGCPSingleProjectRetriesRateLimits(t *testing.T) {
+	f := newFakeCloud(t)
+	networks := "GET /compute/v1/projects/demo-project/global/networks"
+	f.respond(computeHost, networks, http.StatusTooManyRequests, "gcp/errors/rate_limited.json")
+	serveDemoProject(f, nil)
+
+	resources, err := FetchGCPSingleProject(t.Context(), "demo-project", f.gcpOptions()...)
+	if err != nil {
+		t.Fatalf("FetchGCPSingleProject() failed: %v", err)
+	}
+	if _, ok := resourceIndex(resources)["vpc/prod-vpc"]; !ok {
+		t.Error("VPC missing after a retried rate limit error")
+	}
+	if n := f.calls(computeHost, networks); n != 2 {
+		t.Errorf("Expected the networks call to be retried once, got %d calls", n)
+	}
+}
+
+func without(keys []string, remove ...string) []string {
+	var out []string
+	for _, k := range keys {
+		if !slices.Contains(remove, k) {
+			out = append(out, k)
+		}
+	}
+	return out
+}
@@ -0,0 +1,152 @@
+// fetcher/gcp_dns_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/dns/v1"
+	"google.golang.org/api/option"
+)
+
+func init() {
+	Register(NewFetcher("gcp-dns", "gcp", ScopeProject, []string{"dnszone", "dnsrecord"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchGCPDNSRecords(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+}
+
+// FetchGCPDNSRecords collects the Cloud DNS managed zones of a project with
+// their record sets, in the same shape as FetchRoute53Records: a zone is
+// identified by its name and named after its domain, a record's ID is
+// "<zone>/<name>/<type>" and its "parent_id" is the zone. Records with a
+// routing policy list the data of every policy item in "values", failover
+// records their primary load balancer IPs first. A zone whose records
+// cannot be listed is reported as a ServiceError for dnsrecord.
+func FetchGCPDNSRecords(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	dnsService, err := dns.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dns service for project %s: %w", projectID, err)
+	}
+	log.Printf("   -> Fetching Cloud DNS zones for project: %s", projectID)
+
+	var zones []*dns.ManagedZone
+	zoneCall := dnsService.ManagedZones.List(projectID).Context(ctx)
+	for {
+		page, err := gcpDo(ctx, apiGCPDNS, zoneCall.Do)
+		if err != nil {
+			return nil, fmt.Errorf("could not list managed zones for project %s: %w", projectID, err)
+		}
+		zones = append(zones, page.ManagedZones...)
+		if page.NextPageToken == "" {
+			break
+		}
+		zoneCall.PageToken(page.NextPageToken)
+	}
+
+	var recordErrs []error
+	for _, zone := range zones {
+		resources = append(resources, cloudDNSZoneResource(zone, projectID))
+		recordCall := dnsService.ResourceRecordSets.List(projectID, zone.Name).Context(ctx)
+		for {
+			page, err := gcpDo(ctx, apiGCPDNS, recordCall.Do)
+			if err != nil {
+				recordErrs = append(recordErrs, fmt.Errorf("could not list records of %s: %w", zone.Name, err))
+				break
+			}
+			for _, record := range page.Rrsets {
+				resources = append(resources, cloudDNSRecordResource(record, zone, projectID))
+			}
+			if page.NextPageToken == "" {
+				break
+			}
+			recordCall.PageToken(page.NextPageToken)
+		}
+	}
+	if err := errors.Join(recordErrs...); err != nil {
+		return resources, &ServiceError{Service: "dnsrecord", Err: err}
+	}
+	return resources, nil
+}
+
+func cloudDNSZoneResource(zone *dns.ManagedZone, projectID string) StandardizedResource {
+	attributes := map[string]string{
+		"project_id":  projectID,
+		"visibility":  zone.Visibility,
+		"private":     fmt.Sprintf("%t", zone.Visibility == "private"),
+		"description": zone.Description,
+		"created":     zone.CreationTime,
+	}
+	if zone.DnssecConfig != nil {
+		attributes["dnssec"] = zone.DnssecConfig.State
+	}
+	if zone.PrivateVisibilityConfig != nil {
+		var networks []string
+		for _, network := range zone.PrivateVisibilityConfig.Networks {
+			networks = append(networks, extractResourceName(network.NetworkUrl))
+		}
+		attributes["networks"] = strings.Join(networks, ", ")
+	}
+	return StandardizedResource{
+		Provider:   "gcp",
+		Service:    "dnszone",
+		Region:     "global",
+		ID:         zone.Name,
+		Name:       dnsName(zone.DnsName),
+		Attributes: dropEmpty(attributes),
+	}
+}
+
+func cloudDNSRecordResource(record *dns.ResourceRecordSet, zone *dns.ManagedZone, projectID string) StandardizedResource {
+	name := dnsName(record.Name)
+	values := append([]string(nil), record.Rrdatas...)
+	attributes := map[string]string{
+		"project_id": projectID,
+		"parent_id":  zone.Name,
+		"zone":       dnsName(zone.DnsName),
+		"type":       record.Type,
+		"ttl":        strconv.FormatInt(record.Ttl, 10),
+	}
+	if policy := record.RoutingPolicy; policy != nil {
+		switch {
+		case policy.Geo != nil:
+			attributes["routing_policy"] = "geo"
+			for _, item := range policy.Geo.Items {
+				values = append(values, item.Rrdatas...)
+			}
+		case policy.Wrr != nil:
+			attributes["routing_policy"] = "weighted"
+			for _, item := range policy.Wrr.Items {
+				values = append(values, item.Rrdatas...)
+			}
+		case policy.PrimaryBackup != nil:
+			attributes["routing_policy"] = "failover"
+			// The primary targets are health-checked load balancers, found by their IP.
+			if primary := policy.PrimaryBackup.PrimaryTargets; primary != nil {
+				for _, target := range primary.InternalLoadBalancers {
+					values = append(values, target.IpAddress)
+				}
+				values = append(values, primary.ExternalEndpoints...)
+			}
+			if backup := policy.PrimaryBackup.BackupGeoTargets; backup != nil {
+				for _, item := range backup.Items {
+					values = append(values, item.Rrdatas...)
+				}
+			}
+		}
+	}
+	attributes["values"] = strings.Join(values, ", ")
+	return StandardizedResource{
+		Provider:   "gcp",
+		Service:    "dnsrecord",
+		Region:     "global",
+		ID:         zone.Name + "/" + name + "/" + record.Type,
+		Name:       name,
+		Attributes: dropEmpty(attributes),
+	}
+}
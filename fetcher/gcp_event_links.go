@@ -0,0 +1,87 @@
+// fetcher/gcp_event_links.go
+package fetcher
+
+import (
+	"net/url"
+	"strings"
+)
+
+// LinkEventTargets records which collected resource receives the events of
+// Cloud Scheduler jobs and push subscriptions, so an event path can be traced
+// through the cache. Topics are matched by their full name, and Cloud
+// Functions and Cloud Run services by their URL, across projects. A linked
+// resource's service and ID are set in "target_service" and "target_id", and
+// its project in "target_project". Scheduler jobs and subscriptions come from
+// separate fetchers than their targets, so this runs once all are in.
+func LinkEventTargets(resources []StandardizedResource) {
+	topics := make(map[string]StandardizedResource)
+	var endpoints []eventEndpoint
+	for _, res := range resources {
+		if res.Provider != "gcp" {
+			continue
+		}
+		switch res.Service {
+		case "pubsubtopic":
+			topics[res.Attributes["full_name"]] = res
+		case "cloudfunction", "cloudrun":
+			for _, key := range []string{"url", "service_url"} {
+				if u := eventURL(res.Attributes[key]); u != "" {
+					endpoints = append(endpoints, eventEndpoint{url: u, resource: res})
+				}
+			}
+		}
+	}
+
+	for i := range resources {
+		res := &resources[i]
+		var target StandardizedResource
+		var found bool
+		switch {
+		case res.Service == "schedulerjob" && res.Attributes["target_type"] == "pubsub":
+			target, found = topics[res.Attributes["target"]]
+		case res.Service == "schedulerjob" && res.Attributes["target_type"] == "http":
+			target, found = matchEventEndpoint(endpoints, res.Attributes["target"])
+		case res.Service == "pubsubsubscription" && res.Attributes["push_endpoint"] != "":
+			target, found = matchEventEndpoint(endpoints, res.Attributes["push_endpoint"])
+		}
+		if found {
+			res.Attributes["target_service"] = target.Service
+			res.Attributes["target_id"] = target.ID
+			res.Attributes["target_project"] = target.Attributes["project_id"]
+		}
+	}
+}
+
+type eventEndpoint struct {
+	url      string
+	resource StandardizedResource
+}
+
+// matchEventEndpoint returns the resource whose URL is the longest prefix of
+// target, so a request to a path of a service matches that service.
+func matchEventEndpoint(endpoints []eventEndpoint, target string) (StandardizedResource, bool) {
+	target = eventURL(target)
+	var best *eventEndpoint
+	for i, endpoint := range endpoints {
+		if target != endpoint.url && !strings.HasPrefix(target, endpoint.url+"/") {
+			continue
+		}
+		if best == nil || len(endpoint.url) > len(best.url) {
+			best = &endpoints[i]
+		}
+	}
+	if best == nil {
+		return StandardizedResource{}, false
+	}
+	return best.resource, true
+}
+
+// eventURL normalizes a URL for comparison: lower-case host, no query and no
+// trailing slash.
+func eventURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + strings.ToLower(u.Host) + strings.TrimSuffix(u.Path, "/")
+}
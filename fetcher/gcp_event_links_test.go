@@ -0,0 +1,43 @@
+package fetcher
+
+import "testing"
+
+func TestLinkEventTargets(t *testing.T) {
+	resources := []StandardizedResource{
+		{Provider: "gcp", Service: "pubsubtopic", ID: "events", Attributes: map[string]string{"project_id": "shared-project", "full_name": "projects/shared-project/topics/events"}},
+		{Provider: "gcp", Service: "cloudrun", ID: "api", Attributes: map[string]string{"project_id": "app-project", "url": "https://api-abc-uc.a.run.app"}},
+		{Provider: "gcp", Service: "cloudfunction", ID: "us-central1/hook", Attributes: map[string]string{"project_id": "app-project", "url": "https://us-central1-app-project.cloudfunctions.net/hook"}},
+		{Provider: "gcp", Service: "cloudfunction", ID: "us-central1/hook-v2", Attributes: map[string]string{"project_id": "app-project", "url": "https://us-central1-app-project.cloudfunctions.net/hook-v2"}},
+		{Provider: "gcp", Service: "schedulerjob", ID: "us-central1/publish", Attributes: map[string]string{"target_type": "pubsub", "target": "projects/shared-project/topics/events"}},
+		{Provider: "gcp", Service: "schedulerjob", ID: "us-central1/call-api", Attributes: map[string]string{"target_type": "http", "target": "https://API-abc-uc.a.run.app/jobs/run?force=true"}},
+		{Provider: "gcp", Service: "schedulerjob", ID: "us-central1/call-hook", Attributes: map[string]string{"target_type": "http", "target": "https://us-central1-app-project.cloudfunctions.net/hook-v2/"}},
+		{Provider: "gcp", Service: "schedulerjob", ID: "us-central1/external", Attributes: map[string]string{"target_type": "http", "target": "https://example.com/ping"}},
+		{Provider: "gcp", Service: "schedulerjob", ID: "us-central1/missing-topic", Attributes: map[string]string{"target_type": "pubsub", "target": "projects/app-project/topics/events"}},
+		{Provider: "gcp", Service: "pubsubsubscription", ID: "api-push", Attributes: map[string]string{"push_endpoint": "https://api-abc-uc.a.run.app/pubsub"}},
+	}
+	LinkEventTargets(resources)
+
+	tests := []struct {
+		id, wantService, wantID, wantProject string
+	}{
+		{"us-central1/publish", "pubsubtopic", "events", "shared-project"},
+		{"us-central1/call-api", "cloudrun", "api", "app-project"},
+		{"us-central1/call-hook", "cloudfunction", "us-central1/hook-v2", "app-project"},
+		{"us-central1/external", "", "", ""},
+		{"us-central1/missing-topic", "", "", ""},
+		{"api-push", "cloudrun", "api", "app-project"},
+	}
+	index := make(map[string]StandardizedResource)
+	for _, res := range resources {
+		index[res.ID] = res
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			attributes := index[tt.id].Attributes
+			if attributes["target_service"] != tt.wantService || attributes["target_id"] != tt.wantID || attributes["target_project"] != tt.wantProject {
+				t.Errorf("target = %s/%s in %q, want %s/%s in %q", attributes["target_service"], attributes["target_id"], attributes["target_project"],
+					tt.wantService, tt.wantID, tt.wantProject)
+			}
+		})
+	}
+}
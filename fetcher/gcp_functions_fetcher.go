@@ -0,0 +1,123 @@
+// fetcher/gcp_functions_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	cloudfunctions "google.golang.org/api/cloudfunctions/v2"
+	"google.golang.org/api/option"
+)
+
+func init() {
+	Register(NewFetcher("gcp-functions", "gcp", ScopeProject, []string{"cloudfunction"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchCloudFunctions(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+}
+
+// FetchCloudFunctions collects the Cloud Functions of every region of a
+// project, 1st and 2nd gen alike; "generation" tells them apart. A
+// function's ID is "<region>/<name>". "trigger" is "http" or the event type
+// that triggers it, with the full name of its Pub/Sub topic in
+// "trigger_topic". Regions the API could not reach are reported as a
+// ServiceError, with the functions of the others returned.
+func FetchCloudFunctions(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	functionsService, err := cloudfunctions.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloudfunctions service for project %s: %w", projectID, err)
+	}
+	log.Printf("   -> Fetching Cloud Functions for project: %s", projectID)
+
+	var unreachable []string
+	call := functionsService.Projects.Locations.Functions.List(fmt.Sprintf("projects/%s/locations/-", projectID)).Context(ctx)
+	for {
+		page, err := gcpDo(ctx, apiGCPFunctions, call.Do)
+		if err != nil {
+			return nil, fmt.Errorf("could not list Cloud Functions for project %s: %w", projectID, err)
+		}
+		for _, function := range page.Functions {
+			resources = append(resources, cloudFunctionResource(function, projectID))
+		}
+		unreachable = append(unreachable, page.Unreachable...)
+		if page.NextPageToken == "" {
+			break
+		}
+		call.PageToken(page.NextPageToken)
+	}
+
+	log.Printf("   -> Fetched %d Cloud Functions for project %s", len(resources), projectID)
+	if len(unreachable) > 0 {
+		return resources, &ServiceError{Service: "cloudfunction", Err: fmt.Errorf("could not list Cloud Functions in %s", strings.Join(unreachable, ", "))}
+	}
+	return resources, nil
+}
+
+func cloudFunctionResource(function *cloudfunctions.Function, projectID string) StandardizedResource {
+	region := gcpLocation(function.Name)
+	name := extractResourceName(function.Name)
+	generation := "gen2"
+	if function.Environment == "GEN_1" {
+		generation = "gen1"
+	}
+	attributes := map[string]string{
+		"project_id": projectID,
+		"generation": generation,
+		"status":     strings.ToLower(function.State),
+		"url":        function.Url,
+		"trigger":    "http",
+		"kms_key":    function.KmsKeyName,
+		"updated":    function.UpdateTime,
+	}
+	if build := function.BuildConfig; build != nil {
+		attributes["runtime"] = build.Runtime
+		attributes["entry_point"] = build.EntryPoint
+	}
+	if service := function.ServiceConfig; service != nil {
+		attributes["service_account"] = service.ServiceAccountEmail
+		attributes["ingress"] = strings.ToLower(service.IngressSettings)
+		attributes["memory"] = service.AvailableMemory
+		attributes["timeout"] = fmt.Sprintf("%ds", service.TimeoutSeconds)
+		attributes["max_instances"] = fmt.Sprintf("%d", service.MaxInstanceCount)
+		if service.VpcConnector != "" {
+			attributes["vpc_connector"] = extractResourceName(service.VpcConnector)
+			attributes["vpc_egress"] = strings.ToLower(service.VpcConnectorEgressSettings)
+		}
+		// 2nd gen functions run as a Cloud Run service, with its own URL.
+		if generation == "gen2" {
+			attributes["service_url"] = service.Uri
+			if service.Service != "" {
+				attributes["cloudrun_service"] = extractResourceName(service.Service)
+			}
+		}
+		if attributes["url"] == "" {
+			attributes["url"] = service.Uri
+		}
+	}
+	if trigger := function.EventTrigger; trigger != nil {
+		attributes["trigger"] = trigger.EventType
+		attributes["trigger_topic"] = trigger.PubsubTopic
+		attributes["trigger_region"] = trigger.TriggerRegion
+		var filters []string
+		for _, filter := range trigger.EventFilters {
+			filters = append(filters, filter.Attribute+"="+filter.Value)
+		}
+		attributes["trigger_filters"] = strings.Join(filters, ", ")
+	}
+	attributes = dropEmpty(attributes)
+	for key, value := range function.Labels {
+		attributes["label:"+key] = value
+	}
+
+	return StandardizedResource{
+		Provider:   "gcp",
+		Service:    "cloudfunction",
+		Region:     region,
+		ID:         region + "/" + name,
+		Name:       name,
+		Attributes: attributes,
+	}
+}
@@ -0,0 +1,140 @@
+// fetcher/gcp_gce_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+func init() {
+	Register(NewFetcher("gcp-gce", "gcp", ScopeProject, []string{"gce"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchGCEInstances(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+}
+
+// FetchGCEInstances collects the Compute Engine VM instances of every zone
+// of a project. Like EC2 instances, they record their addresses in
+// "private_ip" and "public_ip", and their labels as "label:<key>"
+// attributes. "network_tags" holds the tags firewall rules select instances
+// by in "target_tags". The boot image is read from the project's disks; when
+// they cannot be listed, instances are returned without it.
+func FetchGCEInstances(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	computeService, err := compute.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compute service for project %s: %w", projectID, err)
+	}
+	log.Printf("   -> Fetching Compute Engine instances for project: %s", projectID)
+
+	var instances []*compute.Instance
+	instanceCall := computeService.Instances.AggregatedList(projectID).Context(ctx)
+	for {
+		page, err := gcpDo(ctx, apiGCPCompute, instanceCall.Do)
+		if err != nil {
+			return nil, fmt.Errorf("could not list instances for project %s: %w", projectID, err)
+		}
+		for _, scope := range page.Items {
+			instances = append(instances, scope.Instances...)
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		instanceCall.PageToken(page.NextPageToken)
+	}
+
+	// Boot disks are matched to instances by their self link.
+	images := make(map[string]string)
+	if len(instances) > 0 {
+		diskCall := computeService.Disks.AggregatedList(projectID).Context(ctx)
+		for {
+			page, err := gcpDo(ctx, apiGCPCompute, diskCall.Do)
+			if err != nil {
+				log.Printf("Warning: could not list disks for project %s, boot images are unknown: %v", projectID, err)
+				break
+			}
+			for _, scope := range page.Items {
+				for _, disk := range scope.Disks {
+					images[disk.SelfLink] = disk.SourceImage
+				}
+			}
+			if page.NextPageToken == "" {
+				break
+			}
+			diskCall.PageToken(page.NextPageToken)
+		}
+	}
+
+	for _, instance := range instances {
+		resources = append(resources, gceInstanceResource(instance, images, projectID))
+	}
+	log.Printf("   -> Fetched %d Compute Engine instances for project %s", len(resources), projectID)
+	return resources, nil
+}
+
+func gceInstanceResource(instance *compute.Instance, images map[string]string, projectID string) StandardizedResource {
+	zone := extractResourceName(instance.Zone)
+	attributes := map[string]string{
+		"project_id":   projectID,
+		"zone":         zone,
+		"machine_type": extractResourceName(instance.MachineType),
+		"status":       instance.Status,
+		"cpu_platform": instance.CpuPlatform,
+		"created":      instance.CreationTimestamp,
+	}
+	if len(instance.NetworkInterfaces) > 0 {
+		// The first interface is the instance's primary one.
+		nic := instance.NetworkInterfaces[0]
+		attributes["private_ip"] = nic.NetworkIP
+		attributes["vpc"] = extractResourceName(nic.Network)
+		attributes["subnet"] = extractResourceName(nic.Subnetwork)
+		for _, access := range nic.AccessConfigs {
+			if access.NatIP != "" {
+				attributes["public_ip"] = access.NatIP
+				break
+			}
+		}
+	}
+	if instance.Tags != nil {
+		attributes["network_tags"] = strings.Join(instance.Tags.Items, ", ")
+	}
+	if len(instance.ServiceAccounts) > 0 {
+		attributes["service_account"] = instance.ServiceAccounts[0].Email
+	}
+	for _, disk := range instance.Disks {
+		if disk.Boot {
+			attributes["boot_disk"] = extractResourceName(disk.Source)
+			attributes["boot_image"] = strings.TrimPrefix(images[disk.Source], "https://www.googleapis.com/compute/v1/")
+		}
+	}
+	if instance.Scheduling != nil {
+		attributes["preemptible"] = fmt.Sprintf("%t", instance.Scheduling.Preemptible || instance.Scheduling.ProvisioningModel == "SPOT")
+	}
+	attributes = dropEmpty(attributes)
+	for key, value := range instance.Labels {
+		attributes["label:"+key] = value
+	}
+
+	return StandardizedResource{
+		Provider:   "gcp",
+		Service:    "gce",
+		Region:     gceZoneRegion(zone),
+		ID:         zone + "/" + instance.Name,
+		Name:       instance.Name,
+		Attributes: attributes,
+	}
+}
+
+// gceZoneRegion returns the region of a zone, e.g. "us-central1" for
+// "us-central1-a".
+func gceZoneRegion(zone string) string {
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		return zone[:i]
+	}
+	return zone
+}
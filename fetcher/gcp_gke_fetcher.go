@@ -0,0 +1,149 @@
+// fetcher/gcp_gke_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"google.golang.org/api/container/v1"
+	"google.golang.org/api/option"
+)
+
+func init() {
+	Register(NewFetcher("gcp-gke", "gcp", ScopeProject, []string{"gkecluster", "gkenodepool"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchGKEClusters(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+}
+
+// FetchGKEClusters collects the GKE clusters of a project, zonal and
+// regional, with their node pools. A cluster's ID is "<location>/<name>";
+// a node pool's is "<location>/<cluster>/<node pool>" and its "parent_id"
+// attribute is the ID of its cluster. Locations the API could not reach
+// are reported as ServiceErrors, with the clusters of the others returned.
+func FetchGKEClusters(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	containerService, err := container.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container service for project %s: %w", projectID, err)
+	}
+	log.Printf("   -> Fetching GKE clusters for project: %s", projectID)
+
+	parent := fmt.Sprintf("projects/%s/locations/-", projectID)
+	list, err := gcpDo(ctx, apiGCPContainer, containerService.Projects.Locations.Clusters.List(parent).Context(ctx).Do)
+	if err != nil {
+		// Node pools are listed with their clusters.
+		err = fmt.Errorf("could not list GKE clusters for project %s: %w", projectID, err)
+		return nil, errors.Join(&ServiceError{Service: "gkecluster", Err: err}, &ServiceError{Service: "gkenodepool", Err: err})
+	}
+	for _, cluster := range list.Clusters {
+		resources = append(resources, gkeClusterResource(cluster, projectID))
+		for _, pool := range cluster.NodePools {
+			resources = append(resources, gkeNodePoolResource(pool, cluster, projectID))
+		}
+	}
+
+	log.Printf("   -> Fetched %d GKE clusters for project %s", len(list.Clusters), projectID)
+	if len(list.MissingZones) > 0 {
+		err := fmt.Errorf("could not list GKE clusters in %s", strings.Join(list.MissingZones, ", "))
+		return resources, errors.Join(&ServiceError{Service: "gkecluster", Err: err}, &ServiceError{Service: "gkenodepool", Err: err})
+	}
+	return resources, nil
+}
+
+func gkeClusterResource(cluster *container.Cluster, projectID string) StandardizedResource {
+	attributes := map[string]string{
+		"project_id":      projectID,
+		"location":        cluster.Location,
+		"version":         cluster.CurrentMasterVersion,
+		"node_version":    cluster.CurrentNodeVersion,
+		"status":          strings.ToLower(cluster.Status),
+		"endpoint":        cluster.Endpoint,
+		"vpc":             cluster.Network,
+		"subnet":          cluster.Subnetwork,
+		"pod_cidr":        cluster.ClusterIpv4Cidr,
+		"service_cidr":    cluster.ServicesIpv4Cidr,
+		"node_count":      fmt.Sprintf("%d", cluster.CurrentNodeCount),
+		"private_cluster": "false",
+		"created":         cluster.CreateTime,
+	}
+	if private := cluster.PrivateClusterConfig; private != nil {
+		attributes["private_cluster"] = fmt.Sprintf("%t", private.EnablePrivateNodes)
+		attributes["private_endpoint"] = fmt.Sprintf("%t", private.EnablePrivateEndpoint)
+		attributes["master_cidr"] = private.MasterIpv4CidrBlock
+	}
+	if identity := cluster.WorkloadIdentityConfig; identity != nil {
+		attributes["workload_pool"] = identity.WorkloadPool
+	}
+	if cluster.Autopilot != nil {
+		attributes["autopilot"] = fmt.Sprintf("%t", cluster.Autopilot.Enabled)
+	}
+	if cluster.ReleaseChannel != nil {
+		attributes["release_channel"] = strings.ToLower(cluster.ReleaseChannel.Channel)
+	}
+	attributes = dropEmpty(attributes)
+	for key, value := range cluster.ResourceLabels {
+		attributes["label:"+key] = value
+	}
+
+	return StandardizedResource{
+		Provider:   "gcp",
+		Service:    "gkecluster",
+		Region:     gkeRegion(cluster.Location),
+		ID:         cluster.Location + "/" + cluster.Name,
+		Name:       cluster.Name,
+		Attributes: attributes,
+	}
+}
+
+func gkeNodePoolResource(pool *container.NodePool, cluster *container.Cluster, projectID string) StandardizedResource {
+	clusterID := cluster.Location + "/" + cluster.Name
+	attributes := map[string]string{
+		"project_id":         projectID,
+		"parent_id":          clusterID,
+		"version":            pool.Version,
+		"status":             strings.ToLower(pool.Status),
+		"locations":          strings.Join(pool.Locations, ", "),
+		"initial_node_count": fmt.Sprintf("%d", pool.InitialNodeCount),
+		"autoscaling":        "false",
+	}
+	if config := pool.Config; config != nil {
+		attributes["machine_type"] = config.MachineType
+		attributes["disk_size_gb"] = fmt.Sprintf("%d", config.DiskSizeGb)
+		attributes["image_type"] = config.ImageType
+		attributes["service_account"] = config.ServiceAccount
+		attributes["spot"] = fmt.Sprintf("%t", config.Spot || config.Preemptible)
+	}
+	if scaling := pool.Autoscaling; scaling != nil && scaling.Enabled {
+		attributes["autoscaling"] = "true"
+		// Total limits apply to the whole pool, the others to each of its zones.
+		if scaling.TotalMaxNodeCount > 0 {
+			attributes["min_nodes"] = fmt.Sprintf("%d", scaling.TotalMinNodeCount)
+			attributes["max_nodes"] = fmt.Sprintf("%d", scaling.TotalMaxNodeCount)
+		} else {
+			attributes["min_nodes_per_zone"] = fmt.Sprintf("%d", scaling.MinNodeCount)
+			attributes["max_nodes_per_zone"] = fmt.Sprintf("%d", scaling.MaxNodeCount)
+		}
+	}
+
+	return StandardizedResource{
+		Provider:   "gcp",
+		Service:    "gkenodepool",
+		Region:     gkeRegion(cluster.Location),
+		ID:         clusterID + "/" + pool.Name,
+		Name:       pool.Name,
+		Attributes: dropEmpty(attributes),
+	}
+}
+
+// gkeRegion returns the region of a cluster's location, which is a zone for
+// zonal clusters, e.g. "us-central1-a", and a region for regional ones.
+func gkeRegion(location string) string {
+	if strings.Count(location, "-") == 2 {
+		return gceZoneRegion(location)
+	}
+	return location
+}
@@ -9,22 +9,29 @@ import (
 
 	crm "google.golang.org/api/cloudresourcemanager/v1" // Use v1 for project policy
 	"google.golang.org/api/iam/v1"
+	"google.golang.org/api/option"
 )
 
+func init() {
+	Register(NewFetcher("gcp-iam", "gcp", ScopeProject, []string{"serviceaccount"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchGCPServiceAccounts(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+}
+
 // FetchGCPServiceAccounts fetches all service accounts and their PROJECT-LEVEL assigned roles.
-func FetchGCPServiceAccounts(projectID string) ([]StandardizedResource, error) {
-	ctx := context.Background()
+func FetchGCPServiceAccounts(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
 	var iamResources []StandardizedResource
 
 	// IAM client (for listing SAs)
-	iamService, err := iam.NewService(ctx)
+	iamService, err := iam.NewService(ctx, opts...)
 	if err != nil {
 		log.Printf("Error creating IAM service for project %s: %v", projectID, err)
 		return nil, fmt.Errorf("failed to create iam service for project %s: %w", projectID, err)
 	}
 
 	// Cloud Resource Manager client (for getting project policy)
-	crmService, err := crm.NewService(ctx)
+	crmService, err := crm.NewService(ctx, opts...)
 	if err != nil {
 		log.Printf("Error creating CRM service for project %s: %v", projectID, err)
 		return nil, fmt.Errorf("failed to create cloudresourcemanager service for project %s: %w", projectID, err)
@@ -33,19 +40,17 @@ func FetchGCPServiceAccounts(projectID string) ([]StandardizedResource, error) {
 	log.Printf("   -> Fetching Service Accounts and Project Roles for project: %s", projectID)
 
 	// --- Step 1: Get the Project's IAM Policy ---
-	projectPolicy, err := crmService.Projects.GetIamPolicy(projectID, &crm.GetIamPolicyRequest{}).Do()
+	projectPolicy, err := gcpDo(ctx, apiGCPResourceManager, crmService.Projects.GetIamPolicy(projectID, &crm.GetIamPolicyRequest{}).Context(ctx).Do)
 	if err != nil {
-		// If we can't get the project policy, we can't determine roles. Log and return empty.
-		log.Printf("Warning: could not get project IAM policy for project %s (permissions issue?): %v", projectID, err)
-		return []StandardizedResource{}, nil
+		// If we can't get the project policy, we can't determine roles.
+		return nil, fmt.Errorf("could not get project IAM policy for project %s: %w", projectID, err)
 	}
 
 	// --- Step 2: List Service Accounts ---
 	parent := fmt.Sprintf("projects/%s", projectID)
-	resp, err := iamService.Projects.ServiceAccounts.List(parent).Do()
+	resp, err := gcpDo(ctx, apiGCPIAM, iamService.Projects.ServiceAccounts.List(parent).Context(ctx).Do)
 	if err != nil {
-		log.Printf("Warning: could not list service accounts for project %s: %v", projectID, err)
-		return []StandardizedResource{}, nil
+		return nil, fmt.Errorf("could not list service accounts for project %s: %w", projectID, err)
 	}
 
 	if resp == nil || len(resp.Accounts) == 0 {
@@ -0,0 +1,147 @@
+// fetcher/gcp_pubsub_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"google.golang.org/api/option"
+	pubsub "google.golang.org/api/pubsub/v1"
+)
+
+func init() {
+	Register(NewFetcher("gcp-pubsub", "gcp", ScopeProject, []string{"pubsubtopic", "pubsubsubscription"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchPubSub(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+}
+
+// FetchPubSub collects the Pub/Sub topics and subscriptions of a project.
+// Both are identified by their short name. A subscription records the full
+// name of its topic in "topic" and, when the topic is in the same project,
+// its ID in "parent_id". Where a push subscription delivers to is linked
+// afterwards by LinkEventTargets.
+func FetchPubSub(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	pubsubService, err := pubsub.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pubsub service for project %s: %w", projectID, err)
+	}
+	log.Printf("   -> Fetching Pub/Sub topics and subscriptions for project: %s", projectID)
+
+	var errs []error
+	topicCall := pubsubService.Projects.Topics.List("projects/" + projectID).Context(ctx)
+	for {
+		page, err := gcpDo(ctx, apiGCPPubSub, topicCall.Do)
+		if err != nil {
+			errs = append(errs, &ServiceError{Service: "pubsubtopic", Err: fmt.Errorf("could not list Pub/Sub topics for project %s: %w", projectID, err)})
+			break
+		}
+		for _, topic := range page.Topics {
+			resources = append(resources, pubsubTopicResource(topic, projectID))
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		topicCall.PageToken(page.NextPageToken)
+	}
+
+	subscriptionCall := pubsubService.Projects.Subscriptions.List("projects/" + projectID).Context(ctx)
+	for {
+		page, err := gcpDo(ctx, apiGCPPubSub, subscriptionCall.Do)
+		if err != nil {
+			errs = append(errs, &ServiceError{Service: "pubsubsubscription", Err: fmt.Errorf("could not list Pub/Sub subscriptions for project %s: %w", projectID, err)})
+			break
+		}
+		for _, subscription := range page.Subscriptions {
+			resources = append(resources, pubsubSubscriptionResource(subscription, projectID))
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		subscriptionCall.PageToken(page.NextPageToken)
+	}
+
+	log.Printf("   -> Fetched %d Pub/Sub topics and subscriptions for project %s", len(resources), projectID)
+	return resources, errors.Join(errs...)
+}
+
+func pubsubTopicResource(topic *pubsub.Topic, projectID string) StandardizedResource {
+	name := extractResourceName(topic.Name)
+	attributes := map[string]string{
+		"project_id":        projectID,
+		"full_name":         topic.Name,
+		"kms_key":           topic.KmsKeyName,
+		"message_retention": topic.MessageRetentionDuration,
+		"status":            strings.ToLower(topic.State),
+	}
+	if topic.SchemaSettings != nil {
+		attributes["schema"] = topic.SchemaSettings.Schema
+	}
+	if policy := topic.MessageStoragePolicy; policy != nil {
+		attributes["allowed_regions"] = strings.Join(policy.AllowedPersistenceRegions, ", ")
+	}
+	attributes = dropEmpty(attributes)
+	for key, value := range topic.Labels {
+		attributes["label:"+key] = value
+	}
+
+	return StandardizedResource{Provider: "gcp", Service: "pubsubtopic", Region: "global", ID: name, Name: name, Attributes: attributes}
+}
+
+func pubsubSubscriptionResource(subscription *pubsub.Subscription, projectID string) StandardizedResource {
+	name := extractResourceName(subscription.Name)
+	attributes := map[string]string{
+		"project_id":        projectID,
+		"full_name":         subscription.Name,
+		"topic":             subscription.Topic,
+		"delivery":          "pull",
+		"filter":            subscription.Filter,
+		"ack_deadline":      fmt.Sprintf("%ds", subscription.AckDeadlineSeconds),
+		"message_retention": subscription.MessageRetentionDuration,
+		"message_ordering":  fmt.Sprintf("%t", subscription.EnableMessageOrdering),
+		"exactly_once":      fmt.Sprintf("%t", subscription.EnableExactlyOnceDelivery),
+		"detached":          fmt.Sprintf("%t", subscription.Detached),
+		"status":            strings.ToLower(subscription.State),
+	}
+	// A subscription whose topic was deleted names "_deleted-topic_" instead.
+	if topicProject, topic, ok := pubsubTopicName(subscription.Topic); ok && topicProject == projectID {
+		attributes["parent_id"] = topic
+	}
+	switch {
+	case subscription.PushConfig != nil && subscription.PushConfig.PushEndpoint != "":
+		attributes["delivery"] = "push"
+		attributes["push_endpoint"] = subscription.PushConfig.PushEndpoint
+		if token := subscription.PushConfig.OidcToken; token != nil {
+			attributes["push_service_account"] = token.ServiceAccountEmail
+		}
+	case subscription.BigqueryConfig != nil && subscription.BigqueryConfig.Table != "":
+		attributes["delivery"] = "bigquery"
+		attributes["bigquery_table"] = subscription.BigqueryConfig.Table
+	case subscription.CloudStorageConfig != nil && subscription.CloudStorageConfig.Bucket != "":
+		attributes["delivery"] = "cloudstorage"
+		attributes["bucket"] = subscription.CloudStorageConfig.Bucket
+	}
+	if policy := subscription.DeadLetterPolicy; policy != nil {
+		attributes["dead_letter_topic"] = policy.DeadLetterTopic
+		attributes["max_delivery_attempts"] = fmt.Sprintf("%d", policy.MaxDeliveryAttempts)
+	}
+	attributes = dropEmpty(attributes)
+	for key, value := range subscription.Labels {
+		attributes["label:"+key] = value
+	}
+
+	return StandardizedResource{Provider: "gcp", Service: "pubsubsubscription", Region: "global", ID: name, Name: name, Attributes: attributes}
+}
+
+// pubsubTopicName splits a topic's full name, "projects/<project>/topics/<topic>".
+func pubsubTopicName(name string) (project, topic string, ok bool) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 4 || parts[0] != "projects" || parts[2] != "topics" {
+		return "", "", false
+	}
+	return parts[1], parts[3], true
+}
@@ -8,25 +8,31 @@ import (
 	"log"
 	"strings"
 
+	"google.golang.org/api/option"
 	"google.golang.org/api/run/v1"
 )
 
+func init() {
+	Register(NewFetcher("gcp-cloudrun", "gcp", ScopeProject, []string{"cloudrun"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchGCPCloudRunServices(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+}
+
 // FetchGCPCloudRunServices fetches all Cloud Run services for a given project using the v1 API.
-// It also enriches the data with subnet CIDR information from the provided network resources.
-func FetchGCPCloudRunServices(projectID string, networkResources []StandardizedResource) ([]StandardizedResource, error) {
-	ctx := context.Background()
+// Subnet CIDRs are filled in afterwards by LinkCloudRunSubnets.
+func FetchGCPCloudRunServices(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
 	var cloudRunResources []StandardizedResource
-	runService, err := run.NewService(ctx)
+	runService, err := run.NewService(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create run service for project %s: %w", projectID, err)
 	}
 
 	log.Printf("   -> Fetching Cloud Run services for project: %s", projectID)
 	parent := fmt.Sprintf("projects/%s/locations/-", projectID)
-	resp, err := runService.Projects.Locations.Services.List(parent).Do()
+	resp, err := gcpDo(ctx, apiGCPRun, runService.Projects.Locations.Services.List(parent).Context(ctx).Do)
 	if err != nil {
-		log.Printf("Warning: could not list Cloud Run services for project %s: %v", projectID, err)
-		return nil, nil
+		return nil, fmt.Errorf("could not list Cloud Run services for project %s: %w", projectID, err)
 	}
 
 	for _, service := range resp.Items {
@@ -60,14 +66,6 @@ func FetchGCPCloudRunServices(projectID string, networkResources []StandardizedR
 					}
 					if subnetName != "" {
 						attributes["subnet"] = subnetName
-						// Look up the subnet CIDR from network resources
-						log.Printf("[DEBUG] Looking up CIDR for subnet: %s among %d network resources", subnetName, len(networkResources))
-						if cidr := findSubnetCIDR(networkResources, subnetName); cidr != "" {
-							attributes["subnet_cidr"] = cidr
-							log.Printf("[DEBUG] Found and set CIDR: %s", cidr)
-						} else {
-							log.Printf("[DEBUG] CIDR not found for subnet: %s", subnetName)
-						}
 					}
 				}
 			}
@@ -127,6 +125,32 @@ func parseNetworkInterfaces(networkInterfacesJSON string, projectID string) (vpc
 	return vpcName, subnetName
 }
 
+// LinkCloudRunSubnets fills in subnet_cidr on Cloud Run services that use direct
+// VPC egress, using the subnets collected for the same project. Cloud Run and
+// network resources come from separate fetchers, so the lookup runs once both are in.
+func LinkCloudRunSubnets(resources []StandardizedResource) {
+	subnetsByProject := make(map[string][]StandardizedResource)
+	for _, resource := range resources {
+		if resource.Provider == "gcp" && resource.Service == "subnet" {
+			projectID := resource.Attributes["project_id"]
+			subnetsByProject[projectID] = append(subnetsByProject[projectID], resource)
+		}
+	}
+
+	for i := range resources {
+		resource := &resources[i]
+		if resource.Service != "cloudrun" || resource.Attributes == nil || resource.Attributes["subnet_cidr"] != "" {
+			continue
+		}
+		// VPC connectors don't have a direct CIDR, they use a subnet internally
+		subnetName := resource.Attributes["subnet"]
+		if subnetName == "" || subnetName == "N/A" || resource.Attributes["vpc"] == "via-connector" {
+			continue
+		}
+		resource.Attributes["subnet_cidr"] = findSubnetCIDR(subnetsByProject[resource.Attributes["project_id"]], subnetName)
+	}
+}
+
 // findSubnetCIDR looks up the CIDR range for a given subnet name from the network resources
 func findSubnetCIDR(networkResources []StandardizedResource, subnetName string) string {
 	if subnetName == "" {
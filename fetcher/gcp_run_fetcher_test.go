@@ -193,3 +193,56 @@ func TestFindSubnetCIDR(t *testing.T) {
 		})
 	}
 }
+
+func TestLinkCloudRunSubnets(t *testing.T) {
+	resources := []StandardizedResource{
+		{
+			Provider:   "gcp",
+			Service:    "subnet",
+			ID:         "my-subnet",
+			Name:       "my-subnet",
+			Attributes: map[string]string{"project_id": "project-a", "cidr_range": "10.0.1.0/24"},
+		},
+		{
+			Provider:   "gcp",
+			Service:    "subnet",
+			ID:         "my-subnet",
+			Name:       "my-subnet",
+			Attributes: map[string]string{"project_id": "project-b", "cidr_range": "10.9.9.0/24"},
+		},
+		{
+			Provider:   "gcp",
+			Service:    "cloudrun",
+			ID:         "direct-egress",
+			Attributes: map[string]string{"project_id": "project-a", "vpc": "my-vpc", "subnet": "my-subnet", "subnet_cidr": ""},
+		},
+		{
+			Provider:   "gcp",
+			Service:    "cloudrun",
+			ID:         "via-connector",
+			Attributes: map[string]string{"project_id": "project-a", "vpc": "via-connector", "subnet": "my-subnet", "subnet_cidr": ""},
+		},
+		{
+			Provider:   "gcp",
+			Service:    "cloudrun",
+			ID:         "no-vpc",
+			Attributes: map[string]string{"project_id": "project-a", "vpc": "N/A", "subnet": "N/A", "subnet_cidr": ""},
+		},
+	}
+
+	LinkCloudRunSubnets(resources)
+
+	expected := map[string]string{
+		"direct-egress": "10.0.1.0/24",
+		"via-connector": "",
+		"no-vpc":        "",
+	}
+	for _, res := range resources {
+		if res.Service != "cloudrun" {
+			continue
+		}
+		if got := res.Attributes["subnet_cidr"]; got != expected[res.ID] {
+			t.Errorf("%s: subnet_cidr = %q, expected %q", res.ID, got, expected[res.ID])
+		}
+	}
+}
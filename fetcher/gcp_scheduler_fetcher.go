@@ -0,0 +1,123 @@
+// fetcher/gcp_scheduler_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	cloudscheduler "google.golang.org/api/cloudscheduler/v1"
+	"google.golang.org/api/option"
+)
+
+func init() {
+	Register(NewFetcher("gcp-scheduler", "gcp", ScopeProject, []string{"schedulerjob"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchSchedulerJobs(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+}
+
+// FetchSchedulerJobs collects the Cloud Scheduler jobs of a project. Jobs
+// can only be listed one location at a time, so the locations Cloud
+// Scheduler offers the project are listed first. A job's ID is
+// "<location>/<name>"; "target_type" is "http", "pubsub" or "appengine" and
+// "target" holds the URL, topic or App Engine service it calls. The cached
+// resource behind the target is linked afterwards by LinkEventTargets.
+func FetchSchedulerJobs(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
+	var resources []StandardizedResource
+	schedulerService, err := cloudscheduler.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloudscheduler service for project %s: %w", projectID, err)
+	}
+	log.Printf("   -> Fetching Cloud Scheduler jobs for project: %s", projectID)
+
+	var locations []string
+	locationCall := schedulerService.Projects.Locations.List("projects/" + projectID).Context(ctx)
+	for {
+		page, err := gcpDo(ctx, apiGCPScheduler, locationCall.Do)
+		if err != nil {
+			return nil, fmt.Errorf("could not list Cloud Scheduler locations for project %s: %w", projectID, err)
+		}
+		for _, location := range page.Locations {
+			locations = append(locations, location.LocationId)
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		locationCall.PageToken(page.NextPageToken)
+	}
+
+	var errs []error
+	for _, location := range locations {
+		jobCall := schedulerService.Projects.Locations.Jobs.List(fmt.Sprintf("projects/%s/locations/%s", projectID, location)).Context(ctx)
+		for {
+			page, err := gcpDo(ctx, apiGCPScheduler, jobCall.Do)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("could not list Cloud Scheduler jobs in %s: %w", location, err))
+				break
+			}
+			for _, job := range page.Jobs {
+				resources = append(resources, schedulerJobResource(job, location, projectID))
+			}
+			if page.NextPageToken == "" {
+				break
+			}
+			jobCall.PageToken(page.NextPageToken)
+		}
+	}
+
+	log.Printf("   -> Fetched %d Cloud Scheduler jobs for project %s", len(resources), projectID)
+	if err := errors.Join(errs...); err != nil {
+		return resources, &ServiceError{Service: "schedulerjob", Err: err}
+	}
+	return resources, nil
+}
+
+func schedulerJobResource(job *cloudscheduler.Job, location, projectID string) StandardizedResource {
+	name := extractResourceName(job.Name)
+	attributes := map[string]string{
+		"project_id":   projectID,
+		"description":  job.Description,
+		"schedule":     job.Schedule,
+		"time_zone":    job.TimeZone,
+		"status":       strings.ToLower(job.State),
+		"last_attempt": job.LastAttemptTime,
+		"next_run":     job.ScheduleTime,
+	}
+	if job.Status != nil && job.Status.Code != 0 {
+		attributes["last_error"] = job.Status.Message
+	}
+	switch {
+	case job.HttpTarget != nil:
+		attributes["target_type"] = "http"
+		attributes["target"] = job.HttpTarget.Uri
+		attributes["http_method"] = job.HttpTarget.HttpMethod
+		if token := job.HttpTarget.OidcToken; token != nil {
+			attributes["service_account"] = token.ServiceAccountEmail
+		} else if token := job.HttpTarget.OauthToken; token != nil {
+			attributes["service_account"] = token.ServiceAccountEmail
+		}
+	case job.PubsubTarget != nil:
+		attributes["target_type"] = "pubsub"
+		attributes["target"] = job.PubsubTarget.TopicName
+	case job.AppEngineHttpTarget != nil:
+		attributes["target_type"] = "appengine"
+		attributes["http_method"] = job.AppEngineHttpTarget.HttpMethod
+		service := "default"
+		if routing := job.AppEngineHttpTarget.AppEngineRouting; routing != nil && routing.Service != "" {
+			service = routing.Service
+		}
+		attributes["target"] = service + job.AppEngineHttpTarget.RelativeUri
+	}
+
+	return StandardizedResource{
+		Provider:   "gcp",
+		Service:    "schedulerjob",
+		Region:     location,
+		ID:         location + "/" + name,
+		Name:       name,
+		Attributes: dropEmpty(attributes),
+	}
+}
@@ -0,0 +1,142 @@
+// fetcher/gcp_storage_fetcher.go
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"google.golang.org/api/option"
+	storage "google.golang.org/api/storage/v1"
+)
+
+func init() {
+	Register(NewFetcher("gcp-storage", "gcp", ScopeProject, []string{"gcs"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return FetchGCSBuckets(ctx, scope.ProjectID, scope.GCPOptions...)
+		}))
+}
+
+// gcsPublicMembers are the IAM members that grant access to anyone.
+var gcsPublicMembers = map[string]bool{"allUsers": true, "allAuthenticatedUsers": true}
+
+// FetchGCSBuckets collects the Cloud Storage buckets of a project with their
+// security posture: uniform bucket-level access, public access prevention,
+// retention policy, versioning and CMEK key. Each bucket's IAM policy is read
+// to set "public", which is "true" when allUsers or allAuthenticatedUsers are
+// granted a role and public access prevention does not block them;
+// "public_members" lists those grants either way. A policy the credentials
+// may not read is recorded as "access_denied", as for S3 buckets.
+func FetchGCSBuckets(ctx context.Context, projectID string, opts ...option.ClientOption) ([]StandardizedResource, error) {
+	storageService, err := storage.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage service for project %s: %w", projectID, err)
+	}
+	log.Printf("   -> Fetching Cloud Storage buckets for project: %s", projectID)
+
+	var buckets []*storage.Bucket
+	call := storageService.Buckets.List(projectID).Context(ctx)
+	for {
+		page, err := gcpDo(ctx, apiGCPStorage, call.Do)
+		if err != nil {
+			return nil, fmt.Errorf("could not list buckets for project %s: %w", projectID, err)
+		}
+		buckets = append(buckets, page.Items...)
+		if page.NextPageToken == "" {
+			break
+		}
+		call.PageToken(page.NextPageToken)
+	}
+
+	resources := make([]StandardizedResource, len(buckets))
+	errs := make([]error, len(buckets))
+	sem := make(chan struct{}, maxBucketConcurrency)
+	var wg sync.WaitGroup
+	for i, bucket := range buckets {
+		resources[i] = gcsBucketResource(bucket, projectID)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			policy, err := gcpDo(ctx, apiGCPStorage, storageService.Buckets.GetIamPolicy(bucket.Name).Context(ctx).Do)
+			switch {
+			case err == nil:
+				setGCSPublicAccess(resources[i].Attributes, policy)
+			case IsPermissionDenied(err):
+				resources[i].Attributes["public"] = "access_denied"
+			default:
+				errs[i] = fmt.Errorf("bucket %s: could not get IAM policy: %w", bucket.Name, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	log.Printf("   -> Fetched %d Cloud Storage buckets for project %s", len(resources), projectID)
+	return resources, errors.Join(errs...)
+}
+
+func gcsBucketResource(bucket *storage.Bucket, projectID string) StandardizedResource {
+	attributes := map[string]string{
+		"project_id":    projectID,
+		"location":      strings.ToLower(bucket.Location),
+		"location_type": bucket.LocationType,
+		"storage_class": bucket.StorageClass,
+		"versioning":    "disabled",
+		"created":       bucket.TimeCreated,
+	}
+	if iam := bucket.IamConfiguration; iam != nil {
+		attributes["uniform_access"] = fmt.Sprintf("%t", iam.UniformBucketLevelAccess != nil && iam.UniformBucketLevelAccess.Enabled)
+		attributes["public_access_prevention"] = iam.PublicAccessPrevention
+	}
+	if retention := bucket.RetentionPolicy; retention != nil {
+		attributes["retention_seconds"] = fmt.Sprintf("%d", retention.RetentionPeriod)
+		attributes["retention_locked"] = fmt.Sprintf("%t", retention.IsLocked)
+	}
+	if bucket.Versioning != nil && bucket.Versioning.Enabled {
+		attributes["versioning"] = "enabled"
+	}
+	if bucket.Encryption != nil {
+		attributes["kms_key"] = bucket.Encryption.DefaultKmsKeyName
+	}
+	attributes = dropEmpty(attributes)
+	for key, value := range bucket.Labels {
+		attributes["label:"+key] = value
+	}
+
+	return StandardizedResource{
+		Provider:   "gcp",
+		Service:    "gcs",
+		Region:     strings.ToLower(bucket.Location),
+		ID:         bucket.Name,
+		Name:       bucket.Name,
+		Attributes: attributes,
+	}
+}
+
+// setGCSPublicAccess records the grants of policy to allUsers and
+// allAuthenticatedUsers as "<member> (<role>)" in "public_members".
+func setGCSPublicAccess(attributes map[string]string, policy *storage.Policy) {
+	var grants []string
+	for _, binding := range policy.Bindings {
+		for _, member := range binding.Members {
+			if gcsPublicMembers[member] {
+				grants = append(grants, fmt.Sprintf("%s (%s)", member, binding.Role))
+			}
+		}
+	}
+	// Enforced prevention overrides any public grant.
+	public := len(grants) > 0 && attributes["public_access_prevention"] != "enforced"
+	attributes["public"] = fmt.Sprintf("%t", public)
+	if len(grants) > 0 {
+		attributes["public_members"] = strings.Join(grants, ", ")
+	}
+}
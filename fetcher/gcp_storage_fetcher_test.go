@@ -0,0 +1,32 @@
+package fetcher
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestFetchGCSBucketsIAMPolicyDenied(t *testing.T) {
+	f := newFakeCloud(t)
+	f.handle(storageHost, "GET /storage/v1/b", "gcp/demo-project/buckets.json")
+	f.respond(storageHost, "GET /storage/v1/b/demo-project-assets/iam", http.StatusForbidden, "gcp/errors/forbidden.json")
+	f.handle(storageHost, "GET /storage/v1/b/demo-project-backups/iam", "gcp/demo-project/bucket_iam_backups.json")
+	f.respond(storageHost, "GET /storage/v1/b/demo-project-legacy/iam", http.StatusBadRequest, "gcp/errors/bad_request.json")
+
+	resources, err := FetchGCSBuckets(t.Context(), "demo-project", f.gcpOptions()...)
+	if err == nil {
+		t.Error("Expected an error for the bucket whose policy could not be read")
+	}
+	index := resourceIndex(resources)
+	if len(index) != 3 {
+		t.Fatalf("Expected every bucket to be returned, got %v", resourceKeys(resources))
+	}
+	for key, want := range map[string]string{
+		"gcs/demo-project-assets":  "access_denied",
+		"gcs/demo-project-backups": "false",
+		"gcs/demo-project-legacy":  "",
+	} {
+		if got := index[key].Attributes["public"]; got != want {
+			t.Errorf("%s: public = %q, want %q", key, got, want)
+		}
+	}
+}
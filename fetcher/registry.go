@@ -0,0 +1,252 @@
+// fetcher/registry.go
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"google.golang.org/api/option"
+)
+
+// Scope narrows what a Fetcher collects. The zero value means "everything the
+// current credentials can see".
+type Scope struct {
+	// ProjectID is the GCP project a project-scoped fetcher collects from.
+	// When set for a provider-scoped GCP fetcher, discovery is limited to that project.
+	ProjectID string
+	// Account is the AWS account an account-scoped fetcher collects from.
+	// When only its ID is set for a provider-scoped AWS fetcher, discovery is
+	// limited to that account.
+	Account AWSAccount
+
+	// GCPOptions are passed to every Google API client, e.g. to point the
+	// fetchers at a test server.
+	GCPOptions []option.ClientOption
+	// AWSConfig replaces the default AWS configuration when set. It is used
+	// as the source credentials when Account has a role to assume.
+	AWSConfig *aws.Config
+	// AWSRegions limits regional AWS fetchers to these regions. When empty,
+	// every region enabled for the account is collected.
+	AWSRegions []string
+	// AWSAccounts are the accounts AWS discovery resolves. When empty, the
+	// default credentials are used.
+	AWSAccounts []AWSAccount
+	// AWSOrganization configures AWS Organizations discovery.
+	AWSOrganization AWSOrganization
+}
+
+// WithProject returns a copy of the scope limited to projectID.
+func (s Scope) WithProject(projectID string) Scope {
+	s.ProjectID = projectID
+	return s
+}
+
+// WithAccount returns a copy of the scope limited to account.
+func (s Scope) WithAccount(account AWSAccount) Scope {
+	s.Account = account
+	return s
+}
+
+// Key returns the project or account ID the scope is limited to, or "".
+func (s Scope) Key() string {
+	if s.ProjectID != "" {
+		return s.ProjectID
+	}
+	return s.Account.ID
+}
+
+// String describes the scope for log and error messages.
+func (s Scope) String() string {
+	switch {
+	case s.ProjectID != "":
+		return "project " + s.ProjectID
+	case s.Account.ID != "":
+		return "account " + s.Account.ID
+	default:
+		return "all"
+	}
+}
+
+// ChildScope returns the scope that project-scoped fetchers run in for a
+// resource found by discovery: a GCP project or an AWS account.
+func ChildScope(parent Scope, res StandardizedResource) (Scope, bool) {
+	if res.ID == "" || res.ID == "N/A" {
+		return Scope{}, false
+	}
+	switch res.Service {
+	case "project":
+		return parent.WithProject(res.ID), true
+	case "aws-account":
+		if res.Attributes["collect"] == "false" {
+			return Scope{}, false
+		}
+		return parent.WithAccount(parent.awsAccountFor(res)), true
+	}
+	return Scope{}, false
+}
+
+// ScopeKind describes how often the sync command invokes a Fetcher.
+type ScopeKind int
+
+const (
+	// ScopeProvider fetchers run once per provider sync.
+	ScopeProvider ScopeKind = iota
+	// ScopeProject fetchers run once for every discovered GCP project or AWS account.
+	ScopeProject
+)
+
+// String returns the name used for the kind in `infrakit sync --list`.
+func (k ScopeKind) String() string {
+	switch k {
+	case ScopeProject:
+		return "project"
+	default:
+		return "provider"
+	}
+}
+
+// Fetcher collects one or more services from a single cloud provider.
+type Fetcher interface {
+	// Name uniquely identifies the collector, e.g. "aws-ec2".
+	Name() string
+	// Provider is the cloud the collector talks to ("aws" or "gcp").
+	Provider() string
+	// Services lists the StandardizedResource.Service values the collector emits.
+	Services() []string
+	// Fetch collects resources within the given scope.
+	Fetch(ctx context.Context, scope Scope) ([]StandardizedResource, error)
+}
+
+// FetchFunc is the signature of the function wrapped by NewFetcher.
+type FetchFunc func(ctx context.Context, scope Scope) ([]StandardizedResource, error)
+
+// collector is the Fetcher implementation returned by NewFetcher.
+type collector struct {
+	name     string
+	provider string
+	services []string
+	kind     ScopeKind
+	fetch    FetchFunc
+}
+
+// NewFetcher builds a Fetcher from a plain fetch function.
+func NewFetcher(name, provider string, kind ScopeKind, services []string, fetch FetchFunc) Fetcher {
+	return &collector{name: name, provider: provider, services: services, kind: kind, fetch: fetch}
+}
+
+func (c *collector) Name() string       { return c.name }
+func (c *collector) Provider() string   { return c.provider }
+func (c *collector) Services() []string { return c.services }
+func (c *collector) Kind() ScopeKind    { return c.kind }
+
+func (c *collector) Fetch(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+	return c.fetch(ctx, scope)
+}
+
+// ScopeKindOf reports how f expects to be invoked. Fetchers without a
+// Kind method are provider-scoped.
+func ScopeKindOf(f Fetcher) ScopeKind {
+	if k, ok := f.(interface{ Kind() ScopeKind }); ok {
+		return k.Kind()
+	}
+	return ScopeProvider
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Fetcher)
+)
+
+// Register makes a Fetcher available to `infrakit sync`. It is meant to be
+// called from init functions and panics if the name is already taken.
+func Register(f Fetcher) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if f == nil {
+		panic("fetcher: Register fetcher is nil")
+	}
+	if _, dup := registry[f.Name()]; dup {
+		panic("fetcher: Register called twice for fetcher " + f.Name())
+	}
+	registry[f.Name()] = f
+}
+
+// Fetchers returns every registered Fetcher, sorted by provider and name.
+func Fetchers() []Fetcher {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	fetchers := make([]Fetcher, 0, len(registry))
+	for _, f := range registry {
+		fetchers = append(fetchers, f)
+	}
+	sort.Slice(fetchers, func(i, j int) bool {
+		if fetchers[i].Provider() != fetchers[j].Provider() {
+			return fetchers[i].Provider() < fetchers[j].Provider()
+		}
+		return fetchers[i].Name() < fetchers[j].Name()
+	})
+	return fetchers
+}
+
+// FetchersFor returns the registered Fetchers for a provider and scope kind.
+func FetchersFor(provider string, kind ScopeKind) []Fetcher {
+	var fetchers []Fetcher
+	for _, f := range Fetchers() {
+		if f.Provider() == provider && ScopeKindOf(f) == kind {
+			fetchers = append(fetchers, f)
+		}
+	}
+	return fetchers
+}
+
+// Providers returns the distinct providers that have at least one Fetcher.
+func Providers() []string {
+	var providers []string
+	seen := make(map[string]bool)
+	for _, f := range Fetchers() {
+		if !seen[f.Provider()] {
+			seen[f.Provider()] = true
+			providers = append(providers, f.Provider())
+		}
+	}
+	return providers
+}
+
+// LookupFetcher returns the Fetcher registered under name.
+func LookupFetcher(name string) (Fetcher, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := registry[name]
+	return f, ok
+}
+
+// FetchProjectResources runs every project-scoped Fetcher of a provider
+// against the project in scope. A failing collector is logged and whatever
+// it did collect is kept, so one broken API does not hide the rest of the project.
+func FetchProjectResources(ctx context.Context, provider string, scope Scope) []StandardizedResource {
+	var resources []StandardizedResource
+	for _, f := range FetchersFor(provider, ScopeProject) {
+		res, err := f.Fetch(ctx, scope)
+		if err != nil {
+			log.Printf("Warning: %s failed for %s: %v", f.Name(), scope, err)
+		}
+		resources = append(resources, res...)
+	}
+	LinkCloudRunSubnets(resources)
+	LinkEventTargets(resources)
+	return resources
+}
+
+// ValidateProvider returns an error if no Fetcher is registered for provider.
+func ValidateProvider(provider string) error {
+	for _, p := range Providers() {
+		if p == provider {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid provider '%s'. Valid providers are %v", provider, Providers())
+}
@@ -0,0 +1,120 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBuiltinFetchersRegistered(t *testing.T) {
+	expected := map[string]ScopeKind{
+		"aws-accounts":       ScopeProvider,
+		"aws-acm":            ScopeProject,
+		"aws-ec2":            ScopeProject,
+		"aws-ecs":            ScopeProject,
+		"aws-elb":            ScopeProject,
+		"aws-eks":            ScopeProject,
+		"aws-iam":            ScopeProject,
+		"aws-lambda":         ScopeProject,
+		"aws-network":        ScopeProject,
+		"aws-rds":            ScopeProject,
+		"aws-route53":        ScopeProject,
+		"aws-s3":             ScopeProject,
+		"aws-secretsmanager": ScopeProject,
+		"aws-ssm":            ScopeProject,
+		"gcp-projects":       ScopeProvider,
+		"gcp-network":        ScopeProject,
+		"gcp-cloudrun":       ScopeProject,
+		"gcp-appinfra":       ScopeProject,
+		"gcp-iam":            ScopeProject,
+		"gcp-dns":            ScopeProject,
+		"gcp-gce":            ScopeProject,
+		"gcp-gke":            ScopeProject,
+		"gcp-cloudsql":       ScopeProject,
+		"gcp-redis":          ScopeProject,
+		"gcp-spanner":        ScopeProject,
+		"gcp-firestore":      ScopeProject,
+		"gcp-storage":        ScopeProject,
+		"gcp-pubsub":         ScopeProject,
+		"gcp-functions":      ScopeProject,
+		"gcp-scheduler":      ScopeProject,
+	}
+
+	for name, kind := range expected {
+		f, ok := LookupFetcher(name)
+		if !ok {
+			t.Errorf("Fetcher %q is not registered", name)
+			continue
+		}
+		if ScopeKindOf(f) != kind {
+			t.Errorf("Fetcher %q: expected scope %s, got %s", name, kind, ScopeKindOf(f))
+		}
+		if len(f.Services()) == 0 {
+			t.Errorf("Fetcher %q does not declare any services", name)
+		}
+	}
+}
+
+func TestFetchersSortedByProviderAndName(t *testing.T) {
+	fetchers := Fetchers()
+	for i := 1; i < len(fetchers); i++ {
+		prev, cur := fetchers[i-1], fetchers[i]
+		if prev.Provider() > cur.Provider() || (prev.Provider() == cur.Provider() && prev.Name() > cur.Name()) {
+			t.Errorf("Fetchers not sorted: %s/%s before %s/%s", prev.Provider(), prev.Name(), cur.Provider(), cur.Name())
+		}
+	}
+}
+
+func TestFetchersFor(t *testing.T) {
+	for _, f := range FetchersFor("gcp", ScopeProject) {
+		if f.Provider() != "gcp" || ScopeKindOf(f) != ScopeProject {
+			t.Errorf("FetchersFor(gcp, project) returned %s (%s, %s)", f.Name(), f.Provider(), ScopeKindOf(f))
+		}
+	}
+	if len(FetchersFor("azure", ScopeProvider)) != 0 {
+		t.Error("Expected no fetchers for an unregistered provider")
+	}
+}
+
+func TestValidateProvider(t *testing.T) {
+	if err := ValidateProvider("aws"); err != nil {
+		t.Errorf("ValidateProvider(aws) returned error: %v", err)
+	}
+	if err := ValidateProvider("gcp"); err != nil {
+		t.Errorf("ValidateProvider(gcp) returned error: %v", err)
+	}
+	if err := ValidateProvider("azure"); err == nil {
+		t.Error("ValidateProvider(azure) should return an error")
+	}
+}
+
+func TestNewFetcher(t *testing.T) {
+	f := NewFetcher("test-fetcher", "test", ScopeProject, []string{"widget"},
+		func(ctx context.Context, scope Scope) ([]StandardizedResource, error) {
+			return []StandardizedResource{{Provider: "test", Service: "widget", ID: scope.ProjectID}}, nil
+		})
+
+	if f.Name() != "test-fetcher" || f.Provider() != "test" {
+		t.Errorf("Unexpected name/provider: %s/%s", f.Name(), f.Provider())
+	}
+	if ScopeKindOf(f) != ScopeProject {
+		t.Errorf("Expected project scope, got %s", ScopeKindOf(f))
+	}
+
+	resources, err := f.Fetch(context.Background(), Scope{ProjectID: "my-project"})
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+	if len(resources) != 1 || resources[0].ID != "my-project" {
+		t.Errorf("Fetch did not receive the scope, got %+v", resources)
+	}
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Register to panic on a duplicate name")
+		}
+	}()
+	f, _ := LookupFetcher("aws-ec2")
+	Register(f)
+}
@@ -0,0 +1,65 @@
+// fetcher/report.go
+package fetcher
+
+import "time"
+
+// SyncStatus is the outcome of collecting one service within one scope.
+type SyncStatus string
+
+const (
+	// StatusSucceeded means the service was listed completely (possibly with zero resources).
+	StatusSucceeded SyncStatus = "succeeded"
+	// StatusSkipped means the credentials were not allowed to read the service,
+	// or its API is not enabled.
+	StatusSkipped SyncStatus = "skipped"
+	// StatusFailed means the API call failed for any other reason.
+	StatusFailed SyncStatus = "failed"
+)
+
+// ServiceStatus records how collection went for one service of one provider
+// within one project (or provider-wide when Scope is empty).
+type ServiceStatus struct {
+	Provider  string     `json:"provider"`
+	Scope     string     `json:"scope,omitempty"`
+	Level     string     `json:"level"`
+	Service   string     `json:"service"`
+	Fetcher   string     `json:"fetcher"`
+	Status    SyncStatus `json:"status"`
+	Resources int        `json:"resources"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// SyncReport summarises a sync run. It is written to ~/.infrakit/last_sync.json.
+// Scope is set when the run was limited to a single project.
+type SyncReport struct {
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Providers  []string        `json:"providers"`
+	Scope      string          `json:"scope,omitempty"`
+	Aborted    string          `json:"aborted,omitempty"`
+	Services   []ServiceStatus `json:"services"`
+	APICalls   []APIStats      `json:"api_calls,omitempty"`
+}
+
+// Count returns how many service statuses in the report have the given status.
+func (r SyncReport) Count(status SyncStatus) int {
+	n := 0
+	for _, s := range r.Services {
+		if s.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// StatusFor classifies a fetch error.
+func StatusFor(err error) SyncStatus {
+	switch {
+	case err == nil:
+		return StatusSucceeded
+	case IsPermissionDenied(err):
+		return StatusSkipped
+	default:
+		return StatusFailed
+	}
+}
@@ -0,0 +1,291 @@
+// fetcher/retry.go
+package fetcher
+
+import (
+	"context"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Names of the APIs the fetchers call, used to key rate limits and counters.
+const (
+	apiGCPCompute         = "gcp-compute"
+	apiGCPIAM             = "gcp-iam"
+	apiGCPResourceManager = "gcp-resourcemanager"
+	apiGCPCloudAsset      = "gcp-cloudasset"
+	apiGCPRun             = "gcp-run"
+	apiGCPDNS             = "gcp-dns"
+	apiGCPContainer       = "gcp-container"
+	apiGCPSQLAdmin        = "gcp-sqladmin"
+	apiGCPRedis           = "gcp-redis"
+	apiGCPSpanner         = "gcp-spanner"
+	apiGCPFirestore       = "gcp-firestore"
+	apiGCPStorage         = "gcp-storage"
+	apiGCPPubSub          = "gcp-pubsub"
+	apiGCPFunctions       = "gcp-cloudfunctions"
+	apiGCPScheduler       = "gcp-cloudscheduler"
+	apiAWSEC2             = "aws-ec2"
+	apiAWSIAM             = "aws-iam"
+	apiAWSSTS             = "aws-sts"
+	apiAWSOrganizations   = "aws-organizations"
+	apiAWSS3              = "aws-s3"
+	apiAWSRDS             = "aws-rds"
+	apiAWSLambda          = "aws-lambda"
+	apiAWSEKS             = "aws-eks"
+	apiAWSECS             = "aws-ecs"
+	apiAWSELB             = "aws-elb"
+	apiAWSWAF             = "aws-wafv2"
+	apiAWSRoute53         = "aws-route53"
+	apiAWSACM             = "aws-acm"
+	apiAWSSecretsManager  = "aws-secretsmanager"
+	apiAWSSSM             = "aws-ssm"
+)
+
+// RetryPolicy controls how throttled and transient API errors are retried.
+// Delays grow exponentially from BaseDelay up to MaxDelay, with jitter.
+type RetryPolicy struct {
+	MaxAttempts int           `yaml:"max_attempts"`
+	BaseDelay   time.Duration `yaml:"base_delay"`
+	MaxDelay    time.Duration `yaml:"max_delay"`
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 5, BaseDelay: 500 * time.Millisecond, MaxDelay: 30 * time.Second}
+}
+
+// RateLimit is a client-side token bucket for one API. A zero
+// RequestsPerSecond disables the limit.
+type RateLimit struct {
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+	Burst             int     `yaml:"burst"`
+}
+
+// DefaultRateLimits returns the per-API limits used when none are configured.
+// They sit below the default project and account quotas of each API.
+func DefaultRateLimits() map[string]RateLimit {
+	return map[string]RateLimit{
+		apiGCPCompute:         {RequestsPerSecond: 20, Burst: 20},
+		apiGCPIAM:             {RequestsPerSecond: 10, Burst: 10},
+		apiGCPResourceManager: {RequestsPerSecond: 10, Burst: 10},
+		apiGCPCloudAsset:      {RequestsPerSecond: 5, Burst: 5},
+		apiGCPRun:             {RequestsPerSecond: 10, Burst: 10},
+		apiGCPDNS:             {RequestsPerSecond: 10, Burst: 10},
+		apiGCPContainer:       {RequestsPerSecond: 5, Burst: 5},
+		apiGCPSQLAdmin:        {RequestsPerSecond: 5, Burst: 5},
+		apiGCPRedis:           {RequestsPerSecond: 5, Burst: 5},
+		apiGCPSpanner:         {RequestsPerSecond: 10, Burst: 10},
+		apiGCPFirestore:       {RequestsPerSecond: 5, Burst: 5},
+		apiGCPStorage:         {RequestsPerSecond: 10, Burst: 10},
+		apiGCPPubSub:          {RequestsPerSecond: 10, Burst: 10},
+		apiGCPFunctions:       {RequestsPerSecond: 5, Burst: 5},
+		apiGCPScheduler:       {RequestsPerSecond: 5, Burst: 5},
+		apiAWSEC2:             {RequestsPerSecond: 20, Burst: 20},
+		apiAWSIAM:             {RequestsPerSecond: 10, Burst: 5},
+		apiAWSSTS:             {RequestsPerSecond: 10, Burst: 10},
+		apiAWSOrganizations:   {RequestsPerSecond: 2, Burst: 4},
+		apiAWSS3:              {RequestsPerSecond: 20, Burst: 20},
+		apiAWSRDS:             {RequestsPerSecond: 10, Burst: 10},
+		apiAWSLambda:          {RequestsPerSecond: 10, Burst: 10},
+		apiAWSEKS:             {RequestsPerSecond: 10, Burst: 10},
+		apiAWSECS:             {RequestsPerSecond: 20, Burst: 20},
+		apiAWSELB:             {RequestsPerSecond: 10, Burst: 10},
+		apiAWSWAF:             {RequestsPerSecond: 5, Burst: 5},
+		apiAWSRoute53:         {RequestsPerSecond: 5, Burst: 5},
+		apiAWSACM:             {RequestsPerSecond: 10, Burst: 10},
+		apiAWSSecretsManager:  {RequestsPerSecond: 10, Burst: 10},
+		apiAWSSSM:             {RequestsPerSecond: 5, Burst: 5},
+	}
+}
+
+// APIStats counts the calls made to one API during a sync.
+type APIStats struct {
+	API       string `json:"api"`
+	Calls     int    `json:"calls"`
+	Retries   int    `json:"retries"`
+	Throttled int    `json:"throttled"`
+	Failed    int    `json:"failed"`
+	WaitedMS  int64  `json:"waited_ms"`
+}
+
+// Throttle rate limits and retries cloud API calls. Every fetcher in this
+// package routes its calls through the Throttle installed with SetThrottle.
+type Throttle struct {
+	policy RetryPolicy
+	limits map[string]RateLimit
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	stats    map[string]*APIStats
+
+	// sleep waits between attempts; tests replace it to avoid real delays.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewThrottle creates a Throttle. APIs missing from limits are not rate limited.
+func NewThrottle(policy RetryPolicy, limits map[string]RateLimit) *Throttle {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &Throttle{
+		policy:   policy,
+		limits:   limits,
+		limiters: make(map[string]*rate.Limiter),
+		stats:    make(map[string]*APIStats),
+		sleep:    sleepContext,
+	}
+}
+
+// Do runs call, waiting for the API's rate limit first and retrying
+// throttled or transient errors with exponential backoff.
+func (t *Throttle) Do(ctx context.Context, api string, call func() error) error {
+	limiter := t.limiter(api)
+	for attempt := 1; ; attempt++ {
+		if limiter != nil {
+			start := time.Now()
+			if err := limiter.Wait(ctx); err != nil {
+				return err
+			}
+			t.record(api, func(s *APIStats) { s.WaitedMS += time.Since(start).Milliseconds() })
+		}
+
+		err := call()
+		retry := attempt < t.policy.MaxAttempts && IsRetryable(err) && ctx.Err() == nil
+		t.record(api, func(s *APIStats) {
+			s.Calls++
+			if IsThrottled(err) {
+				s.Throttled++
+			}
+			if retry {
+				s.Retries++
+			} else if err != nil {
+				s.Failed++
+			}
+		})
+		if !retry {
+			return err
+		}
+
+		delay := t.backoff(attempt)
+		if err := t.sleep(ctx, delay); err != nil {
+			return err
+		}
+		t.record(api, func(s *APIStats) { s.WaitedMS += delay.Milliseconds() })
+	}
+}
+
+// Stats returns the counters of every API called so far, sorted by name.
+func (t *Throttle) Stats() []APIStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := make([]APIStats, 0, len(t.stats))
+	for _, s := range t.stats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].API < stats[j].API })
+	return stats
+}
+
+// backoff returns the delay before the next attempt: exponential growth
+// capped at MaxDelay, with the upper half randomised so that parallel
+// collectors do not retry in lockstep.
+func (t *Throttle) backoff(attempt int) time.Duration {
+	delay := t.policy.BaseDelay
+	for i := 1; i < attempt && delay < t.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if t.policy.MaxDelay > 0 && delay > t.policy.MaxDelay {
+		delay = t.policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+func (t *Throttle) limiter(api string) *rate.Limiter {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if l, ok := t.limiters[api]; ok {
+		return l
+	}
+	var l *rate.Limiter
+	if limit, ok := t.limits[api]; ok && limit.RequestsPerSecond > 0 {
+		burst := limit.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		l = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)
+	}
+	t.limiters[api] = l
+	return l
+}
+
+func (t *Throttle) record(api string, update func(*APIStats)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.stats[api]
+	if !ok {
+		s = &APIStats{API: api}
+		t.stats[api] = s
+	}
+	update(s)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+var (
+	throttleMu     sync.RWMutex
+	activeThrottle = NewThrottle(DefaultRetryPolicy(), DefaultRateLimits())
+)
+
+// SetThrottle replaces the Throttle used by the fetchers.
+func SetThrottle(t *Throttle) {
+	throttleMu.Lock()
+	defer throttleMu.Unlock()
+	activeThrottle = t
+}
+
+// CurrentThrottle returns the Throttle used by the fetchers.
+func CurrentThrottle() *Throttle {
+	throttleMu.RLock()
+	defer throttleMu.RUnlock()
+	return activeThrottle
+}
+
+// callAPI runs a single API call through the active Throttle.
+func callAPI[T any](ctx context.Context, api string, call func() (T, error)) (T, error) {
+	var result T
+	err := CurrentThrottle().Do(ctx, api, func() error {
+		var err error
+		result, err = call()
+		return err
+	})
+	return result, err
+}
+
+// gcpDo runs the Do method of a Google API call through the active Throttle,
+// e.g. gcpDo(ctx, apiGCPCompute, svc.Networks.List(project).Context(ctx).Do).
+func gcpDo[T any, O any](ctx context.Context, api string, do func(...O) (T, error)) (T, error) {
+	return callAPI(ctx, api, func() (T, error) { return do() })
+}
+
+// awsPage fetches the next page of an AWS paginator through the active
+// Throttle. Paginators only advance on success, so a failed page is retried.
+func awsPage[T any, O any](ctx context.Context, api string, nextPage func(context.Context, ...O) (T, error)) (T, error) {
+	return callAPI(ctx, api, func() (T, error) { return nextPage(ctx) })
+}
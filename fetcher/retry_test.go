@@ -0,0 +1,178 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"google.golang.org/api/googleapi"
+)
+
+// newTestThrottle returns a Throttle that records its backoff delays instead of sleeping.
+func newTestThrottle(maxAttempts int, limits map[string]RateLimit) (*Throttle, *[]time.Duration) {
+	var delays []time.Duration
+	th := NewThrottle(RetryPolicy{MaxAttempts: maxAttempts, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}, limits)
+	th.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	return th, &delays
+}
+
+func TestThrottleDo(t *testing.T) {
+	throttled := &googleapi.Error{Code: 429, Message: "rateLimitExceeded"}
+
+	tests := []struct {
+		name        string
+		errs        []error // returned by successive attempts; nil once exhausted
+		maxAttempts int
+		wantErr     bool
+		wantStats   APIStats
+	}{
+		{
+			name:        "success",
+			maxAttempts: 3,
+			wantStats:   APIStats{Calls: 1},
+		},
+		{
+			name:        "retries throttling then succeeds",
+			errs:        []error{throttled, &smithy.GenericAPIError{Code: "Throttling"}},
+			maxAttempts: 3,
+			wantStats:   APIStats{Calls: 3, Retries: 2, Throttled: 2},
+		},
+		{
+			name:        "retries server errors",
+			errs:        []error{&googleapi.Error{Code: 503}},
+			maxAttempts: 3,
+			wantStats:   APIStats{Calls: 2, Retries: 1},
+		},
+		{
+			name:        "gives up after max attempts",
+			errs:        []error{throttled, throttled, throttled, throttled},
+			maxAttempts: 3,
+			wantErr:     true,
+			wantStats:   APIStats{Calls: 3, Retries: 2, Throttled: 3, Failed: 1},
+		},
+		{
+			name:        "does not retry permission errors",
+			errs:        []error{&googleapi.Error{Code: 403, Message: "denied"}},
+			maxAttempts: 3,
+			wantErr:     true,
+			wantStats:   APIStats{Calls: 1, Failed: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th, delays := newTestThrottle(tt.maxAttempts, nil)
+			attempt := 0
+			err := th.Do(context.Background(), "test-api", func() error {
+				defer func() { attempt++ }()
+				if attempt < len(tt.errs) {
+					return tt.errs[attempt]
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			stats := th.Stats()
+			if len(stats) != 1 {
+				t.Fatalf("Expected stats for one API, got %v", stats)
+			}
+			got := stats[0]
+			tt.wantStats.API = "test-api"
+			got.WaitedMS, tt.wantStats.WaitedMS = 0, 0
+			if got != tt.wantStats {
+				t.Errorf("Expected stats %+v, got %+v", tt.wantStats, got)
+			}
+			if len(*delays) != tt.wantStats.Retries {
+				t.Errorf("Expected %d backoff sleeps, got %d", tt.wantStats.Retries, len(*delays))
+			}
+		})
+	}
+}
+
+func TestThrottleBackoff(t *testing.T) {
+	th := NewThrottle(RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}, nil)
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{4, 400 * time.Millisecond, 800 * time.Millisecond},
+		{8, 500 * time.Millisecond, time.Second}, // capped at MaxDelay
+	}
+	for _, tt := range tests {
+		for i := 0; i < 50; i++ {
+			if d := th.backoff(tt.attempt); d < tt.min || d > tt.max {
+				t.Fatalf("backoff(%d) = %s, expected between %s and %s", tt.attempt, d, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestThrottleRateLimit(t *testing.T) {
+	th, _ := newTestThrottle(1, map[string]RateLimit{"limited": {RequestsPerSecond: 1, Burst: 1}})
+
+	// The burst allows the first call; the second must wait for a token,
+	// which cannot arrive before the context deadline.
+	if err := th.Do(context.Background(), "limited", func() error { return nil }); err != nil {
+		t.Fatalf("First call failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	called := false
+	if err := th.Do(ctx, "limited", func() error { called = true; return nil }); err == nil {
+		t.Error("Expected the rate limiter to refuse a call it cannot serve before the deadline")
+	}
+	if called {
+		t.Error("Call ran despite the rate limit")
+	}
+
+	// APIs without a limit are not throttled.
+	for i := 0; i < 100; i++ {
+		if err := th.Do(ctx, "unlimited", func() error { return nil }); err != nil {
+			t.Fatalf("Unlimited call failed: %v", err)
+		}
+	}
+}
+
+func TestThrottleStopsOnCancel(t *testing.T) {
+	th, _ := newTestThrottle(5, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := th.Do(ctx, "test-api", func() error {
+		calls++
+		cancel()
+		return &googleapi.Error{Code: 429}
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("Expected a single attempt after cancellation, got %d (err %v)", calls, err)
+	}
+}
+
+func TestCallAPIUsesCurrentThrottle(t *testing.T) {
+	th, _ := newTestThrottle(3, nil)
+	previous := CurrentThrottle()
+	SetThrottle(th)
+	defer SetThrottle(previous)
+
+	attempt := 0
+	got, err := callAPI(context.Background(), apiGCPCompute, func() (string, error) {
+		attempt++
+		if attempt == 1 {
+			return "", &googleapi.Error{Code: 429}
+		}
+		return "ok", nil
+	})
+	if err != nil || got != "ok" {
+		t.Fatalf("callAPI() = %q, %v", got, err)
+	}
+	if stats := th.Stats(); len(stats) != 1 || stats[0].API != apiGCPCompute || stats[0].Retries != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
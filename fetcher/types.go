@@ -1,6 +1,8 @@
 // fetcher/types.go
 package fetcher
 
+import "sort"
+
 // StandardizedResource is our common format for any cloud resource.
 type StandardizedResource struct {
 	Provider   string            `json:"provider"`
@@ -11,9 +13,36 @@ type StandardizedResource struct {
 	Attributes map[string]string `json:"attributes"`
 }
 
-// LoadBalancerFlow represents the entire traceable path of a GCP Load Balancer.
+// SortResources orders resources by provider, project, service, region and ID
+// so that repeated syncs of an unchanged estate produce identical cache files.
+func SortResources(resources []StandardizedResource) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		a, b := resources[i], resources[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if pa, pb := a.Attributes["project_id"], b.Attributes["project_id"]; pa != pb {
+			return pa < pb
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return a.Name < b.Name
+	})
+}
+
+// LoadBalancerFlow represents the entire traceable path of a load balancer:
+// a GCP forwarding rule or an AWS listener. For AWS, ProjectID holds the
+// account ID and CloudArmor the WAF web ACL.
 type LoadBalancerFlow struct {
 	Name         string           `json:"name"`
+	Provider     string           `json:"provider"`
 	ProjectID    string           `json:"projectId"`
 	Frontend     FrontendConfig   `json:"frontend"`
 	RoutingRules []RoutingRule    `json:"routingRules"`
@@ -24,6 +53,7 @@ type LoadBalancerFlow struct {
 // FrontendConfig holds details about the user-facing side of the LB.
 type FrontendConfig struct {
 	IPAddress           string   `json:"ipAddress"`
+	DNSName             string   `json:"dnsName,omitempty"`
 	PortRange           string   `json:"portRange"`
 	Protocol            string   `json:"protocol"`
 	Certificates        []string `json:"certificates"`
@@ -31,24 +61,31 @@ type FrontendConfig struct {
 	LoadBalancingScheme string   `json:"loadBalancingScheme"`
 }
 
-// RoutingRule holds details from the URL Map.
+// RoutingRule holds details from the URL Map, or from a listener rule of an
+// AWS load balancer along with the backend it forwards to.
 type RoutingRule struct {
-	Hosts       []string `json:"hosts"`
-	PathMatcher string   `json:"pathMatcher"`
+	Hosts       []string       `json:"hosts"`
+	PathMatcher string         `json:"pathMatcher"`
+	Paths       []string       `json:"paths,omitempty"`
+	Priority    string         `json:"priority,omitempty"`
+	Action      string         `json:"action,omitempty"`
+	Backend     *BackendConfig `json:"backend,omitempty"`
 }
 
 // BackendConfig holds details about the final destination of traffic.
 type BackendConfig struct {
-	Name        string `json:"name"`
-	Type        string `json:"type"` // e.g., "Cloud Run"
-	ServiceName string `json:"serviceName"`
-	Region      string `json:"region"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type"` // e.g., "Cloud Run"
+	ServiceName string   `json:"serviceName"`
+	Region      string   `json:"region"`
+	Targets     []string `json:"targets,omitempty"`
 }
 
 // CloudArmorPolicy holds details about the attached security policy.
 type CloudArmorPolicy struct {
-	Name  string           `json:"name"`
-	Rules []CloudArmorRule `json:"rules"`
+	Name          string           `json:"name"`
+	DefaultAction string           `json:"defaultAction,omitempty"`
+	Rules         []CloudArmorRule `json:"rules"`
 }
 
 // CloudArmorRule holds details for a single rule within a policy.
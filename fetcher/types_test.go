@@ -291,3 +291,24 @@ func TestStandardizedResourceProviderValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestSortResources(t *testing.T) {
+	resources := []StandardizedResource{
+		{Provider: "gcp", Service: "vpc", ID: "vpc-b", Attributes: map[string]string{"project_id": "p1"}},
+		{Provider: "aws", Service: "iam", ID: "role-1"},
+		{Provider: "gcp", Service: "subnet", Region: "us-east1", ID: "subnet-1", Attributes: map[string]string{"project_id": "p1"}},
+		{Provider: "gcp", Service: "vpc", ID: "vpc-a", Attributes: map[string]string{"project_id": "p1"}},
+		{Provider: "aws", Service: "ec2", Region: "us-west-2", ID: "i-2"},
+		{Provider: "aws", Service: "ec2", Region: "us-east-1", ID: "i-3"},
+		{Provider: "gcp", Service: "project", ID: "p1"},
+	}
+
+	SortResources(resources)
+
+	expected := []string{"i-3", "i-2", "role-1", "p1", "subnet-1", "vpc-a", "vpc-b"}
+	for i, id := range expected {
+		if resources[i].ID != id {
+			t.Errorf("Position %d: expected %s, got %s", i, id, resources[i].ID)
+		}
+	}
+}
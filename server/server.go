@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"regexp"
 	"strings"
 
 	"github.com/rahulwagh/infrakit/cache"
@@ -16,6 +17,31 @@ import (
 //go:embed index.html
 var content embed.FS
 
+// iamAction matches an IAM action such as "s3:DeleteBucket" or "ec2:Describe*".
+var iamAction = regexp.MustCompile(`^[a-zA-Z0-9-]+:[a-zA-Z*][a-zA-Z0-9*?]*$`)
+
+// searchableServices are the services whose resources handleSearch returns.
+var searchableServices = map[string]bool{
+	"project": true, "aws-account": true, "aws-ou": true,
+	"ec2": true, "gce": true, "lambda": true, "cloudfunction": true,
+	"s3": true, "gcs": true,
+	"rds": true, "cloudsql": true, "redis": true, "spanner": true, "firestore": true,
+	"pubsubtopic": true, "pubsubsubscription": true, "schedulerjob": true,
+	"iam": true, "iamuser": true, "iamgroup": true, "iampolicy": true,
+	"ekscluster": true, "gkecluster": true, "ecscluster": true, "ecsservice": true,
+	"elb": true, "dnsrecord": true, "acmcertificate": true,
+	"secret": true, "ssmparameter": true,
+}
+
+// searchableAttributes are matched against the query besides a resource's
+// name and ID, so a search for an IP, hostname or account finds what holds it.
+var searchableAttributes = []string{
+	"account_id", "account_alias",
+	"private_ip", "public_ip", "private_dns", "endpoint", "dns_name",
+	"values", "alias_target",
+	"trusted_accounts", "trusted_principals", "domains",
+}
+
 // --- handleSearch function ---
 func handleSearch(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
@@ -32,9 +58,25 @@ func handleSearch(w http.ResponseWriter, r *http.Request) {
 	var results []fetcher.StandardizedResource
 	lowerQuery := strings.ToLower(query)
 	for _, res := range resources {
-		if res.Service == "project" || res.Service == "ec2" {
-			searchText := strings.ToLower(res.Name + " " + res.ID)
-			if strings.Contains(searchText, lowerQuery) {
+		if !searchableServices[res.Service] {
+			continue
+		}
+		fields := []string{res.Name, res.ID}
+		for _, key := range searchableAttributes {
+			fields = append(fields, res.Attributes[key])
+		}
+		if strings.Contains(strings.ToLower(strings.Join(fields, " ")), lowerQuery) {
+			results = append(results, res)
+		}
+	}
+	// A query such as "s3:DeleteBucket" also finds the IAM principals whose policies allow that action.
+	if iamAction.MatchString(query) && !strings.HasPrefix(lowerQuery, "arn:") {
+		found := make(map[string]bool)
+		for _, res := range results {
+			found[res.Service+"/"+res.ID] = true
+		}
+		for _, res := range fetcher.IAMPrincipalsAllowing(resources, query) {
+			if !found[res.Service+"/"+res.ID] {
 				results = append(results, res)
 			}
 		}
@@ -57,7 +99,8 @@ func handleGetResources(w http.ResponseWriter, r *http.Request) {
 	}
 	groupedChildren := make(map[string][]fetcher.StandardizedResource)
 	for _, res := range allResources {
-		if projID, ok := res.Attributes["project_id"]; ok && projID == parentProjectID {
+		// Children of a GCP project carry its project_id, those of an AWS account its account_id.
+		if res.Attributes["project_id"] == parentProjectID || res.Attributes["account_id"] == parentProjectID {
 			groupedChildren[res.Service] = append(groupedChildren[res.Service], res)
 		}
 	}
@@ -106,6 +149,7 @@ func handleGetLBFlows(w http.ResponseWriter, r *http.Request) {
 		if res.Service == "forwardingrule" && res.Attributes["project_id"] == projectID {
 			flow := fetcher.LoadBalancerFlow{
 				Name:      res.Name,
+				Provider:  "gcp",
 				ProjectID: projectID,
 				Frontend: fetcher.FrontendConfig{
 					IPAddress:           res.Attributes["ip_address"],
@@ -141,10 +185,32 @@ func handleGetLBFlows(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
+	// The project may also be an AWS account, whose flows are traced from its listeners.
+	flows = append(flows, fetcher.AWSLoadBalancerFlows(allResources, projectID)...)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(flows)
 }
 
+// --- handleResolveDNS function ---
+func handleResolveDNS(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "query parameter 'name' is required", http.StatusBadRequest)
+		return
+	}
+	allResources, err := cache.LoadResources()
+	if err != nil {
+		http.Error(w, "Failed to load cache", http.StatusInternalServerError)
+		return
+	}
+	links := fetcher.ResolveDNSName(allResources, name)
+	if links == nil {
+		links = []fetcher.DNSLink{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(links)
+}
+
 // --- handleGetIAMTemplate function ---
 func handleGetIAMTemplate(w http.ResponseWriter, r *http.Request) {
 	templateBytes, err := content.ReadFile("iam_tab.html")
@@ -171,6 +237,7 @@ func StartServer() {
 	http.HandleFunc("/api/search", handleSearch)
 	http.HandleFunc("/api/resources", handleGetResources)
 	http.HandleFunc("/api/lb-flows", handleGetLBFlows)
+	http.HandleFunc("/api/dns", handleResolveDNS)
 	http.HandleFunc("/templates/iam", handleGetIAMTemplate) // Still needed for the IAM tab JS
 
 	log.Println("Starting server on http://localhost:8080")
@@ -0,0 +1,258 @@
+// syncer/engine.go
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/rahulwagh/infrakit/fetcher"
+)
+
+// DefaultConcurrency is the number of fetch tasks run in parallel when the
+// Engine is not given an explicit limit.
+const DefaultConcurrency = 10
+
+// cancelGrace is how long a task whose context ended still waits for its
+// fetcher to return, so a fetcher that honours cancellation can hand back
+// what it collected so far.
+const cancelGrace = 250 * time.Millisecond
+
+// Engine fans fetcher work out across a bounded pool of workers.
+type Engine struct {
+	// Fetchers is the set of collectors to run, usually fetcher.Fetchers().
+	Fetchers []fetcher.Fetcher
+	// Concurrency caps the number of fetch tasks in flight at once.
+	Concurrency int
+	// ServiceTimeout bounds a single fetch task. Zero means no limit.
+	ServiceTimeout time.Duration
+	// Throttle, when set, is installed for the fetchers during Run and its
+	// API counters are copied into the report.
+	Throttle *fetcher.Throttle
+}
+
+// Task is a single invocation of a Fetcher within a Scope.
+type Task struct {
+	Fetcher fetcher.Fetcher
+	Scope   fetcher.Scope
+}
+
+// TaskError records a Task that returned an error.
+type TaskError struct {
+	Fetcher  string
+	Provider string
+	Scope    fetcher.Scope
+	Err      error
+}
+
+func (e TaskError) Error() string {
+	if e.Scope.Key() != "" {
+		return fmt.Sprintf("%s (%s): %v", e.Fetcher, e.Scope, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Fetcher, e.Err)
+}
+
+// Result is the combined output of an Engine run.
+type Result struct {
+	// Resources is sorted with fetcher.SortResources.
+	Resources []fetcher.StandardizedResource
+	// ProviderErrors are failures of provider-scoped fetchers, which leave a
+	// provider's inventory incomplete.
+	ProviderErrors []TaskError
+	// ProjectErrors are failures of project-scoped fetchers.
+	ProjectErrors []TaskError
+	// Report records the outcome of every service in every scope.
+	Report fetcher.SyncReport
+}
+
+// Run syncs the given providers. Provider-scoped fetchers run first; every
+// GCP project and AWS account they discover is then fanned out to the
+// provider's project-scoped fetchers. A non-empty scope.ProjectID or
+// scope.Account.ID limits the run to that project or account.
+// Failed fetchers keep whatever partial results they returned. If ctx is
+// cancelled, tasks that have not started are recorded as errors and Run
+// returns whatever was collected so far.
+func (e *Engine) Run(ctx context.Context, providers []string, scope fetcher.Scope) *Result {
+	result := &Result{Report: fetcher.SyncReport{StartedAt: time.Now().UTC(), Providers: providers, Scope: scope.Key()}}
+	if e.Throttle != nil {
+		previous := fetcher.CurrentThrottle()
+		fetcher.SetThrottle(e.Throttle)
+		defer fetcher.SetThrottle(previous)
+	}
+
+	var providerTasks []Task
+	for _, f := range e.fetchersFor(providers, fetcher.ScopeProvider) {
+		providerTasks = append(providerTasks, Task{Fetcher: f, Scope: scope})
+	}
+	log.Printf("Running %d provider collectors with concurrency %d...", len(providerTasks), e.concurrency())
+	discovered := e.runTasks(ctx, providerTasks, result, &result.ProviderErrors)
+
+	var projectTasks []Task
+	seen := make(map[string]bool)
+	for _, res := range discovered {
+		child, ok := fetcher.ChildScope(scope, res)
+		if !ok {
+			continue
+		}
+		key := res.Provider + "/" + res.ID
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		for _, f := range e.fetchersFor([]string{res.Provider}, fetcher.ScopeProject) {
+			projectTasks = append(projectTasks, Task{Fetcher: f, Scope: child})
+		}
+	}
+	if len(projectTasks) > 0 {
+		log.Printf("Running %d project collectors across %d projects and accounts...", len(projectTasks), len(seen))
+		e.runTasks(ctx, projectTasks, result, &result.ProjectErrors)
+	}
+
+	fetcher.LinkCloudRunSubnets(result.Resources)
+	fetcher.LinkEventTargets(result.Resources)
+	fetcher.SortResources(result.Resources)
+	sortStatuses(result.Report.Services)
+	result.Report.FinishedAt = time.Now().UTC()
+	if e.Throttle != nil {
+		result.Report.APICalls = e.Throttle.Stats()
+	}
+	if ctx.Err() != nil {
+		result.Report.Aborted = ctx.Err().Error()
+	}
+	return result
+}
+
+// runTasks executes tasks on the worker pool, appending their resources and
+// service statuses to result and their failures to errs. It returns the
+// resources fetched by this batch alone.
+func (e *Engine) runTasks(ctx context.Context, tasks []Task, result *Result, errs *[]TaskError) []fetcher.StandardizedResource {
+	type taskOutput struct {
+		resources []fetcher.StandardizedResource
+		err       error
+	}
+	outputs := make([]taskOutput, len(tasks))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < e.concurrency() && w < len(tasks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				res, err := e.runTask(ctx, tasks[i])
+				outputs[i] = taskOutput{resources: res, err: err}
+			}
+		}()
+	}
+dispatch:
+	for i := range tasks {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			for j := i; j < len(tasks); j++ {
+				outputs[j].err = fmt.Errorf("not started: %w", ctx.Err())
+			}
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Outputs are indexed by task, so collection order never depends on
+	// which worker finished first.
+	var batch []fetcher.StandardizedResource
+	for i, out := range outputs {
+		result.Report.Services = append(result.Report.Services, serviceStatuses(tasks[i], out.resources, out.err)...)
+		if out.err != nil {
+			taskErr := TaskError{Fetcher: tasks[i].Fetcher.Name(), Provider: tasks[i].Fetcher.Provider(), Scope: tasks[i].Scope, Err: out.err}
+			log.Printf("Warning: %v", taskErr)
+			*errs = append(*errs, taskErr)
+		}
+		batch = append(batch, out.resources...)
+	}
+	result.Resources = append(result.Resources, batch...)
+	return batch
+}
+
+// runTask invokes a single fetcher, giving up on it once ServiceTimeout
+// elapses or ctx is cancelled. Whatever the fetcher returns by then, or
+// within cancelGrace after, is kept along with the context error.
+func (e *Engine) runTask(ctx context.Context, task Task) ([]fetcher.StandardizedResource, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("not started: %w", err)
+	}
+	taskCtx := ctx
+	if e.ServiceTimeout > 0 {
+		var cancel context.CancelFunc
+		taskCtx, cancel = context.WithTimeout(ctx, e.ServiceTimeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		resources []fetcher.StandardizedResource
+		err       error
+	}
+	// Buffered so an abandoned fetcher can still deliver its result and exit.
+	done := make(chan outcome, 1)
+	go func() {
+		res, err := task.Fetcher.Fetch(taskCtx, task.Scope)
+		done <- outcome{resources: res, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		if out.err != nil && taskCtx.Err() != nil {
+			return out.resources, e.contextError(ctx, taskCtx)
+		}
+		return out.resources, out.err
+	case <-taskCtx.Done():
+		grace := time.NewTimer(cancelGrace)
+		defer grace.Stop()
+		select {
+		case out := <-done:
+			return out.resources, e.contextError(ctx, taskCtx)
+		case <-grace.C:
+			// The fetcher ignored cancellation or is stuck in a call; stop waiting for it.
+			return nil, e.contextError(ctx, taskCtx)
+		}
+	}
+}
+
+// contextError explains why taskCtx ended: the whole sync was cancelled, or
+// just this task ran past ServiceTimeout.
+func (e *Engine) contextError(ctx, taskCtx context.Context) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("sync cancelled: %w", ctx.Err())
+	}
+	if errors.Is(taskCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", e.ServiceTimeout, taskCtx.Err())
+	}
+	return taskCtx.Err()
+}
+
+// fetchersFor returns the Engine's fetchers matching any of providers and kind.
+func (e *Engine) fetchersFor(providers []string, kind fetcher.ScopeKind) []fetcher.Fetcher {
+	var fetchers []fetcher.Fetcher
+	for _, f := range e.Fetchers {
+		if fetcher.ScopeKindOf(f) != kind {
+			continue
+		}
+		for _, p := range providers {
+			if f.Provider() == p {
+				fetchers = append(fetchers, f)
+				break
+			}
+		}
+	}
+	return fetchers
+}
+
+func (e *Engine) concurrency() int {
+	if e.Concurrency < 1 {
+		return DefaultConcurrency
+	}
+	return e.Concurrency
+}
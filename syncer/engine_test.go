@@ -0,0 +1,225 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rahulwagh/infrakit/fetcher"
+)
+
+// projectLister returns a provider-scoped fetcher that discovers the given projects.
+func projectLister(projects ...string) fetcher.Fetcher {
+	return fetcher.NewFetcher("test-projects", "test", fetcher.ScopeProvider, []string{"project"},
+		func(ctx context.Context, scope fetcher.Scope) ([]fetcher.StandardizedResource, error) {
+			if scope.ProjectID != "" {
+				projects = []string{scope.ProjectID}
+			}
+			var resources []fetcher.StandardizedResource
+			// Emit in reverse so the test can check the final sort.
+			for i := len(projects) - 1; i >= 0; i-- {
+				resources = append(resources, fetcher.StandardizedResource{Provider: "test", Service: "project", ID: projects[i], Name: projects[i]})
+			}
+			return resources, nil
+		})
+}
+
+// widgetFetcher returns a project-scoped fetcher that emits one widget per
+// project and records how many calls were in flight at once.
+func widgetFetcher(inFlight, maxInFlight *int32) fetcher.Fetcher {
+	return fetcher.NewFetcher("test-widgets", "test", fetcher.ScopeProject, []string{"widget"},
+		func(ctx context.Context, scope fetcher.Scope) ([]fetcher.StandardizedResource, error) {
+			n := atomic.AddInt32(inFlight, 1)
+			defer atomic.AddInt32(inFlight, -1)
+			for {
+				max := atomic.LoadInt32(maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt32(maxInFlight, max, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return []fetcher.StandardizedResource{{
+				Provider:   "test",
+				Service:    "widget",
+				ID:         "widget-" + scope.ProjectID,
+				Attributes: map[string]string{"project_id": scope.ProjectID},
+			}}, nil
+		})
+}
+
+func TestEngineRunFansOutPerProject(t *testing.T) {
+	var inFlight, maxInFlight int32
+	engine := &Engine{
+		Fetchers:    []fetcher.Fetcher{projectLister("p1", "p2", "p3", "p4", "p5", "p6"), widgetFetcher(&inFlight, &maxInFlight)},
+		Concurrency: 2,
+	}
+
+	result := engine.Run(context.Background(), []string{"test"}, fetcher.Scope{})
+
+	if len(result.ProviderErrors) != 0 || len(result.ProjectErrors) != 0 {
+		t.Fatalf("Unexpected errors: %v %v", result.ProviderErrors, result.ProjectErrors)
+	}
+	// 6 projects + 6 widgets
+	if len(result.Resources) != 12 {
+		t.Fatalf("Expected 12 resources, got %d", len(result.Resources))
+	}
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 concurrent fetches, saw %d", maxInFlight)
+	}
+}
+
+func TestEngineRunIsDeterministic(t *testing.T) {
+	var inFlight, maxInFlight int32
+	engine := &Engine{
+		Fetchers:    []fetcher.Fetcher{projectLister("b", "a", "c"), widgetFetcher(&inFlight, &maxInFlight)},
+		Concurrency: 4,
+	}
+
+	first := engine.Run(context.Background(), []string{"test"}, fetcher.Scope{})
+	second := engine.Run(context.Background(), []string{"test"}, fetcher.Scope{})
+
+	if len(first.Resources) != len(second.Resources) {
+		t.Fatalf("Runs returned different counts: %d vs %d", len(first.Resources), len(second.Resources))
+	}
+	for i := range first.Resources {
+		if first.Resources[i].ID != second.Resources[i].ID || first.Resources[i].Service != second.Resources[i].Service {
+			t.Errorf("Resource %d differs between runs: %s/%s vs %s/%s", i,
+				first.Resources[i].Service, first.Resources[i].ID, second.Resources[i].Service, second.Resources[i].ID)
+		}
+	}
+	// Projects have no project_id attribute, so they sort before the widgets.
+	if first.Resources[0].ID != "a" || first.Resources[1].ID != "b" || first.Resources[2].ID != "c" {
+		t.Errorf("Projects not sorted: %s, %s, %s", first.Resources[0].ID, first.Resources[1].ID, first.Resources[2].ID)
+	}
+}
+
+func TestEngineRunCollectsErrors(t *testing.T) {
+	failing := fetcher.NewFetcher("test-broken", "test", fetcher.ScopeProject, []string{"gadget"},
+		func(ctx context.Context, scope fetcher.Scope) ([]fetcher.StandardizedResource, error) {
+			if scope.ProjectID == "p2" {
+				return nil, errors.New("permission denied")
+			}
+			return []fetcher.StandardizedResource{{Provider: "test", Service: "gadget", ID: "g-" + scope.ProjectID}}, nil
+		})
+	engine := &Engine{Fetchers: []fetcher.Fetcher{projectLister("p1", "p2"), failing}, Concurrency: 3}
+
+	result := engine.Run(context.Background(), []string{"test"}, fetcher.Scope{})
+
+	if len(result.ProjectErrors) != 1 {
+		t.Fatalf("Expected 1 project error, got %d", len(result.ProjectErrors))
+	}
+	if result.ProjectErrors[0].Scope.ProjectID != "p2" || result.ProjectErrors[0].Fetcher != "test-broken" {
+		t.Errorf("Unexpected error record: %+v", result.ProjectErrors[0])
+	}
+	// 2 projects + the gadget from p1
+	if len(result.Resources) != 3 {
+		t.Errorf("Expected 3 resources, got %d", len(result.Resources))
+	}
+}
+
+func TestEngineRunSingleProject(t *testing.T) {
+	var inFlight, maxInFlight int32
+	engine := &Engine{Fetchers: []fetcher.Fetcher{projectLister("p1", "p2"), widgetFetcher(&inFlight, &maxInFlight)}}
+
+	result := engine.Run(context.Background(), []string{"test"}, fetcher.Scope{ProjectID: "p2"})
+
+	if len(result.Resources) != 2 {
+		t.Fatalf("Expected 2 resources, got %d", len(result.Resources))
+	}
+	for _, res := range result.Resources {
+		if res.ID != "p2" && res.ID != "widget-p2" {
+			t.Errorf("Unexpected resource outside the scoped project: %s", res.ID)
+		}
+	}
+}
+
+func TestEngineRunSkipsOtherProviders(t *testing.T) {
+	other := fetcher.NewFetcher("other-thing", "other", fetcher.ScopeProvider, []string{"thing"},
+		func(ctx context.Context, scope fetcher.Scope) ([]fetcher.StandardizedResource, error) {
+			t.Error("Fetcher for an unselected provider was run")
+			return nil, nil
+		})
+	engine := &Engine{Fetchers: []fetcher.Fetcher{projectLister("p1"), other}}
+
+	engine.Run(context.Background(), []string{"test"}, fetcher.Scope{})
+}
+
+func TestEngineRunAbandonsSlowFetcher(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	stuck := fetcher.NewFetcher("test-stuck", "test", fetcher.ScopeProject, []string{"gizmo"},
+		func(ctx context.Context, scope fetcher.Scope) ([]fetcher.StandardizedResource, error) {
+			// Simulates an API call that ignores cancellation.
+			<-release
+			return nil, nil
+		})
+	engine := &Engine{Fetchers: []fetcher.Fetcher{projectLister("p1"), stuck}, ServiceTimeout: 20 * time.Millisecond}
+
+	done := make(chan *Result)
+	go func() { done <- engine.Run(context.Background(), []string{"test"}, fetcher.Scope{}) }()
+
+	select {
+	case result := <-done:
+		if len(result.ProjectErrors) != 1 {
+			t.Fatalf("Expected the stuck fetcher to be reported, got %v", result.ProjectErrors)
+		}
+		if !errors.Is(result.ProjectErrors[0].Err, context.DeadlineExceeded) {
+			t.Errorf("Expected a deadline error, got %v", result.ProjectErrors[0].Err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Engine did not abandon the stuck fetcher")
+	}
+}
+
+func TestEngineRunKeepsPartialResultsOnTimeout(t *testing.T) {
+	partial := fetcher.NewFetcher("test-partial", "test", fetcher.ScopeProject, []string{"gizmo"},
+		func(ctx context.Context, scope fetcher.Scope) ([]fetcher.StandardizedResource, error) {
+			resources := []fetcher.StandardizedResource{{Provider: "test", Service: "gizmo", ID: "gizmo-1", Attributes: map[string]string{"project_id": scope.ProjectID}}}
+			// The second page never arrives before the deadline.
+			<-ctx.Done()
+			return resources, ctx.Err()
+		})
+	engine := &Engine{Fetchers: []fetcher.Fetcher{projectLister("p1"), partial}, ServiceTimeout: 20 * time.Millisecond}
+
+	result := engine.Run(context.Background(), []string{"test"}, fetcher.Scope{})
+
+	if len(result.ProjectErrors) != 1 || !errors.Is(result.ProjectErrors[0].Err, context.DeadlineExceeded) {
+		t.Fatalf("Expected a deadline error, got %v", result.ProjectErrors)
+	}
+	var found bool
+	for _, res := range result.Resources {
+		found = found || res.ID == "gizmo-1"
+	}
+	if !found {
+		t.Errorf("Expected the partial results to be kept, got %v", result.Resources)
+	}
+}
+
+func TestEngineRunStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+	cancelling := fetcher.NewFetcher("test-cancel", "test", fetcher.ScopeProject, []string{"gizmo"},
+		func(ctx context.Context, scope fetcher.Scope) ([]fetcher.StandardizedResource, error) {
+			atomic.AddInt32(&calls, 1)
+			cancel()
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+	engine := &Engine{Fetchers: []fetcher.Fetcher{projectLister("p1", "p2", "p3", "p4"), cancelling}, Concurrency: 1}
+
+	result := engine.Run(ctx, []string{"test"}, fetcher.Scope{})
+
+	if len(result.ProjectErrors) != 4 {
+		t.Fatalf("Expected every project task to be reported, got %d", len(result.ProjectErrors))
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected tasks queued after cancellation not to run, got %d calls", n)
+	}
+	for _, taskErr := range result.ProjectErrors {
+		if !errors.Is(taskErr.Err, context.Canceled) {
+			t.Errorf("Expected a cancellation error, got %v", taskErr.Err)
+		}
+	}
+}
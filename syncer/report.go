@@ -0,0 +1,195 @@
+// syncer/report.go
+package syncer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rahulwagh/infrakit/fetcher"
+)
+
+// serviceStatuses turns the outcome of one task into a status per service
+// the fetcher declares. A fetcher may fail as a whole, or report individual
+// services through fetcher.ServiceError while the rest succeed.
+func serviceStatuses(task Task, resources []fetcher.StandardizedResource, err error) []fetcher.ServiceStatus {
+	counts := make(map[string]int)
+	for _, res := range resources {
+		counts[res.Service]++
+	}
+	failed := fetcher.FailedServices(err)
+
+	var statuses []fetcher.ServiceStatus
+	for _, service := range task.Fetcher.Services() {
+		serviceErr := err
+		if len(failed) > 0 {
+			serviceErr = failed[service]
+		}
+		status := fetcher.ServiceStatus{
+			Provider:  task.Fetcher.Provider(),
+			Scope:     task.Scope.Key(),
+			Level:     fetcher.ScopeKindOf(task.Fetcher).String(),
+			Service:   service,
+			Fetcher:   task.Fetcher.Name(),
+			Status:    fetcher.StatusFor(serviceErr),
+			Resources: counts[service],
+		}
+		if serviceErr != nil {
+			status.Error = serviceErr.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func sortStatuses(statuses []fetcher.ServiceStatus) {
+	sort.SliceStable(statuses, func(i, j int) bool {
+		a, b := statuses[i], statuses[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.Scope != b.Scope {
+			return a.Scope < b.Scope
+		}
+		return a.Service < b.Service
+	})
+}
+
+// FailurePolicy decides when a sync should exit with a non-zero status.
+type FailurePolicy string
+
+const (
+	// FailNever always exits zero once the cache is written.
+	FailNever FailurePolicy = "never"
+	// FailProvider exits non-zero when a provider-wide collector (AWS account
+	// or GCP project discovery) did not succeed.
+	FailProvider FailurePolicy = "provider"
+	// FailFailed exits non-zero when any service failed. Permission skips are tolerated.
+	FailFailed FailurePolicy = "failed"
+	// FailAny exits non-zero when any service failed or was skipped.
+	FailAny FailurePolicy = "any"
+)
+
+// ParseFailurePolicy validates a --fail-on value.
+func ParseFailurePolicy(s string) (FailurePolicy, error) {
+	switch p := FailurePolicy(s); p {
+	case FailNever, FailProvider, FailFailed, FailAny:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid failure policy '%s'. Valid policies are never, provider, failed, any", s)
+}
+
+// Violated reports whether the report breaks the policy.
+func (p FailurePolicy) Violated(report fetcher.SyncReport) bool {
+	if p == FailNever {
+		return false
+	}
+	if report.Aborted != "" {
+		return true
+	}
+	for _, s := range report.Services {
+		switch p {
+		case FailProvider:
+			if s.Level == fetcher.ScopeProvider.String() && s.Status != fetcher.StatusSucceeded {
+				return true
+			}
+		case FailFailed:
+			if s.Status == fetcher.StatusFailed {
+				return true
+			}
+		case FailAny:
+			if s.Status != fetcher.StatusSucceeded {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WriteSummary prints a per-service summary of the report followed by the
+// individual services that did not succeed.
+func WriteSummary(w io.Writer, report fetcher.SyncReport) {
+	type totals struct {
+		provider, service          string
+		succeeded, skipped, failed int
+		resources                  int
+	}
+	var order []string
+	byService := make(map[string]*totals)
+	for _, s := range report.Services {
+		key := s.Provider + "/" + s.Service
+		t, ok := byService[key]
+		if !ok {
+			t = &totals{provider: s.Provider, service: s.Service}
+			byService[key] = t
+			order = append(order, key)
+		}
+		t.resources += s.Resources
+		switch s.Status {
+		case fetcher.StatusSucceeded:
+			t.succeeded++
+		case fetcher.StatusSkipped:
+			t.skipped++
+		default:
+			t.failed++
+		}
+	}
+	sort.Strings(order)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tSERVICE\tSUCCEEDED\tSKIPPED\tFAILED\tRESOURCES")
+	for _, key := range order {
+		t := byService[key]
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\n", t.provider, t.service, t.succeeded, t.skipped, t.failed, t.resources)
+	}
+	tw.Flush()
+
+	writeAPIStats(w, report.APICalls)
+
+	var problems []fetcher.ServiceStatus
+	for _, s := range report.Services {
+		if s.Status != fetcher.StatusSucceeded {
+			problems = append(problems, s)
+		}
+	}
+	if len(problems) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%d service(s) were not fully collected:\n", len(problems))
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tSCOPE\tSERVICE\tSTATUS\tERROR")
+	for _, s := range problems {
+		scope := s.Scope
+		if scope == "" {
+			scope = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", s.Provider, scope, s.Service, s.Status, firstLine(s.Error))
+	}
+	tw.Flush()
+}
+
+// writeAPIStats prints the call counters of each API, if any were recorded.
+func writeAPIStats(w io.Writer, stats []fetcher.APIStats) {
+	if len(stats) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "API\tCALLS\tRETRIES\tTHROTTLED\tFAILED\tWAITED")
+	for _, s := range stats {
+		waited := (time.Duration(s.WaitedMS) * time.Millisecond).Round(time.Millisecond)
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%s\n", s.API, s.Calls, s.Retries, s.Throttled, s.Failed, waited)
+	}
+	tw.Flush()
+}
+
+// firstLine trims multi-line API errors so the summary stays one row per service.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + " ..."
+	}
+	return s
+}
@@ -0,0 +1,184 @@
+package syncer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rahulwagh/infrakit/fetcher"
+	"google.golang.org/api/googleapi"
+)
+
+func networkTask(projectID string) Task {
+	f := fetcher.NewFetcher("test-network", "test", fetcher.ScopeProject, []string{"vpc", "subnet", "firewall"},
+		func(ctx context.Context, scope fetcher.Scope) ([]fetcher.StandardizedResource, error) {
+			return nil, nil
+		})
+	return Task{Fetcher: f, Scope: fetcher.Scope{ProjectID: projectID}}
+}
+
+func TestServiceStatuses(t *testing.T) {
+	resources := []fetcher.StandardizedResource{
+		{Provider: "test", Service: "vpc", ID: "vpc-1"},
+		{Provider: "test", Service: "vpc", ID: "vpc-2"},
+		{Provider: "test", Service: "firewall", ID: "fw-1"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		statuses := serviceStatuses(networkTask("p1"), resources, nil)
+		if len(statuses) != 3 {
+			t.Fatalf("Expected a status per service, got %d", len(statuses))
+		}
+		for _, s := range statuses {
+			if s.Status != fetcher.StatusSucceeded || s.Scope != "p1" || s.Level != "project" {
+				t.Errorf("Unexpected status %+v", s)
+			}
+		}
+		if statuses[0].Resources != 2 || statuses[1].Resources != 0 || statuses[2].Resources != 1 {
+			t.Errorf("Unexpected resource counts: %d, %d, %d", statuses[0].Resources, statuses[1].Resources, statuses[2].Resources)
+		}
+	})
+
+	t.Run("partial failure", func(t *testing.T) {
+		err := errors.Join(
+			&fetcher.ServiceError{Service: "subnet", Err: &googleapi.Error{Code: 403}},
+			&fetcher.ServiceError{Service: "firewall", Err: errors.New("backend error")},
+		)
+		statuses := serviceStatuses(networkTask("p1"), resources, err)
+		expected := map[string]fetcher.SyncStatus{
+			"vpc":      fetcher.StatusSucceeded,
+			"subnet":   fetcher.StatusSkipped,
+			"firewall": fetcher.StatusFailed,
+		}
+		for _, s := range statuses {
+			if s.Status != expected[s.Service] {
+				t.Errorf("%s: expected %s, got %s", s.Service, expected[s.Service], s.Status)
+			}
+		}
+	})
+
+	t.Run("whole fetcher failure", func(t *testing.T) {
+		statuses := serviceStatuses(networkTask("p1"), nil, errors.New("boom"))
+		for _, s := range statuses {
+			if s.Status != fetcher.StatusFailed || s.Error != "boom" {
+				t.Errorf("Unexpected status %+v", s)
+			}
+		}
+	})
+}
+
+func TestFailurePolicy(t *testing.T) {
+	report := func(statuses ...fetcher.ServiceStatus) fetcher.SyncReport {
+		return fetcher.SyncReport{Services: statuses}
+	}
+	ok := fetcher.ServiceStatus{Level: "project", Status: fetcher.StatusSucceeded}
+	skippedProject := fetcher.ServiceStatus{Level: "project", Status: fetcher.StatusSkipped}
+	failedProject := fetcher.ServiceStatus{Level: "project", Status: fetcher.StatusFailed}
+	failedProvider := fetcher.ServiceStatus{Level: "provider", Status: fetcher.StatusFailed}
+
+	tests := []struct {
+		policy   FailurePolicy
+		report   fetcher.SyncReport
+		expected bool
+	}{
+		{FailNever, report(failedProvider), false},
+		{FailProvider, report(ok, failedProject), false},
+		{FailProvider, report(ok, failedProvider), true},
+		{FailFailed, report(ok, skippedProject), false},
+		{FailFailed, report(ok, failedProject), true},
+		{FailAny, report(ok, skippedProject), true},
+		{FailAny, report(ok), false},
+		{FailFailed, fetcher.SyncReport{Aborted: "context canceled"}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.policy.Violated(tt.report); got != tt.expected {
+			t.Errorf("%s.Violated(%+v) = %v, expected %v", tt.policy, tt.report, got, tt.expected)
+		}
+	}
+}
+
+func TestParseFailurePolicy(t *testing.T) {
+	for _, valid := range []string{"never", "provider", "failed", "any"} {
+		if _, err := ParseFailurePolicy(valid); err != nil {
+			t.Errorf("ParseFailurePolicy(%q) returned error: %v", valid, err)
+		}
+	}
+	if _, err := ParseFailurePolicy("sometimes"); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	report := fetcher.SyncReport{Services: []fetcher.ServiceStatus{
+		{Provider: "gcp", Scope: "p1", Service: "vpc", Status: fetcher.StatusSucceeded, Resources: 2},
+		{Provider: "gcp", Scope: "p2", Service: "vpc", Status: fetcher.StatusSkipped, Error: "googleapi: Error 403: denied\nmore details"},
+		{Provider: "gcp", Scope: "p1", Service: "cloudrun", Status: fetcher.StatusFailed, Error: "boom"},
+	}}
+
+	var buf bytes.Buffer
+	WriteSummary(&buf, report)
+	out := buf.String()
+
+	for _, want := range []string{"PROVIDER", "cloudrun", "2 service(s) were not fully collected", "googleapi: Error 403: denied ...", "boom"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Summary missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "RETRIES") {
+		t.Errorf("Summary should not print API counters when none were recorded:\n%s", out)
+	}
+	if strings.Contains(out, "more details") {
+		t.Errorf("Summary should only show the first line of an error:\n%s", out)
+	}
+}
+
+func TestEngineRunReportsStatuses(t *testing.T) {
+	partial := fetcher.NewFetcher("test-partial", "test", fetcher.ScopeProject, []string{"vpc", "firewall"},
+		func(ctx context.Context, scope fetcher.Scope) ([]fetcher.StandardizedResource, error) {
+			return []fetcher.StandardizedResource{{Provider: "test", Service: "vpc", ID: "vpc-" + scope.ProjectID}},
+				&fetcher.ServiceError{Service: "firewall", Err: errors.New("boom")}
+		})
+	engine := &Engine{Fetchers: []fetcher.Fetcher{projectLister("p1"), partial}}
+
+	result := engine.Run(context.Background(), []string{"test"}, fetcher.Scope{})
+
+	// The vpc from the partially failed fetcher is kept.
+	foundVPC := false
+	for _, res := range result.Resources {
+		if res.ID == "vpc-p1" {
+			foundVPC = true
+		}
+	}
+	if !foundVPC {
+		t.Error("Partial results of a failed fetcher were dropped")
+	}
+	// project (provider level) + vpc + firewall
+	if len(result.Report.Services) != 3 {
+		t.Fatalf("Expected 3 service statuses, got %d", len(result.Report.Services))
+	}
+	if result.Report.Count(fetcher.StatusFailed) != 1 || result.Report.Count(fetcher.StatusSucceeded) != 2 {
+		t.Errorf("Unexpected report: %+v", result.Report.Services)
+	}
+	if result.Report.StartedAt.IsZero() || result.Report.FinishedAt.IsZero() {
+		t.Error("Report timestamps not set")
+	}
+}
+
+func TestWriteSummaryAPICalls(t *testing.T) {
+	report := fetcher.SyncReport{APICalls: []fetcher.APIStats{
+		{API: "gcp-compute", Calls: 120, Retries: 7, Throttled: 5, WaitedMS: 2500},
+	}}
+
+	var buf bytes.Buffer
+	WriteSummary(&buf, report)
+	out := buf.String()
+
+	for _, want := range []string{"API", "RETRIES", "gcp-compute", "120", "2.5s"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Summary missing %q:\n%s", want, out)
+		}
+	}
+}